@@ -11,13 +11,19 @@ import (
 
 type (
 	Configuration struct {
-		Application Application       `mapstructure:"app"`
-		Frontend    Frontend          `mapstructure:"frontend"`
-		PostgreSQL  PostgreSQL        `mapstructure:"psql"`
-		Google      Google            `mapstructure:"google"`
-		Auth        Auth              `mapstructure:"auth"`
-		Department  map[string]string `mapstructure:"department"`
-		Campus      map[string]string `mapstructure:"campus"`
+		Application  Application       `mapstructure:"app"`
+		Frontend     Frontend          `mapstructure:"frontend"`
+		PostgreSQL   PostgreSQL        `mapstructure:"psql"`
+		Google       Google            `mapstructure:"google"`
+		Auth         Auth              `mapstructure:"auth"`
+		Recurrence   Recurrence        `mapstructure:"recurrence"`
+		Notification Notification      `mapstructure:"notification"`
+		NewJoiner    NewJoiner         `mapstructure:"new_joiner"`
+		Login        Login             `mapstructure:"login"`
+		RateLimit    RateLimit         `mapstructure:"rate_limit"`
+		Idempotency  Idempotency       `mapstructure:"idempotency"`
+		Department   map[string]string `mapstructure:"department"`
+		Campus       map[string]string `mapstructure:"campus"`
 	}
 	Application struct {
 		Name        string        `mapstructure:"name"`
@@ -28,6 +34,9 @@ type (
 		Timeout     time.Duration `mapstructure:"timeout"`
 		LogOption   string        `mapstructure:"log_option"`
 		LogLevel    string        `mapstructure:"log_level"`
+		// TrustedProxies are the CIDR ranges of the proxies in front of the app, only they can set X-Forwarded-For.
+		// Without any, the client IP is the address of the connection.
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	}
 	Frontend struct {
 		Host string `mapstructure:"host"`
@@ -54,6 +63,77 @@ type (
 		RefreshDuration int               `mapstructure:"refresh_duration"`
 		APIKey          string            `mapstructure:"api_key"`
 		ClientId        map[string]bool   `mapstructure:"client_id"`
+		TicketSecret    map[string]string `mapstructure:"ticket_secret"`
+		TicketKeyId     string            `mapstructure:"ticket_key_id"`
+		// PasswordResetDuration is in minutes, PasswordResetURL is the page the reset link opens
+		PasswordResetDuration int    `mapstructure:"password_reset_duration"`
+		PasswordResetURL      string `mapstructure:"password_reset_url"`
+		// SigningAlgorithm is HS256 (default), RS256 or EdDSA. SigningKeys maps key ids to PEM private key files
+		// for the last two, SigningKeyId picks the one that signs when there are several.
+		SigningAlgorithm string            `mapstructure:"signing_algorithm"`
+		SigningKeys      map[string]string `mapstructure:"signing_keys"`
+		SigningKeyId     string            `mapstructure:"signing_key_id"`
+		// AcceptLegacyHS256 keeps accepting access tokens signed with the bearer secret after switching to RS256 or
+		// EdDSA, until LegacyHS256Until (RFC3339), so users are not logged out by the switch
+		AcceptLegacyHS256 bool   `mapstructure:"accept_legacy_hs256"`
+		LegacyHS256Until  string `mapstructure:"legacy_hs256_until"`
+	}
+	Recurrence struct {
+		HorizonDays int           `mapstructure:"horizon_days"`
+		Interval    time.Duration `mapstructure:"interval"`
+	}
+	Notification struct {
+		Interval     time.Duration                 `mapstructure:"interval"`
+		BatchSize    int                           `mapstructure:"batch_size"`
+		MaxAttempts  int                           `mapstructure:"max_attempts"`
+		PhoneChannel string                        `mapstructure:"phone_channel"`
+		Senders      map[string]NotificationSender `mapstructure:"senders"`
+	}
+	NewJoiner struct {
+		// A new joiner still pending after PendingSLA is overdue, and their follow up is reminded once
+		PendingSLA time.Duration `mapstructure:"pending_sla"`
+		Interval   time.Duration `mapstructure:"interval"`
+	}
+	Login struct {
+		// Failed logins within Window count towards a lockout of LockoutDuration, from the third one on
+		// every attempt has to wait twice as long as the previous one, up to MaxDelay
+		MaxFailures      int           `mapstructure:"max_failures"`
+		MaxFailuresPerIP int           `mapstructure:"max_failures_per_ip"`
+		Window           time.Duration `mapstructure:"window"`
+		LockoutDuration  time.Duration `mapstructure:"lockout_duration"`
+		MaxDelay         time.Duration `mapstructure:"max_delay"`
+	}
+	RateLimit struct {
+		// Store is memory (default, per instance) or postgres (shared by every instance)
+		Store    string                     `mapstructure:"store"`
+		MaxKeys  int                        `mapstructure:"max_keys"`
+		Policies map[string]RateLimitPolicy `mapstructure:"policies"`
+	}
+	RateLimitPolicy struct {
+		// Key is ip, client (X-Client-Id) or user (subject of the access token), falling back to ip.
+		// Routes are "METHOD /api/path/:param" or a path for every method; the policy named default covers the rest.
+		Key      string        `mapstructure:"key"`
+		Requests int           `mapstructure:"requests"`
+		Period   time.Duration `mapstructure:"period"`
+		Burst    int           `mapstructure:"burst"`
+		Routes   []string      `mapstructure:"routes"`
+	}
+	Idempotency struct {
+		// Responses are replayed for TTL, a request that has not finished within LockTimeout can be retried
+		TTL         time.Duration `mapstructure:"ttl"`
+		LockTimeout time.Duration `mapstructure:"lock_timeout"`
+	}
+	NotificationSender struct {
+		Driver   string            `mapstructure:"driver"`
+		Host     string            `mapstructure:"host"`
+		Port     int               `mapstructure:"port"`
+		Username string            `mapstructure:"username"`
+		Password string            `mapstructure:"password"`
+		From     string            `mapstructure:"from"`
+		URL      string            `mapstructure:"url"`
+		Headers  map[string]string `mapstructure:"headers"`
+		Timeout  time.Duration     `mapstructure:"timeout"`
+		Path     string            `mapstructure:"path"`
 	}
 )
 
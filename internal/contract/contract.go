@@ -2,6 +2,7 @@ package contract
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-community/internal/config"
 	handler "go-community/internal/deliveries/http"
@@ -9,16 +10,21 @@ import (
 	"go-community/internal/pkg/database/postgre"
 	"go-community/internal/pkg/google"
 	"go-community/internal/pkg/logger"
+	"go-community/internal/pkg/notification"
+	"go-community/internal/pkg/ratelimit"
+	"go-community/internal/pkg/scheduler"
 	"go-community/internal/repositories/pgsql"
 	"go-community/internal/usecases"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
 type Contract struct {
-	echo *echo.Echo
+	echo      *echo.Echo
+	scheduler *scheduler.Scheduler
 }
 
 func New(config *config.Configuration) *Contract {
@@ -56,6 +62,18 @@ func New(config *config.Configuration) *Contract {
 		logger.Logger.Fatal(fmt.Sprintf("[AUTH_ERROR] Failed to setup auth - %v", err), zap.Error(err))
 	}
 
+	// Ticket
+	ticket, err := authorization.NewTicket(config)
+	if err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("[AUTH_ERROR] Failed to setup ticket - %v", err), zap.Error(err))
+	}
+
+	// Notification
+	notifier, err := notification.NewNotifier(config)
+	if err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("[NOTIFICATION_ERROR] Failed to setup notification - %v", err), zap.Error(err))
+	}
+
 	// Register Repository
 	postgreRepository := pgsql.New(psql)
 
@@ -64,21 +82,72 @@ func New(config *config.Configuration) *Contract {
 		Repository:    postgreRepository,
 		Google:        oauthGoogle,
 		Authorization: auth,
+		Ticket:        ticket,
+		Notifier:      notifier,
 		Config:        config,
 	})
 
+	// Rate Limit
+	limiter, err := ratelimit.New(config.RateLimit, postgreRepository.RateLimit)
+	if err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("[RATE_LIMIT_ERROR] Failed to setup rate limit - %v", err), zap.Error(err))
+	}
+
 	// Register Handler
-	handler.New(e, usecase, config, auth)
+	handler.New(e, usecase, config, auth, limiter)
+
+	// Register Background Jobs
+	recurrenceInterval := config.Recurrence.Interval
+	if recurrenceInterval <= 0 {
+		recurrenceInterval = time.Hour
+	}
+
+	notificationInterval := config.Notification.Interval
+	if notificationInterval <= 0 {
+		notificationInterval = time.Minute
+	}
+
+	newJoinerInterval := config.NewJoiner.Interval
+	if newJoinerInterval <= 0 {
+		newJoinerInterval = time.Hour
+	}
+
+	backgroundJobs := []scheduler.Job{
+		{Name: "event-recurrence", Interval: recurrenceInterval, Run: usecase.EventRecurrence.GenerateAll},
+		{Name: "cool-meeting", Interval: recurrenceInterval, Run: usecase.CoolMeeting.GenerateAll},
+		{Name: "cool-new-joiner-sla", Interval: newJoinerInterval, Run: usecase.CoolNewJoiner.RemindOverdue},
+		{Name: "notification-outbox", Interval: notificationInterval, Run: usecase.Notification.SendDue},
+		{Name: "idempotency-cleanup", Interval: time.Hour, Run: usecase.Idempotency.DeleteExpired},
+	}
+
+	if config.RateLimit.Store == ratelimit.StorePostgres {
+		backgroundJobs = append(backgroundJobs, scheduler.Job{Name: "rate-limit-cleanup", Interval: time.Hour, Run: func(ctx context.Context) error {
+			return postgreRepository.RateLimit.DeleteExpired(ctx, time.Now())
+		}})
+	}
+
+	jobs := scheduler.New(backgroundJobs...)
 
 	return &Contract{
-		echo: e,
+		echo:      e,
+		scheduler: jobs,
 	}
 }
 
 func (c *Contract) Start(port int) error {
+	c.scheduler.Start()
+
 	return c.echo.Start(":" + strconv.Itoa(port))
 }
 
+// Stop drains the HTTP connections while waiting for the running jobs, so a job that does not finish in time
+// does not keep the server from shutting down.
 func (c *Contract) Stop(ctx context.Context) error {
-	return c.echo.Shutdown(ctx)
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- c.echo.Shutdown(ctx)
+	}()
+
+	err := c.scheduler.Stop(ctx)
+	return errors.Join(err, <-shutdown)
 }
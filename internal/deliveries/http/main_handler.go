@@ -8,7 +8,9 @@ import (
 	"go-community/internal/deliveries/http/middleware"
 	v1 "go-community/internal/deliveries/http/v1"
 	v2 "go-community/internal/deliveries/http/v2"
+	"go-community/internal/deliveries/http/wellknown"
 	"go-community/internal/pkg/authorization"
+	"go-community/internal/pkg/ratelimit"
 	"go-community/internal/usecases"
 	"net/http"
 
@@ -35,10 +37,11 @@ import (
 // @host localhost:8080
 // @BasePath /api
 // @schemes https
-func New(e *echo.Echo, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
+func New(e *echo.Echo, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth, l *ratelimit.Limiter) {
 	// Middleware for Recover and Logging
 	middleware := middleware.New(e)
 	middleware.Default(c)
+	e.Use(middleware.RateLimiterMiddleware(c, l, a))
 
 	e.GET("/", func(ctx echo.Context) error {
 		message := "Welcome to GROW Community API Service!"
@@ -46,6 +49,7 @@ func New(e *echo.Echo, u *usecases.Usecases, c *config.Configuration, a *authori
 	})
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	wellknown.NewWellKnownHandler(e, a)
 
 	// API Grouping
 	api := e.Group("/api")
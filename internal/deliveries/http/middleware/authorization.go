@@ -6,6 +6,7 @@ import (
 	"go-community/internal/config"
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
 	"go-community/internal/usecases"
 	"time"
 
@@ -20,10 +21,12 @@ type jwtClaim struct {
 	Roles           []string `json:"roles"`
 	Type            string   `json:"typ"`
 	AuthorizedParty string   `json:"azp"`
+	SessionId       string   `json:"sid"`
+	Campuses        []string `json:"campuses"`
 	jwt.RegisteredClaims
 }
 
-func UserMiddleware(config *config.Configuration, usecase *usecases.Usecases, allowedRoles []string) echo.MiddlewareFunc {
+func UserMiddleware(config *config.Configuration, usecase *usecases.Usecases, auth *authorization.Auth) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
 			header := ctx.Request().Header.Get("Authorization")
@@ -32,23 +35,7 @@ func UserMiddleware(config *config.Configuration, usecase *usecases.Usecases, al
 			}
 
 			tokenString := header[len("Bearer "):]
-			token, err := jwt.ParseWithClaims(tokenString, &jwtClaim{}, func(token *jwt.Token) (sec interface{}, err error) {
-				// Extract the Key ID from the token header
-				if kid, ok := token.Header["kid"].(string); ok {
-					keyid, err := base64.RawURLEncoding.DecodeString(kid)
-					if err != nil {
-						return nil, err
-					}
-
-					if key, exists := config.Auth.BearerSecret[string(keyid)]; exists {
-						// This marks success
-						return []byte(key), nil
-					}
-				}
-
-				// This marks error
-				return nil, err
-			})
+			token, err := auth.ParseAccessToken(tokenString, &jwtClaim{})
 
 			if err != nil {
 				if err.Error() == "token has invalid claims: token is expired" {
@@ -74,114 +61,133 @@ func UserMiddleware(config *config.Configuration, usecase *usecases.Usecases, al
 				return response.Error(ctx, models.ErrorInvalidToken)
 			}
 
-			if allowedRoles != nil {
-				for _, userType := range claims.UserTypes {
-					if userType == "superadmin" {
-						ctx.Set("id", claims.Subject)
-						ctx.Set("userTypes", claims.UserTypes)
-						ctx.Set("roles", claims.Roles)
-						return next(ctx)
-					}
-				}
+			setTokenValues(ctx, claims)
 
-				// Check if the user's roles match the required roles
-				for _, allowedRole := range allowedRoles {
-					for _, userRole := range claims.Roles {
-						if userRole == allowedRole {
-							ctx.Set("id", claims.Subject)
-							ctx.Set("userTypes", claims.UserTypes)
-							ctx.Set("roles", claims.Roles)
-							return next(ctx)
-						}
-					}
-				}
+			return next(ctx)
+		}
+	}
+}
 
-				return response.Error(ctx, models.ErrorForbiddenRole)
+// PermissionMiddleware only lets users with the permission through. It has to come after UserMiddleware.
+func PermissionMiddleware(usecase *usecases.Usecases, permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if err := usecase.Permission.Authorize(ctx.Request().Context(), permission, ""); err != nil {
+				return response.Error(ctx, err)
 			}
 
-			ctx.Set("id", claims.Subject)
-			ctx.Set("userTypes", claims.UserTypes)
-			ctx.Set("roles", claims.Roles)
-
 			return next(ctx)
 		}
 	}
 }
 
+// setTokenValues keeps the values of the access token for the handlers, and in the request context for the usecases.
+func setTokenValues(ctx echo.Context, claims *jwtClaim) {
+	ctx.Set("id", claims.Subject)
+	ctx.Set("userTypes", claims.UserTypes)
+	ctx.Set("roles", claims.Roles)
+	ctx.Set("sessionId", claims.SessionId)
+	ctx.Set("campuses", claims.Campuses)
+
+	ctx.SetRequest(ctx.Request().WithContext(models.WithTokenValues(ctx.Request().Context(), models.TokenValues{
+		Id:        claims.Subject,
+		UserTypes: claims.UserTypes,
+		Roles:     claims.Roles,
+		SessionId: claims.SessionId,
+		Campuses:  claims.Campuses,
+	})))
+}
+
 func RefreshMiddleware(config *config.Configuration, usecase *usecases.Usecases) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
-			customRefreshHeader, err := usecase.FeatureFlag.IsFeatureEnabled(ctx.Request().Context(), "event_be_customrefreshheader", "")
+			claims, err := refreshClaims(ctx, config, usecase)
 			if err != nil {
 				return response.Error(ctx, err)
 			}
 
-			var refreshToken string
-			if customRefreshHeader {
-				refreshToken = ctx.Request().Header.Get("X-Refresh-Token")
-
-				if refreshToken == "" {
-					return response.Error(ctx, models.ErrorEmptyToken)
-				}
-			} else {
-				cookie, err := ctx.Cookie("refresh_token")
-				if err != nil {
-					return response.Error(ctx, models.ErrorEmptyToken)
-				}
+			ctx.Set("id", claims.Subject)
+			ctx.Set("sessionId", claims.ID)
+			return next(ctx)
+		}
+	}
+}
 
-				refreshToken = cookie.Value
+// LogoutMiddleware reads the refresh token like RefreshMiddleware, but lets the request through without a valid one
+// so that logging out always clears the cookie.
+func LogoutMiddleware(config *config.Configuration, usecase *usecases.Usecases) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if claims, err := refreshClaims(ctx, config, usecase); err == nil {
+				ctx.Set("id", claims.Subject)
+				ctx.Set("sessionId", claims.ID)
 			}
 
-			if err == nil && refreshToken != "" {
-				token, err := jwt.ParseWithClaims(refreshToken, &jwtClaim{}, func(token *jwt.Token) (sec interface{}, err error) {
-					if kid, ok := token.Header["kid"].(string); ok {
-						keyid, err := base64.RawURLEncoding.DecodeString(kid)
-						if err != nil {
-							return nil, err
-						}
+			return next(ctx)
+		}
+	}
+}
+
+func refreshClaims(ctx echo.Context, config *config.Configuration, usecase *usecases.Usecases) (*jwtClaim, error) {
+	customRefreshHeader, err := usecase.FeatureFlag.IsFeatureEnabled(ctx.Request().Context(), "event_be_customrefreshheader", "")
+	if err != nil {
+		return nil, err
+	}
 
-						if key, exists := config.Auth.RefreshSecret[string(keyid)]; exists {
-							// This marks success
-							return []byte(key), nil
-						}
-					}
+	var refreshToken string
+	if customRefreshHeader {
+		refreshToken = ctx.Request().Header.Get("X-Refresh-Token")
+	} else if cookie, err := ctx.Cookie("refresh_token"); err == nil {
+		refreshToken = cookie.Value
+	}
 
-					// This marks error
-					return nil, err
-				})
+	if refreshToken == "" {
+		return nil, models.ErrorEmptyToken
+	}
 
-				if err != nil {
-					if err.Error() == "token has invalid claims: token is expired" {
-						return response.Error(ctx, models.ErrorExpiredToken)
-					}
-					return response.Error(ctx, models.ErrorInvalidToken)
-				}
+	token, err := jwt.ParseWithClaims(refreshToken, &jwtClaim{}, func(token *jwt.Token) (sec interface{}, err error) {
+		if kid, ok := token.Header["kid"].(string); ok {
+			keyid, err := base64.RawURLEncoding.DecodeString(kid)
+			if err != nil {
+				return nil, err
+			}
 
-				claims, ok := token.Claims.(*jwtClaim)
-				if !ok || !token.Valid {
-					return response.Error(ctx, models.ErrorInvalidToken)
-				}
+			if key, exists := config.Auth.RefreshSecret[string(keyid)]; exists {
+				// This marks success
+				return []byte(key), nil
+			}
+		}
 
-				if claims.ExpiresAt.Time.Before(time.Now()) {
-					return response.Error(ctx, models.ErrorExpiredToken)
-				}
+		// This marks error
+		return nil, err
+	})
 
-				if claims.IssuedAt.Time.After(time.Now()) {
-					return response.Error(ctx, models.ErrorInvalidToken)
-				}
+	if err != nil {
+		if err.Error() == "token has invalid claims: token is expired" {
+			return nil, models.ErrorExpiredToken
+		}
+		return nil, models.ErrorInvalidToken
+	}
 
-				if claims.Type != "refresh" {
-					return response.Error(ctx, models.ErrorInvalidToken)
-				}
+	claims, ok := token.Claims.(*jwtClaim)
+	if !ok || !token.Valid {
+		return nil, models.ErrorInvalidToken
+	}
 
-				ctx.Set("id", claims.Subject)
-				return next(ctx)
-			}
+	if claims.ExpiresAt.Time.Before(time.Now()) {
+		return nil, models.ErrorExpiredToken
+	}
 
-			ctx.Set("id", uuid.New().String())
-			return next(ctx)
-		}
+	if claims.IssuedAt.Time.After(time.Now()) {
+		return nil, models.ErrorInvalidToken
+	}
+
+	// Every refresh token is tracked as a session, one without its id cannot be revoked so it is not accepted
+	if claims.Type != "refresh" || claims.ID == "" {
+		return nil, models.ErrorInvalidToken
 	}
+
+	return claims, nil
 }
 
 func GeneralMiddleware(config *config.Configuration, usecase *usecases.Usecases) echo.MiddlewareFunc {
@@ -202,6 +208,10 @@ func GeneralMiddleware(config *config.Configuration, usecase *usecases.Usecases)
 					return response.Error(ctx, models.ErrorInvalidAPIKey)
 				}
 
+				if requestId := ctx.Request().Header.Get("X-Request-Id"); requestId != "" {
+					ctx.SetRequest(ctx.Request().WithContext(models.WithRequestId(ctx.Request().Context(), requestId)))
+				}
+
 				return next(ctx)
 			}
 
@@ -241,6 +251,7 @@ func GeneralMiddleware(config *config.Configuration, usecase *usecases.Usecases)
 			// Store in context for later use
 			ctx.Set("X-Request-Id", requestId)
 			ctx.Set("X-Timestamp", timestamp)
+			ctx.SetRequest(ctx.Request().WithContext(models.WithRequestId(ctx.Request().Context(), requestId)))
 
 			ctx.Response().Header().Set("X-Request-Id", requestId)
 			ctx.Response().Header().Set("X-Timestamp", timestamp)
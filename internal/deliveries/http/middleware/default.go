@@ -3,12 +3,35 @@ package middleware
 import (
 	"go-community/internal/config"
 	"go-community/internal/pkg/logger"
+	"net"
 
+	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"go.uber.org/zap"
 )
 
 func (m *Middleware) Default(config *config.Configuration) {
+	m.e.IPExtractor = ipExtractor(config)
 	m.e.Use(middleware.Recover())
 	m.e.Use(m.LoggingMiddleware(logger.Logger))
 	m.e.Use(m.corsMiddleware(config))
 }
+
+// ipExtractor decides where RealIP comes from. X-Forwarded-For is only read through the trusted proxies,
+// otherwise any client could pick its own IP to get around the login lockout and the rate limits.
+func ipExtractor(config *config.Configuration) echo.IPExtractor {
+	if len(config.Application.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range config.Application.TrustedProxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			logger.Logger.Fatal("[MIDDLEWARE_ERROR] Invalid trusted proxy", zap.String("proxy", proxy), zap.Error(err))
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
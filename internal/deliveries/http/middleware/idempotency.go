@@ -1,39 +1,109 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/models"
+	"go-community/internal/pkg/logger"
+	"go-community/internal/usecases"
+	"io"
 	"net/http"
-	"sync"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
-var (
-	processedRequests = make(map[string]bool)
-	mu                sync.Mutex
-)
+// IdempotencyMiddleware processes a request sent with an X-Request-ID once per caller and route. Retries with the
+// same body get the stored response again, marked with Idempotent-Replayed; retries while it is still processing
+// and reuses of the id for a different body are rejected. Requests without one are processed as they are.
+// It has to come after UserMiddleware, so the caller is the user of the token.
+func IdempotencyMiddleware(usecase *usecases.Usecases) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if ctx.Request().Method == http.MethodGet {
+				return next(ctx)
+			}
 
-func (m *Middleware) IdempotencyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(ctx echo.Context) error {
-		if ctx.Request().Method == http.MethodGet {
-			return next(ctx)
-		}
+			requestId := strings.TrimSpace(ctx.Request().Header.Get("X-Request-ID"))
+			if requestId == "" {
+				return next(ctx)
+			}
 
-		requestID := ctx.Request().Header.Get("X-Request-ID")
+			body, err := io.ReadAll(ctx.Request().Body)
+			if err != nil {
+				return response.Error(ctx, err)
+			}
+			ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
 
-		if requestID == "" {
-			return response.Error(ctx, models.ErrorEmptyRequestID)
-		}
+			fingerprint := sha256.Sum256(body)
+			key := &models.IdempotencyKey{
+				RequestId:   requestId,
+				Caller:      idempotencyCaller(ctx),
+				Route:       ctx.Request().Method + " " + ctx.Request().URL.Path,
+				Fingerprint: hex.EncodeToString(fingerprint[:]),
+			}
+
+			// The outcome is stored even when the client hung up, its retry is what it is stored for
+			storeCtx := context.WithoutCancel(ctx.Request().Context())
+			stored, err := usecase.Idempotency.Begin(storeCtx, key)
+			if err != nil {
+				return response.Error(ctx, err)
+			}
+
+			if stored != nil {
+				ctx.Response().Header().Set("Idempotent-Replayed", "true")
+				return ctx.Blob(stored.ResponseCode, stored.ContentType, stored.ResponseBody)
+			}
 
-		mu.Lock()
-		if processedRequests[requestID] {
-			mu.Unlock()
-			return response.Error(ctx, models.ErrorProcessedRequestID)
+			recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+			ctx.Response().Writer = recorder
+
+			err = next(ctx)
+			if err != nil || !ctx.Response().Committed || ctx.Response().Status >= http.StatusInternalServerError {
+				if releaseErr := usecase.Idempotency.Release(storeCtx, key); releaseErr != nil {
+					logger.Logger.Error("[IDEMPOTENCY_ERROR] Failed to release request id", zap.String("requestId", requestId), zap.Error(releaseErr))
+				}
+				return err
+			}
+
+			key.ResponseCode = ctx.Response().Status
+			key.ContentType = ctx.Response().Header().Get(echo.HeaderContentType)
+			key.ResponseBody = recorder.body.Bytes()
+			if completeErr := usecase.Idempotency.Complete(storeCtx, key); completeErr != nil {
+				logger.Logger.Error("[IDEMPOTENCY_ERROR] Failed to store response", zap.String("requestId", requestId), zap.Error(completeErr))
+			}
+
+			return nil
 		}
-		processedRequests[requestID] = true
-		mu.Unlock()
+	}
+}
+
+// idempotencyCaller is the user of the token, or the client of the request when it has none.
+func idempotencyCaller(ctx echo.Context) string {
+	if id, ok := ctx.Get("id").(string); ok && id != "" {
+		return "user:" + id
+	}
+
+	return "client:" + strings.TrimSpace(ctx.Request().Header.Get("X-Client-Id"))
+}
+
+// responseRecorder keeps a copy of the response body while it is written.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
 
-		return next(ctx)
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
 	}
 }
@@ -1,40 +1,82 @@
 package middleware
 
 import (
+	"encoding/base64"
+	"fmt"
+	"go-community/internal/common"
+	"go-community/internal/config"
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/models"
-	"net/http"
-	"sync"
+	"go-community/internal/pkg/authorization"
+	"go-community/internal/pkg/logger"
+	"go-community/internal/pkg/ratelimit"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
-	"golang.org/x/time/rate"
+	"go.uber.org/zap"
 )
 
-var (
-	// Create a rate limiter with a limit of 5 requests per minute.
-	limiter = rate.NewLimiter(5, 1) // 5 requests per minute with a burst of 1
-	mtx     sync.Mutex
-)
-
-func (m *Middleware) RateLimiterMiddleware() echo.MiddlewareFunc {
+// RateLimiterMiddleware limits every route under the policy configured for it. Callers are told their limit in the
+// RateLimit-* headers, and how long to wait in Retry-After once they are over it. When the store cannot be reached
+// the request is let through, limits are not worth an outage.
+func (m *Middleware) RateLimiterMiddleware(config *config.Configuration, limiter *ratelimit.Limiter, auth *authorization.Auth) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
-			if ctx.Request().Method != http.MethodPost {
-				// If the method is not POST, continue to the next handler
+			policy := limiter.PolicyFor(ctx.Request().Method, ctx.Path())
+			if policy == nil {
+				return next(ctx)
+			}
+
+			result, err := limiter.Take(ctx.Request().Context(), policy, rateLimitKey(ctx, config, policy, auth))
+			if err != nil {
+				logger.Logger.Error("[RATE_LIMIT_ERROR] Failed to take from the rate limit store", zap.String("policy", policy.Name), zap.Error(err))
 				return next(ctx)
 			}
 
-			// Acquire a token from the rate limiter
-			mtx.Lock()
-			defer mtx.Unlock()
+			header := ctx.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Requests, seconds(policy.Period)))
 
-			if !limiter.Allow() {
-				// Return a custom error response if rate limit is exceeded
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(max(seconds(result.RetryAfter), 1)))
 				return response.Error(ctx, models.ErrorRateLimiterExceeds)
 			}
 
-			// Continue to the next handler if allowed
 			return next(ctx)
 		}
 	}
 }
+
+// rateLimitKey is who the request is counted against under the policy. Requests without a registered X-Client-Id or
+// a valid access token are counted against their IP address instead, as any other value could be changed at will
+// to start over with a new limit.
+func rateLimitKey(ctx echo.Context, config *config.Configuration, policy *ratelimit.Policy, auth *authorization.Auth) string {
+	switch policy.Key {
+	case ratelimit.KeyClient:
+		if decoded, err := base64.StdEncoding.DecodeString(ctx.Request().Header.Get("X-Client-Id")); err == nil {
+			if clientId := common.StringTrimSpaceAndLower(string(decoded)); config.Auth.ClientId[clientId] {
+				return ratelimit.KeyClient + ":" + clientId
+			}
+		}
+	case ratelimit.KeyUser:
+		header := ctx.Request().Header.Get("Authorization")
+		if strings.HasPrefix(header, "Bearer ") {
+			claims := &jwtClaim{}
+			if token, err := auth.ParseAccessToken(header[len("Bearer "):], claims); err == nil && token.Valid && claims.Type == "access" && claims.Subject != "" {
+				return ratelimit.KeyUser + ":" + claims.Subject
+			}
+		}
+	}
+
+	return ratelimit.KeyIP + ":" + ctx.RealIP()
+}
+
+// seconds rounds up, so a caller waiting for it is never early.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -5,6 +5,7 @@ import (
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/deliveries/http/middleware"
 	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
 	"go-community/internal/pkg/validator"
 	"go-community/internal/usecases"
 	"net/http"
@@ -17,12 +18,12 @@ type EventCommunityRequestHandler struct {
 	usecase *usecases.Usecases
 }
 
-func NewEventCommunityRequestHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration) {
+func NewEventCommunityRequestHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
 	handler := &EventCommunityRequestHandler{usecase: u}
 
 	// Define event community request routes
 	endpoint := api.Group("/community-request")
-	endpoint.Use(middleware.UserMiddleware(c, u, nil))
+	endpoint.Use(middleware.UserMiddleware(c, u, a))
 	endpoint.POST("", handler.CreateRequest)
 	endpoint.GET("/:id", handler.GetRequestByID)
 	endpoint.GET("/account/:account_number", handler.GetRequestsByAccountNumber)
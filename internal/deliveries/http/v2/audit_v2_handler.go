@@ -0,0 +1,67 @@
+package v2
+
+import (
+	"go-community/internal/config"
+	"go-community/internal/deliveries/http/common/response"
+	"go-community/internal/deliveries/http/middleware"
+	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
+	"go-community/internal/pkg/validator"
+	"go-community/internal/usecases"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	usecase *usecases.Usecases
+}
+
+func NewAuditHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
+	handler := &AuditHandler{usecase: u}
+
+	endpoint := api.Group("/internal/audit")
+	endpoint.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_AUDIT_VIEW))
+	endpoint.GET("", handler.GetAll)
+}
+
+// GetAll godoc
+// @Summary Get Audit Events
+// @Description Get the administrative changes, newest first. Before and after only hold the fields that changed
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Pagination"
+// @Param direction query string false "pagination direction - prev or next"
+// @Param limit query int false "how many data that user want to load"
+// @Param actorCommunityId query string false "filter by who made the change"
+// @Param action query string false "filter by action, e.g. user.update"
+// @Param entityType query string false "filter by type of the changed entity, e.g. user"
+// @Param entityId query string false "filter by id of the changed entity"
+// @Param requestId query string false "filter by X-Request-Id of the change"
+// @Param from query string false "changed on or after the date, yyyy-mm-dd"
+// @Param to query string false "changed on or before the date, yyyy-mm-dd"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.Pagination{data=[]models.AuditEventResponse,pagination=models.CursorInfo} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 403 {object} models.ErrorResponse "Not allowed to see the audit log"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/audit [get]
+func (ah *AuditHandler) GetAll(ctx echo.Context) error {
+	var param models.GetAllAuditEventCursorParam
+	if err := ctx.Bind(&param); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	data, info, err := ah.usecase.Audit.GetAll(ctx.Request().Context(), param)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessPaginationV2(ctx, http.StatusOK, "", *info, data)
+}
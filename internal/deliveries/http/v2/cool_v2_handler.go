@@ -5,6 +5,7 @@ import (
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/deliveries/http/middleware"
 	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
 	"go-community/internal/pkg/validator"
 	"go-community/internal/usecases"
 	"net/http"
@@ -18,7 +19,7 @@ type CoolHandler struct {
 	conf    *config.Configuration
 }
 
-func NewCoolHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration) {
+func NewCoolHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
 	handler := &CoolHandler{usecase: u, conf: c}
 
 	endpoint := api.Group("/cools")
@@ -29,16 +30,38 @@ func NewCoolHandler(api *echo.Group, u *usecases.Usecases, c *config.Configurati
 	endpointOld.GET("/category", handler.GetAllCategory)
 
 	endpointAuth := endpoint.Group("")
-	endpointAuth.Use(middleware.UserMiddleware(c, u, nil))
+	endpointAuth.Use(middleware.UserMiddleware(c, u, a))
 	endpointAuth.POST("/join", handler.CreateNewJoiner)
+	endpointAuth.GET("/me", handler.GetCoolPersonal)
+	endpointAuth.GET("/:id/meetings", handler.GetAllMeeting)
+	endpointAuth.POST("/:id/meetings", handler.CreateMeeting)
+	endpointAuth.POST("/:id/meetings/generate", handler.GenerateMeeting)
+	endpointAuth.DELETE("/:id/meetings/:meetingId", handler.CancelMeeting)
+	endpointAuth.GET("/:id/meetings/:meetingId/attendance", handler.GetMeetingAttendance)
+	endpointAuth.PUT("/:id/meetings/:meetingId/attendance", handler.MarkMeetingAttendance)
+	endpointAuth.GET("/:id/attendance", handler.GetAttendanceReport)
 	endpoint.GET("", handler.GetAll)
-	endpoint.GET("/me", handler.GetCoolPersonal)
+	endpoint.GET("/search", handler.Search)
 
 	endpointInternalAuth := api.Group("/internal/cools")
-	endpointInternalAuth.Use(middleware.UserMiddleware(c, u, []string{"event-internal-view", "event-internal-edit"}))
+	endpointInternalAuth.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_INTERNAL_ACCESS))
 	endpointInternalAuth.GET("/join", handler.GetAllNewJoiner)
 	endpointInternalAuth.PATCH("/join/:idNewJoiner/:status", handler.UpdateNewJoiner)
+	endpointInternalAuth.GET("/join/:idNewJoiner/suggestions", handler.SuggestNewJoinerCool)
+	endpointInternalAuth.POST("/join/:idNewJoiner/assign", handler.AssignNewJoiner)
+	endpointInternalAuth.GET("/join/:idNewJoiner/notes", handler.GetAllNewJoinerNote)
+	endpointInternalAuth.POST("/join/:idNewJoiner/notes", handler.CreateNewJoinerNote)
+	endpointInternalAuth.POST("/join/:idNewJoiner/convert", handler.ConvertNewJoiner)
 	endpointInternalAuth.POST("", handler.CreateCool)
+	endpointInternalAuth.PUT("/:id", handler.UpdateCool)
+	endpointInternalAuth.PUT("/:id/leadership", handler.UpdateCoolLeadership)
+	endpointInternalAuth.POST("/:id/deactivate", handler.DeactivateCool)
+	endpointInternalAuth.POST("/:id/merge", handler.MergeCool)
+	endpointInternalAuth.POST("/:id/split", handler.SplitCool)
+	endpointInternalAuth.GET("/:id/members", handler.GetAllMember)
+	endpointInternalAuth.POST("/:id/members", handler.AddMember)
+	endpointInternalAuth.POST("/:id/members/:communityId/transfer", handler.TransferMember)
+	endpointInternalAuth.DELETE("/:id/members/:communityId", handler.RemoveMember)
 }
 
 func (clh *CoolHandler) CreateCategory(ctx echo.Context) error {
@@ -137,6 +160,131 @@ func (clh *CoolHandler) UpdateNewJoiner(ctx echo.Context) error {
 	return response.SuccessV2(ctx, http.StatusOK, "", cool)
 }
 
+func (clh *CoolHandler) SuggestNewJoinerCool(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("idNewJoiner"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolNewJoinerParameter{ID: id}
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	suggestions, err := clh.usecase.CoolNewJoiner.Suggest(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(suggestions), suggestions)
+}
+
+func (clh *CoolHandler) AssignNewJoiner(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("idNewJoiner"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolNewJoinerParameter{ID: id}
+
+	var request models.AssignCoolNewJoinerRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	newJoiner, err := clh.usecase.CoolNewJoiner.Assign(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusOK, "", newJoiner)
+}
+
+func (clh *CoolHandler) GetAllNewJoinerNote(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("idNewJoiner"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolNewJoinerParameter{ID: id}
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	notes, err := clh.usecase.CoolNewJoiner.GetNotes(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(notes), notes)
+}
+
+func (clh *CoolHandler) CreateNewJoinerNote(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("idNewJoiner"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolNewJoinerParameter{ID: id}
+
+	var request models.CreateCoolNewJoinerNoteRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	note, err := clh.usecase.CoolNewJoiner.CreateNote(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusCreated, "", note)
+}
+
+func (clh *CoolHandler) ConvertNewJoiner(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("idNewJoiner"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolNewJoinerParameter{ID: id}
+
+	var request models.ConvertCoolNewJoinerRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	user, err := clh.usecase.CoolNewJoiner.Convert(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusCreated, "", user)
+}
+
 func (clh *CoolHandler) CreateCool(ctx echo.Context) error {
 	var request models.CreateCoolRequest
 	if err := ctx.Bind(&request); err != nil {
@@ -155,6 +303,140 @@ func (clh *CoolHandler) CreateCool(ctx echo.Context) error {
 	return response.SuccessV2(ctx, http.StatusCreated, "", new.ToResponse())
 }
 
+func (clh *CoolHandler) UpdateCool(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+
+	var request models.UpdateCoolRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	cool, err := clh.usecase.Cool.Update(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusOK, "", cool)
+}
+
+func (clh *CoolHandler) UpdateCoolLeadership(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+
+	var request models.UpdateCoolLeadershipRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	cool, err := clh.usecase.Cool.UpdateLeadership(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusOK, "", cool)
+}
+
+func (clh *CoolHandler) DeactivateCool(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := clh.usecase.Cool.Deactivate(ctx.Request().Context(), parameter); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (clh *CoolHandler) MergeCool(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+
+	var request models.MergeCoolRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	cool, err := clh.usecase.Cool.Merge(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusOK, "", cool)
+}
+
+func (clh *CoolHandler) SplitCool(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+
+	var request models.SplitCoolRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	cool, err := clh.usecase.Cool.Split(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusCreated, "", cool)
+}
+
 func (clh *CoolHandler) GetAll(ctx echo.Context) error {
 	data, err := clh.usecase.Cool.GetAll(ctx.Request().Context())
 	if err != nil {
@@ -164,6 +446,24 @@ func (clh *CoolHandler) GetAll(ctx echo.Context) error {
 	return response.SuccessListV2(ctx, http.StatusOK, "", data)
 }
 
+func (clh *CoolHandler) Search(ctx echo.Context) error {
+	var param models.SearchCoolCursorParam
+	if err := ctx.Bind(&param); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	data, info, err := clh.usecase.Cool.Search(ctx.Request().Context(), param)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessPaginationV2(ctx, http.StatusOK, "", *info, data)
+}
+
 func (clh *CoolHandler) GetCoolPersonal(ctx echo.Context) error {
 	cool, err := clh.usecase.Cool.GetByCommunityId(ctx.Request().Context(), ctx.Get("id").(string))
 	if err != nil {
@@ -172,3 +472,277 @@ func (clh *CoolHandler) GetCoolPersonal(ctx echo.Context) error {
 
 	return response.SuccessV2(ctx, http.StatusOK, "", cool)
 }
+
+func (clh *CoolHandler) GetAllMember(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	members, err := clh.usecase.CoolMember.GetAll(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(members), members)
+}
+
+func (clh *CoolHandler) AddMember(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+
+	var request models.AddCoolMemberRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	member, err := clh.usecase.CoolMember.Add(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusCreated, "", member)
+}
+
+func (clh *CoolHandler) TransferMember(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberCommunityIdParameter{
+		CoolId:      id,
+		CommunityId: ctx.Param("communityId"),
+	}
+
+	var request models.TransferCoolMemberRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	member, err := clh.usecase.CoolMember.Transfer(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusOK, "", member)
+}
+
+func (clh *CoolHandler) RemoveMember(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberCommunityIdParameter{
+		CoolId:      id,
+		CommunityId: ctx.Param("communityId"),
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := clh.usecase.CoolMember.Remove(ctx.Request().Context(), parameter); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (clh *CoolHandler) GetAllMeeting(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	var parameter models.GetAllCoolMeetingParameter
+	if err := ctx.Bind(&parameter); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+	parameter.CoolId = id
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	meetings, err := clh.usecase.CoolMeeting.GetAll(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(meetings), meetings)
+}
+
+func (clh *CoolHandler) CreateMeeting(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+
+	var request models.CreateCoolMeetingRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	meeting, err := clh.usecase.CoolMeeting.Create(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessV2(ctx, http.StatusCreated, "", meeting)
+}
+
+func (clh *CoolHandler) GenerateMeeting(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	parameter := models.CoolMemberParameter{CoolId: id}
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	meetings, err := clh.usecase.CoolMeeting.Generate(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusCreated, len(meetings), meetings)
+}
+
+func (clh *CoolHandler) CancelMeeting(ctx echo.Context) error {
+	parameter, err := coolMeetingParameter(ctx)
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := clh.usecase.CoolMeeting.Cancel(ctx.Request().Context(), parameter); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (clh *CoolHandler) GetMeetingAttendance(ctx echo.Context) error {
+	parameter, err := coolMeetingParameter(ctx)
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	attendances, err := clh.usecase.CoolMeeting.GetAttendance(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(attendances), attendances)
+}
+
+func (clh *CoolHandler) MarkMeetingAttendance(ctx echo.Context) error {
+	parameter, err := coolMeetingParameter(ctx)
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	var request models.MarkCoolMeetingAttendanceRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	attendances, err := clh.usecase.CoolMeeting.MarkAttendance(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(attendances), attendances)
+}
+
+func (clh *CoolHandler) GetAttendanceReport(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	var parameter models.GetCoolAttendanceParameter
+	if err := ctx.Bind(&parameter); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+	parameter.CoolId = id
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	detail, list, err := clh.usecase.CoolMeeting.GetReport(ctx.Request().Context(), parameter)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessListWithDetail(ctx, http.StatusOK, len(list), detail, list)
+}
+
+func coolMeetingParameter(ctx echo.Context) (models.CoolMeetingParameter, error) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return models.CoolMeetingParameter{}, err
+	}
+
+	meetingId, err := strconv.Atoi(ctx.Param("meetingId"))
+	if err != nil {
+		return models.CoolMeetingParameter{}, err
+	}
+
+	return models.CoolMeetingParameter{CoolId: id, MeetingId: meetingId}, nil
+}
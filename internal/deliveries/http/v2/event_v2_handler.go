@@ -1,6 +1,8 @@
 package v2
 
 import (
+	"errors"
+	"github.com/hashicorp/go-multierror"
 	"github.com/labstack/echo/v4"
 	"go-community/internal/config"
 	"go-community/internal/deliveries/http/common/response"
@@ -23,22 +25,37 @@ func NewEventHandler(api *echo.Group, u *usecases.Usecases, c *config.Configurat
 	endpoint := api.Group("/events")
 
 	endpointUserAuth := endpoint.Group("")
-	endpointUserAuth.Use(middleware.UserMiddleware(c, u, nil))
+	endpointUserAuth.Use(middleware.UserMiddleware(c, u, a))
 	endpointUserAuth.GET("", handler.GetAll)
 	endpointUserAuth.GET("/:code", handler.GetByCode)
-	endpointUserAuth.POST("/registers", handler.Register)
+	endpointUserAuth.GET("/:code/questions", handler.GetQuestions)
+	endpointUserAuth.POST("/registers", handler.Register, middleware.IdempotencyMiddleware(u))
 	endpointUserAuth.GET("/registers", handler.GetAllRegistered)
 	endpointUserAuth.PATCH("/registers/:id/status", handler.UpdateStatus)
+	endpointUserAuth.POST("/registers/status/sync", handler.SyncStatus)
+	endpointUserAuth.POST("/registers/tickets/verify", handler.VerifyTicket)
 	endpointUserAuth.GET("/attendance", handler.GetEventAttendance)
 
 	endpointUserInternal := api.Group("/internal/events")
-	endpointUserInternal.Use(middleware.UserMiddleware(c, u, []string{"event-internal-view", "event-internal-edit"}))
+	endpointUserInternal.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_INTERNAL_ACCESS))
 	endpointUserInternal.POST("", handler.Create)
 	endpointUserInternal.GET("", handler.GetTitles)
 	endpointUserInternal.GET("/:eventCode/summary", handler.GetSummary)
 	endpointUserInternal.GET("/registers", handler.GetAllRegisteredInternal)
 	endpointUserInternal.POST("/instances", handler.CreateInstance)
+	endpointUserInternal.PATCH("/instances/:instanceCode", handler.UpdateInstance)
+	endpointUserInternal.DELETE("/instances/:instanceCode", handler.CancelInstance)
+	endpointUserInternal.PATCH("/:eventCode", handler.Update)
+	endpointUserInternal.DELETE("/:eventCode", handler.Cancel)
 	endpointUserInternal.GET("/registers/download", handler.DownloadInternal)
+	endpointUserInternal.GET("/:eventCode/recurrences", handler.PreviewRecurrence)
+	endpointUserInternal.POST("/:eventCode/recurrences/generate", handler.GenerateRecurrence)
+	endpointUserInternal.PUT("/:eventCode/recurrences/exceptions", handler.UpsertRecurrenceException)
+	endpointUserInternal.DELETE("/:eventCode/recurrences/exceptions", handler.DeleteRecurrenceException)
+	endpointUserInternal.POST("/questions", handler.CreateQuestions)
+	endpointUserInternal.GET("/:eventCode/questions", handler.GetQuestionsInternal)
+	endpointUserInternal.PATCH("/questions/:id", handler.UpdateQuestion)
+	endpointUserInternal.DELETE("/questions/:id", handler.DeleteQuestion)
 }
 
 // Create godoc
@@ -131,9 +148,11 @@ func (eh *EventHandler) GetByCode(ctx echo.Context) error {
 // @Produce json
 // @Param user body models.CreateEventRegistrationRecordRequest true "User object that needs to be added"
 // @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Param X-Request-ID header string false "retries with the same id and body get the response of the first request again"
 // @Security BearerAuth
 // @Success 201 {object} models.CreateEventRegistrationRecordResponse{registrants=models.CreateOtherEventRegistrationRecordRequest} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 409 {object} models.ErrorResponse "The request with this request id is still being processed"
 // @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
 // @Router /v2/events/registers [post]
 func (eh *EventHandler) Register(ctx echo.Context) error {
@@ -153,6 +172,12 @@ func (eh *EventHandler) Register(ctx echo.Context) error {
 
 	register, err := eh.usecase.EventRegistrationRecord.Create(ctx.Request().Context(), &request, &tokenValue)
 	if err != nil {
+		// Answers are checked field by field, so every problem of the form is returned at once
+		var errs *multierror.Error
+		if errors.As(err, &errs) {
+			return response.ErrorValidation(ctx, errs)
+		}
+
 		return response.Error(ctx, err)
 	}
 
@@ -190,7 +215,7 @@ func (eh *EventHandler) GetAllRegistered(ctx echo.Context) error {
 
 // UpdateStatus godoc
 // @Summary Update Registration Status
-// @Description Update user registration id to success or failed
+// @Description Update user registration id to success, checked-out or cancelled
 // @Tags events
 // @Accept json
 // @Produce json
@@ -232,6 +257,77 @@ func (eh *EventHandler) UpdateStatus(ctx echo.Context) error {
 	return response.Success(ctx, http.StatusOK, record.ToResponse())
 }
 
+// SyncStatus godoc
+// @Summary Sync Offline Registration Scans
+// @Description Apply a batch of scans queued by a scanner while offline and return the result of each scan
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param scans body models.SyncRegistrationStatusRequest true "Scans queued by the scanner"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.List{data=[]models.SyncRegistrationStatusResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/events/registers/status/sync [post]
+func (eh *EventHandler) SyncStatus(ctx echo.Context) error {
+	var request models.SyncRegistrationStatusRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	res, err := eh.usecase.EventRegistrationRecord.SyncStatus(ctx.Request().Context(), &request, &tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(res), res)
+}
+
+// VerifyTicket godoc
+// @Summary Verify Registration Ticket
+// @Description Verify the signature and validity window of a registration QR ticket without looking up the registration
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param ticket body models.VerifyRegistrationTicketRequest true "Ticket scanned from the QR"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.VerifyRegistrationTicketResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Ticket is forged or expired"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/events/registers/tickets/verify [post]
+func (eh *EventHandler) VerifyTicket(ctx echo.Context) error {
+	var request models.VerifyRegistrationTicketRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	ticket, err := eh.usecase.EventRegistrationRecord.VerifyTicket(ctx.Request().Context(), &request, &tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, ticket)
+}
+
 // GetTitles godoc
 // @Summary Get Events Titles
 // @Description For Internal Purposes Only
@@ -378,3 +474,448 @@ func (eh *EventHandler) DownloadInternal(ctx echo.Context) error {
 
 	return response.SuccessDownload(ctx, http.StatusOK, contentType, fileName, data)
 }
+
+// PreviewRecurrence godoc
+// @Summary Preview Event Recurrence
+// @Description List the occurrences of a recurring event between two times, with the instance each has or would be generated as
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param from query string false "RFC3339 start of the preview, defaults to now"
+// @Param until query string false "RFC3339 end of the preview, defaults to the recurrence horizon"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.List{data=[]models.GetRecurrencePreviewResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode}/recurrences [get]
+func (eh *EventHandler) PreviewRecurrence(ctx echo.Context) error {
+	var request models.GetRecurrencePreviewParameter
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+	request.EventCode = ctx.Param("eventCode")
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := eh.usecase.EventRecurrence.Preview(ctx.Request().Context(), request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(res), res)
+}
+
+// GenerateRecurrence godoc
+// @Summary Generate Event Recurrence
+// @Description Generate the instances of a recurring event until the recurrence horizon, without waiting for the scheduled job
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 201 {object} models.List{data=[]models.CreateInstanceResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode}/recurrences/generate [post]
+func (eh *EventHandler) GenerateRecurrence(ctx echo.Context) error {
+	request := models.GenerateRecurrenceParameter{
+		EventCode: ctx.Param("eventCode"),
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := eh.usecase.EventRecurrence.Generate(ctx.Request().Context(), request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusCreated, len(res), res)
+}
+
+// UpsertRecurrenceException godoc
+// @Summary Skip or Override an Occurrence
+// @Description Skip an occurrence of a recurring event, or override its time, title, location or seats, before it is generated
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param exception body models.UpsertRecurrenceExceptionRequest true "Occurrence to skip or override"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.RecurrenceExceptionResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode}/recurrences/exceptions [put]
+func (eh *EventHandler) UpsertRecurrenceException(ctx echo.Context) error {
+	var request models.UpsertRecurrenceExceptionRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+	request.EventCode = ctx.Param("eventCode")
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := eh.usecase.EventRecurrence.UpsertException(ctx.Request().Context(), request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, res)
+}
+
+// DeleteRecurrenceException godoc
+// @Summary Remove an Occurrence Exception
+// @Description Remove the skip or override of an occurrence, so it is generated from the template again
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param occurrenceAt query string true "RFC3339 time of the occurrence"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode}/recurrences/exceptions [delete]
+func (eh *EventHandler) DeleteRecurrenceException(ctx echo.Context) error {
+	var request models.DeleteRecurrenceExceptionParameter
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+	request.EventCode = ctx.Param("eventCode")
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := eh.usecase.EventRecurrence.DeleteException(ctx.Request().Context(), request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Update godoc
+// @Summary Update Event
+// @Description Update the fields sent of an event, the time windows are validated the same way as when it is created
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param event body models.UpdateEventRequest true "Fields of the event to update"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.UpdateEventResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode} [patch]
+func (eh *EventHandler) Update(ctx echo.Context) error {
+	var request models.UpdateEventRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+	request.Code = ctx.Param("eventCode")
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	event, err := eh.usecase.Event.Update(ctx.Request().Context(), request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, event)
+}
+
+// Cancel godoc
+// @Summary Cancel Event
+// @Description Cancel an event with all of its sessions. Every registration, verified ones included, is cancelled and returned with its previous status, so they can be informed
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param reason query string false "reason saved on the cancelled registrations"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.CancelEventResponse{instances=[]models.CancelInstanceResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode} [delete]
+func (eh *EventHandler) Cancel(ctx echo.Context) error {
+	var param models.CancelEventParameter
+	if err := ctx.Bind(&param); err != nil {
+		return response.Error(ctx, err)
+	}
+	param.Code = ctx.Param("eventCode")
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	event, err := eh.usecase.Event.Cancel(ctx.Request().Context(), param, &tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, event)
+}
+
+// UpdateInstance godoc
+// @Summary Update Instance
+// @Description Update the fields sent of an instance. Total seats cannot go below the booked seats, and waitlisted registrations that now fit are promoted
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param instanceCode path string true "instance code"
+// @Param instance body models.UpdateInstanceRequest true "Fields of the instance to update"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.UpdateInstanceResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/instances/{instanceCode} [patch]
+func (eh *EventHandler) UpdateInstance(ctx echo.Context) error {
+	var request models.UpdateInstanceRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+	request.Code = ctx.Param("instanceCode")
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	instance, err := eh.usecase.EventInstance.Update(ctx.Request().Context(), request, &tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, instance)
+}
+
+// CancelInstance godoc
+// @Summary Cancel Instance
+// @Description Cancel a session. Every registration, verified ones included, is cancelled and returned with its previous status, so they can be informed
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param instanceCode path string true "instance code"
+// @Param reason query string false "reason saved on the cancelled registrations"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.CancelInstanceResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/instances/{instanceCode} [delete]
+func (eh *EventHandler) CancelInstance(ctx echo.Context) error {
+	var param models.CancelInstanceParameter
+	if err := ctx.Bind(&param); err != nil {
+		return response.Error(ctx, err)
+	}
+	param.Code = ctx.Param("instanceCode")
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	instance, err := eh.usecase.EventInstance.Cancel(ctx.Request().Context(), param, &tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, instance)
+}
+
+// GetQuestions godoc
+// @Summary Get Registration Questions
+// @Description Get the active questions of the registration form in display order. Use registrant to only get the questions asked to the people registered alongside the main registrant
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param code path string true "event code"
+// @Param instanceCode query string false "instance code, to only get the questions asked for that session"
+// @Param registrant query bool false "only the questions visible to registrants"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.List{data=[]models.CreateQuestionResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/events/{code}/questions [get]
+func (eh *EventHandler) GetQuestions(ctx echo.Context) error {
+	var param models.GetQuestionsParameter
+	if err := ctx.Bind(&param); err != nil {
+		return response.Error(ctx, err)
+	}
+	param.EventCode = ctx.Param("code")
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := eh.usecase.EventQuestion.GetAll(ctx.Request().Context(), param)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(res), res)
+}
+
+// CreateQuestions godoc
+// @Summary Create Registration Questions
+// @Description Create the questions of the registration form of an event, or only of some of its sessions
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param question body models.CreateQuestionRequest true "Questions to add to the registration form"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 201 {object} models.List{data=[]models.CreateQuestionResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/questions [post]
+func (eh *EventHandler) CreateQuestions(ctx echo.Context) error {
+	var request models.CreateQuestionRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := eh.usecase.EventQuestion.Create(ctx.Request().Context(), request)
+	if err != nil {
+		var errs *multierror.Error
+		if errors.As(err, &errs) {
+			return response.ErrorValidation(ctx, errs)
+		}
+
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusCreated, len(res), res)
+}
+
+// GetQuestionsInternal godoc
+// @Summary Get Registration Questions Internal
+// @Description Get every question of the registration form in display order, including the inactive ones
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param eventCode path string true "event code"
+// @Param instanceCode query string false "instance code, to only get the questions asked for that session"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.List{data=[]models.CreateQuestionResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/{eventCode}/questions [get]
+func (eh *EventHandler) GetQuestionsInternal(ctx echo.Context) error {
+	var param models.GetQuestionsParameter
+	if err := ctx.Bind(&param); err != nil {
+		return response.Error(ctx, err)
+	}
+	param.EventCode = ctx.Param("eventCode")
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := eh.usecase.EventQuestion.GetAllInternal(ctx.Request().Context(), param)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(res), res)
+}
+
+// UpdateQuestion godoc
+// @Summary Update Registration Question
+// @Description Update the fields sent of a question. Answers already given are kept as they are
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "question id"
+// @Param question body models.UpdateQuestionRequest true "Fields of the question to update"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 200 {object} models.CreateQuestionResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/questions/{id} [patch]
+func (eh *EventHandler) UpdateQuestion(ctx echo.Context) error {
+	var request models.UpdateQuestionRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, err)
+	}
+	request.ID = ctx.Param("id")
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	question, err := eh.usecase.EventQuestion.Update(ctx.Request().Context(), request)
+	if err != nil {
+		var errs *multierror.Error
+		if errors.As(err, &errs) {
+			return response.ErrorValidation(ctx, errs)
+		}
+
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, question)
+}
+
+// DeleteQuestion godoc
+// @Summary Delete Registration Question
+// @Description Remove a question from the registration form. Answers already given to it are kept
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "question id"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/events/questions/{id} [delete]
+func (eh *EventHandler) DeleteQuestion(ctx echo.Context) error {
+	var param models.DeleteQuestionParameter
+	param.ID = ctx.Param("id")
+
+	if err := validator.Validate(param); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := eh.usecase.EventQuestion.Delete(ctx.Request().Context(), param); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
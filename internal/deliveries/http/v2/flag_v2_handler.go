@@ -6,6 +6,7 @@ import (
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/deliveries/http/middleware"
 	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
 	"go-community/internal/pkg/validator"
 	"go-community/internal/usecases"
 	"net/http"
@@ -17,11 +18,11 @@ type FlagHandler struct {
 	conf    config.Configuration
 }
 
-func NewFlagHandler(api *echo.Group, u *usecases.Usecases, c config.Configuration) {
+func NewFlagHandler(api *echo.Group, u *usecases.Usecases, c config.Configuration, a *authorization.Auth) {
 	handler := &FlagHandler{usecase: u, conf: c}
 
 	endpoint := api.Group("/flags")
-	endpoint.Use(middleware.UserMiddleware(&c, u, []string{"event-internal-view", "event-internal-edit"}))
+	endpoint.Use(middleware.UserMiddleware(&c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_INTERNAL_ACCESS))
 	endpoint.GET("", handler.GetAll)
 	endpoint.GET("/:key", handler.GetByKey)
 	endpoint.POST("", handler.Create)
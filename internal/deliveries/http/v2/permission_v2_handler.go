@@ -0,0 +1,135 @@
+package v2
+
+import (
+	"go-community/internal/config"
+	"go-community/internal/deliveries/http/common/response"
+	"go-community/internal/deliveries/http/middleware"
+	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
+	"go-community/internal/pkg/validator"
+	"go-community/internal/usecases"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PermissionHandler struct {
+	usecase *usecases.Usecases
+}
+
+func NewPermissionHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
+	handler := &PermissionHandler{usecase: u}
+
+	endpoint := api.Group("/internal/permissions")
+	endpoint.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_PERMISSION_MANAGE))
+	endpoint.GET("", handler.GetAll)
+	endpoint.PUT("/roles/:role", handler.UpdateRolePermissions)
+	endpoint.PUT("/user-types/:userType", handler.UpdateUserTypePermissions)
+}
+
+// GetAll godoc
+// @Summary Get All Permissions
+// @Description Get every permission that can be granted to roles and user types
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.List{data=[]models.PermissionResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 403 {object} models.ErrorResponse "Not allowed to manage permissions"
+// @Router /v2/internal/permissions [get]
+func (ph *PermissionHandler) GetAll(ctx echo.Context) error {
+	data, err := ph.usecase.Permission.GetAll(ctx.Request().Context())
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	res := make([]models.PermissionResponse, len(data))
+	for i, v := range data {
+		res[i] = v.ToResponse()
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(res), res)
+}
+
+// UpdateRolePermissions godoc
+// @Summary Update Role Permissions
+// @Description Replace the permissions granted to a role. Grants are registered permissions, resource:* for every action of a resource or * for everything
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role path string true "Role"
+// @Param permissions body models.UpdatePermissionsRequest true "Permissions granted to the role"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.GrantedPermissionsResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 404 {object} models.ErrorResponse "Role not found"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/permissions/roles/{role} [put]
+func (ph *PermissionHandler) UpdateRolePermissions(ctx echo.Context) error {
+	parameter := models.UpdateRolePermissionsParameter{
+		Role: ctx.Param("role"),
+	}
+
+	var request models.UpdatePermissionsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := ph.usecase.Permission.UpdateRolePermissions(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, res)
+}
+
+// UpdateUserTypePermissions godoc
+// @Summary Update User Type Permissions
+// @Description Replace the permissions granted to a user type. Grants are registered permissions, resource:* for every action of a resource or * for everything
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userType path string true "User type"
+// @Param permissions body models.UpdatePermissionsRequest true "Permissions granted to the user type"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.GrantedPermissionsResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 404 {object} models.ErrorResponse "User type not found"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/permissions/user-types/{userType} [put]
+func (ph *PermissionHandler) UpdateUserTypePermissions(ctx echo.Context) error {
+	parameter := models.UpdateUserTypePermissionsParameter{
+		UserType: ctx.Param("userType"),
+	}
+
+	var request models.UpdatePermissionsRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	res, err := ph.usecase.Permission.UpdateUserTypePermissions(ctx.Request().Context(), parameter, request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, res)
+}
@@ -5,6 +5,7 @@ import (
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/deliveries/http/middleware"
 	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
 	"go-community/internal/pkg/validator"
 	"go-community/internal/usecases"
 	"net/http"
@@ -17,12 +18,12 @@ type RoleHandler struct {
 	config  *config.Configuration
 }
 
-func NewRoleHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration) {
+func NewRoleHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
 	handler := &RoleHandler{usecase: u, config: c}
 
 	// Define campus routes
 	endpoint := api.Group("/roles")
-	endpoint.Use(middleware.UserMiddleware(c, u, []string{"event-internal-view", "event-internal-edit"}))
+	endpoint.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_INTERNAL_ACCESS))
 	endpoint.POST("", handler.Create)
 	endpoint.GET("", handler.GetAllRoles)
 }
@@ -1,9 +1,7 @@
 package v2
 
 import (
-	"go-community/internal/common"
 	"go-community/internal/config"
-	"go-community/internal/constants"
 	"go-community/internal/deliveries/http/common/response"
 	"go-community/internal/deliveries/http/middleware"
 	"go-community/internal/models"
@@ -42,31 +40,7 @@ func NewTokenHandler(api *echo.Group, a *authorization.Auth, c *config.Configura
 // @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
 // @Router /v2/tokens [get]
 func (th *TokenHandler) Refresh(ctx echo.Context) error {
-	id := ctx.Get("id").(string)
-	if common.IsValidUUID(id) {
-		tokens, err := th.auth.GenerateTokens(id, constants.TYPE_GUEST, constants.ROLE_GUEST)
-		if err != nil {
-			response.Error(ctx, err)
-		}
-
-		return response.SuccessList(ctx, http.StatusCreated, 2, tokens.ToGenerateTokens())
-	} else {
-		user, err := th.usecase.User.GetRBAC(ctx.Request().Context(), id)
-		if err != nil {
-			return response.Error(ctx, err)
-		}
-
-		if user == nil {
-			return response.Error(ctx, models.ErrorUserNotFound)
-		}
-
-		tokens, err := th.auth.GenerateTokens(user.CommunityId, user.UserTypes, user.Roles)
-		if err != nil {
-			response.Error(ctx, err)
-		}
-
-		return response.SuccessList(ctx, http.StatusCreated, 2, tokens.ToGenerateTokens())
-	}
+	return th.refreshSession(ctx)
 }
 
 // RefreshPost godoc
@@ -82,29 +56,41 @@ func (th *TokenHandler) Refresh(ctx echo.Context) error {
 // @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
 // @Router /v2/tokens [post]
 func (th *TokenHandler) RefreshPost(ctx echo.Context) error {
-	header := ctx.Request().Header.Get("X-Refresh-Token")
-	if header == "" {
-		tokens, err := th.auth.GenerateTokens(ctx.Get("id").(string), constants.TYPE_GUEST, constants.ROLE_GUEST)
-		if err != nil {
-			response.Error(ctx, err)
-		}
-
-		return response.SuccessList(ctx, http.StatusCreated, 2, tokens.ToGenerateTokens())
-	} else {
-		user, err := th.usecase.User.GetRBAC(ctx.Request().Context(), ctx.Get("id").(string))
-		if err != nil {
-			return response.Error(ctx, err)
-		}
+	return th.refreshSession(ctx)
+}
 
-		if user == nil {
-			return response.Error(ctx, models.ErrorUserNotFound)
-		}
+// refreshSession rotates the session of a user refresh token. The new refresh token replaces the cookie as well,
+// since the old one cannot be used again.
+func (th *TokenHandler) refreshSession(ctx echo.Context) error {
+	sessionId, _ := ctx.Get("sessionId").(string)
+	tokens, err := th.usecase.UserSession.Refresh(ctx.Request().Context(), models.RefreshSessionRequest{
+		SessionId:   sessionId,
+		CommunityId: ctx.Get("id").(string),
+		Client:      sessionClient(ctx),
+	})
+	if err != nil {
+		return response.Error(ctx, err)
+	}
 
-		tokens, err := th.auth.GenerateTokens(user.CommunityId, user.UserTypes, user.Roles)
-		if err != nil {
-			response.Error(ctx, err)
-		}
+	setRefreshCookie(ctx, tokens)
+	return response.SuccessList(ctx, http.StatusCreated, 2, tokens.ToGenerateTokens())
+}
 
-		return response.SuccessList(ctx, http.StatusCreated, 2, tokens.ToGenerateTokens())
+func sessionClient(ctx echo.Context) models.SessionClient {
+	return models.SessionClient{
+		UserAgent: ctx.Request().UserAgent(),
+		IpAddress: ctx.RealIP(),
 	}
 }
+
+func setRefreshCookie(ctx echo.Context, tokens *models.UserToken) {
+	ctx.SetCookie(&http.Cookie{
+		Name:     "refresh_token",
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshExpiry,
+		HttpOnly: true,                    // Prevent client-side JavaScript access
+		Secure:   true,                    // Only send over HTTPS
+		SameSite: http.SameSiteStrictMode, // Prevent CSRF
+		Path:     "/",
+	})
+}
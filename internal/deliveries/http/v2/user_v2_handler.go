@@ -21,7 +21,7 @@ type UserHandler struct {
 	conf    *config.Configuration
 }
 
-func NewUserHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration) {
+func NewUserHandler(api *echo.Group, u *usecases.Usecases, c *config.Configuration, a *authorization.Auth) {
 	handler := &UserHandler{usecase: u, conf: c}
 
 	endpoint := api.Group("/users")
@@ -30,26 +30,35 @@ func NewUserHandler(api *echo.Group, u *usecases.Usecases, c *config.Configurati
 	endpoint.POST("/login", handler.Login)
 	endpoint.GET("/check/:identifier", handler.Check)
 	endpoint.GET("/:communityId", handler.GetByCommunityId)
-	endpoint.PATCH("/:identifier/password", handler.UpdatePassword)
-	endpoint.PUT("/logout", handler.Logout)
-	endpoint.PUT("/roles-types/update", handler.UpdateRolesOrUserType)
+	endpoint.POST("/password/forgot", handler.ForgotPassword)
+	endpoint.POST("/password/reset", handler.ResetPassword)
+	endpoint.PUT("/logout", handler.Logout, middleware.LogoutMiddleware(c, u))
 
 	endpointUserAuth := endpoint.Group("")
-	endpointUserAuth.Use(middleware.UserMiddleware(c, u, nil))
+	endpointUserAuth.Use(middleware.UserMiddleware(c, u, a))
 	endpointUserAuth.GET("/access-token", handler.GetByAccessToken)
+	endpointUserAuth.PUT("/logout/all", handler.LogoutAll)
 	endpointUserAuth.PATCH("/:communityId/profile", handler.UpdateProfile)
+	endpointUserAuth.PATCH("/:communityId/password", handler.ChangePassword)
+	endpointUserAuth.GET("/:communityId/sessions", handler.GetSessions)
+	endpointUserAuth.DELETE("/:communityId/sessions/:sessionId", handler.DeleteSession)
 	endpointUserAuth.GET("/:communityId/profile", handler.GetProfile)
 	endpointUserAuth.GET("/community-ids", handler.GetCommunityIdsByParams)
 
+	userManageEndpoint := endpoint.Group("")
+	userManageEndpoint.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_USER_MANAGE))
+	userManageEndpoint.PUT("/roles-types/update", handler.UpdateRolesOrUserType)
+
 	userTypeEndpoint := endpoint.Group("/types")
 	userTypeEndpoint.POST("", handler.CreateUserType)
 	userTypeEndpoint.GET("", handler.GetAllUserTypes)
 
 	userInternalEndpoint := api.Group("/internal/users")
-	userInternalEndpoint.Use(middleware.UserMiddleware(c, u, []string{"event-internal-view", "event-internal-edit"}))
+	userInternalEndpoint.Use(middleware.UserMiddleware(c, u, a), middleware.PermissionMiddleware(u, models.PERMISSION_INTERNAL_ACCESS))
 	userInternalEndpoint.GET("", handler.GetAllUserInternal)
 	userInternalEndpoint.PATCH("/:communityId/update", handler.UpdateUser)
 	userInternalEndpoint.DELETE("/:communityId", handler.Delete)
+	userInternalEndpoint.POST("/login/unlock", handler.UnlockLogin, middleware.PermissionMiddleware(u, models.PERMISSION_USER_MANAGE))
 }
 
 // Create godoc
@@ -104,20 +113,13 @@ func (uh *UserHandler) Login(ctx echo.Context) error {
 		return response.ErrorValidation(ctx, err)
 	}
 
+	request.Client = sessionClient(ctx)
 	user, tokens, err := uh.usecase.User.Login(ctx.Request().Context(), &request)
 	if err != nil {
 		return response.Error(ctx, err)
 	}
 
-	ctx.SetCookie(&http.Cookie{
-		Name:     "refresh_token",
-		Value:    tokens.RefreshToken,
-		Expires:  tokens.RefreshExpiry,
-		HttpOnly: true,                    // Prevent client-side JavaScript access
-		Secure:   true,                    // Only send over HTTPS
-		SameSite: http.SameSiteStrictMode, // Prevent CSRF
-		Path:     "/",
-	})
+	setRefreshCookie(ctx, tokens)
 
 	res := models.LoginUserResponse{Type: models.TYPE_USER, CommunityId: user.CommunityID, Name: user.Name, PhoneNumber: user.PhoneNumber, Email: user.Email, CampusCode: user.CampusCode, PlaceOfBirth: user.PlaceOfBirth, DateOfBirth: user.DateOfBirth, Address: user.Address, Gender: user.Gender, DepartmentCode: user.Department, CoolID: user.CoolID, KKJNumber: user.KKJNumber, JemaatId: user.JemaatID, IsKOM100: user.IsKom100, IsBaptized: user.IsBaptized, MaritalStatus: user.MaritalStatus, Status: user.Status, Token: tokens.ToGenerateTokens(), UserTypes: user.UserTypes, Roles: user.Roles}
 	return response.Success(ctx, http.StatusCreated, res.ToLogin())
@@ -203,38 +205,98 @@ func (uh *UserHandler) GetAllUserTypes(ctx echo.Context) error {
 	return response.SuccessList(ctx, http.StatusOK, len(res), res)
 }
 
-// UpdatePassword godoc
-// @Summary Update User Password
-// @Description Update user Password
+// ForgotPassword godoc
+// @Summary Forgot Password
+// @Description Send a single-use token to reset the password to the email or phone number of the user
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param identifier path string true "object that needs to be added"
-// @Param user body models.UpdateUserPasswordRequest true "User object that needs to be added"
+// @Param user body models.ForgotPasswordRequest true "Email or phone number of the user"
 // @Param X-API-Key header string true "mandatory header to access endpoint"
-// @Success 201 {object} models.UpdateUserPasswordResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Success 202 "Response indicates that the request has been accepted. It is the same whether the user exists or not"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
 // @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
-// @Router /v2/users/{identifier}/password [patch]
-func (uh *UserHandler) UpdatePassword(ctx echo.Context) error {
-	var request models.UpdateUserPasswordRequest
-	parameter := models.UpdateUserPasswordParam{
-		Identifier: strings.ToLower(ctx.Param("identifier")),
+// @Router /v2/users/password/forgot [post]
+func (uh *UserHandler) ForgotPassword(ctx echo.Context) error {
+	var request models.ForgotPasswordRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
 	}
 
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	if err := uh.usecase.User.ForgotPassword(ctx.Request().Context(), request); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary Reset Password
+// @Description Set a new password with the token sent by forgot password
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body models.ResetPasswordRequest true "Reset token and the new password"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.UpdateUserPasswordResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/users/password/reset [post]
+func (uh *UserHandler) ResetPassword(ctx echo.Context) error {
+	var request models.ResetPasswordRequest
 	if err := ctx.Bind(&request); err != nil {
 		return response.Error(ctx, models.ErrorInvalidInput)
 	}
 
-	if err := validator.Validate(parameter); err != nil {
+	if err := validator.Validate(request); err != nil {
 		return response.ErrorValidation(ctx, err)
 	}
 
+	user, err := uh.usecase.User.ResetPassword(ctx.Request().Context(), request)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, user.ToUpdatePassword())
+}
+
+// ChangePassword godoc
+// @Summary Change Password
+// @Description Change the password of the logged in user, which requires the current password
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param communityId path string true "Community ID of the logged in user"
+// @Param user body models.ChangePasswordRequest true "Current and new password"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.UpdateUserPasswordResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Current password is wrong"
+// @Failure 403 {object} models.ErrorResponse "Different account from the logged in user"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/users/{communityId}/password [patch]
+func (uh *UserHandler) ChangePassword(ctx echo.Context) error {
+	var request models.ChangePasswordRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	request.CommunityId = ctx.Param("communityId")
 	if err := validator.Validate(request); err != nil {
 		return response.ErrorValidation(ctx, err)
 	}
 
-	user, err := uh.usecase.User.UpdatePassword(ctx.Request().Context(), &parameter, &request)
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	user, err := uh.usecase.User.ChangePassword(ctx.Request().Context(), request, tokenValue)
 	if err != nil {
 		return response.Error(ctx, err)
 	}
@@ -303,7 +365,7 @@ func (uh *UserHandler) GetByAccessToken(ctx echo.Context) error {
 
 // Logout godoc
 // @Summary Logout User
-// @Description Logout user for all kinds of user
+// @Description Logout user for all kinds of user. The session of the refresh token, if any, is revoked
 // @Tags users
 // @Accept json
 // @Produce json
@@ -313,6 +375,12 @@ func (uh *UserHandler) GetByAccessToken(ctx echo.Context) error {
 // @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
 // @Router /v2/users/logout [put]
 func (uh *UserHandler) Logout(ctx echo.Context) error {
+	if sessionId, ok := ctx.Get("sessionId").(string); ok && sessionId != "" {
+		if err := uh.usecase.UserSession.Logout(ctx.Request().Context(), sessionId); err != nil {
+			return response.Error(ctx, err)
+		}
+	}
+
 	ctx.SetCookie(&http.Cookie{
 		Name:     "refresh_token",                // Name of the cookie holding the refresh token
 		Value:    "",                             // Set value to empty
@@ -320,11 +388,119 @@ func (uh *UserHandler) Logout(ctx echo.Context) error {
 		HttpOnly: true,                           // Prevent client-side access to the cookie
 		Secure:   true,                           // Set Secure flag if using HTTPS
 		SameSite: http.SameSiteStrictMode,        // Set SameSite attribute
+		Path:     "/",                            // Same path as the cookie set on login
 	})
 
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// LogoutAll godoc
+// @Summary Logout User From All Devices
+// @Description Revoke every session of the logged in user. Access tokens already issued stay valid until they expire
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid access token"
+// @Router /v2/users/logout/all [put]
+func (uh *UserHandler) LogoutAll(ctx echo.Context) error {
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := uh.usecase.UserSession.LogoutAll(ctx.Request().Context(), tokenValue); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     "refresh_token",
+		Value:    "",
+		Expires:  time.Now().Add(-1 * time.Hour),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		Path:     "/",
+	})
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetSessions godoc
+// @Summary Get User Sessions
+// @Description List the devices the user is logged in with. Users can see their own sessions, users with the user-session:manage permission anyone's
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param communityId path string true "Community ID of the user"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.List{data=[]models.UserSessionResponse} "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 403 {object} models.ErrorResponse "Sessions of another user"
+// @Router /v2/users/{communityId}/sessions [get]
+func (uh *UserHandler) GetSessions(ctx echo.Context) error {
+	parameter := models.GetUserSessionsParameter{
+		CommunityId: ctx.Param("communityId"),
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	sessions, err := uh.usecase.UserSession.GetAll(ctx.Request().Context(), parameter, tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.SuccessList(ctx, http.StatusOK, len(sessions), sessions)
+}
+
+// DeleteSession godoc
+// @Summary Delete User Session
+// @Description Log a device of the user out, so its refresh token cannot be used anymore. Users can remove their own sessions, users with the user-session:manage permission anyone's
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param communityId path string true "Community ID of the user"
+// @Param sessionId path string true "ID of the session"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 204 "No Content"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 403 {object} models.ErrorResponse "Session of another user"
+// @Failure 404 {object} models.ErrorResponse "Session not found or already logged out"
+// @Router /v2/users/{communityId}/sessions/{sessionId} [delete]
+func (uh *UserHandler) DeleteSession(ctx echo.Context) error {
+	parameter := models.DeleteUserSessionParameter{
+		CommunityId: ctx.Param("communityId"),
+		SessionId:   ctx.Param("sessionId"),
+	}
+
+	if err := validator.Validate(parameter); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	if err := uh.usecase.UserSession.Delete(ctx.Request().Context(), parameter, tokenValue); err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
 // GetAllUserInternal godoc
 // @Summary Get All Users
 // @Description Get all information needed about user in list
@@ -364,14 +540,16 @@ func (uh *UserHandler) GetAllUserInternal(ctx echo.Context) error {
 
 // UpdateRolesOrUserType godoc
 // @Summary Update User Role or User Type
-// @Description Update Role or user Type
+// @Description Update Role or user Type. Only roles and user types whose permissions the caller holds can be given, and only to users in the caller's campus scope
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user body models.UpdateRolesOrUserTypesRequest true "User object that needs to be added"
 // @Param X-API-Key header string true "mandatory header to access endpoint"
 // @Success 201 {object} models.UpdateRolesOrUserTypesResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
 // @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 403 {object} models.ErrorResponse "Not allowed to manage users or to give these roles or user types"
 // @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
 // @Router /api/v2/users/roles-types/update [put]
 func (uh *UserHandler) UpdateRolesOrUserType(ctx echo.Context) error {
@@ -555,3 +733,40 @@ func (uh *UserHandler) Delete(ctx echo.Context) error {
 
 	return response.Success(ctx, http.StatusOK, user.ToResponse())
 }
+
+// UnlockLogin godoc
+// @Summary Unlock Login
+// @Description Let an identifier or IP address that is locked out after too many failed logins log in again right away
+// @Tags users-internal
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body models.UnlockLoginRequest true "Identifier and/or IP address to unlock"
+// @Param X-API-Key header string true "mandatory header to access endpoint"
+// @Success 200 {object} models.UnlockLoginResponse "Response indicates that the request succeeded and the resources has been fetched and transmitted in the message body"
+// @Failure 400 {object} models.ErrorResponse "Bad Request"
+// @Failure 403 {object} models.ErrorResponse "Not allowed to manage users"
+// @Failure 422 {object} models.ErrorResponse{errors=models.ErrorValidateResponse} "Validation error. This can happen if there is an error validation while create account"
+// @Router /v2/internal/users/login/unlock [post]
+func (uh *UserHandler) UnlockLogin(ctx echo.Context) error {
+	var request models.UnlockLoginRequest
+	if err := ctx.Bind(&request); err != nil {
+		return response.Error(ctx, models.ErrorInvalidInput)
+	}
+
+	if err := validator.Validate(request); err != nil {
+		return response.ErrorValidation(ctx, err)
+	}
+
+	tokenValue, err := models.GetValueFromToken(ctx)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	res, err := uh.usecase.LoginAttempt.Unlock(ctx.Request().Context(), request, tokenValue)
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	return response.Success(ctx, http.StatusOK, res)
+}
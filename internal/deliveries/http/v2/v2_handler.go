@@ -15,10 +15,12 @@ func NewV2Handler(g *echo.Group, u *usecases.Usecases, c *config.Configuration,
 
 	// Initialize handlers
 	NewEventHandler(v2, u, c, a)
-	NewUserHandler(v2, u, c)
+	NewUserHandler(v2, u, c, a)
 	NewTokenHandler(v2, a, c, u)
-	NewRoleHandler(v2, u, c)
+	NewRoleHandler(v2, u, c, a)
 	NewConfigHandler(v2, c, u)
-	NewCoolHandler(v2, u, c)
-	NewFlagHandler(v2, u, *c)
+	NewCoolHandler(v2, u, c, a)
+	NewFlagHandler(v2, u, *c, a)
+	NewPermissionHandler(v2, u, c, a)
+	NewAuditHandler(v2, u, c, a)
 }
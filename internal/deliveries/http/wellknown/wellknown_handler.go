@@ -0,0 +1,40 @@
+package wellknown
+
+import (
+	"go-community/internal/pkg/authorization"
+	"go-community/internal/pkg/response"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type wellKnownHandler struct {
+	auth *authorization.Auth
+}
+
+func NewWellKnownHandler(e *echo.Echo, a *authorization.Auth) {
+	wh := wellKnownHandler{
+		auth: a,
+	}
+
+	wellKnown := e.Group("/.well-known")
+	wellKnown.GET("/jwks.json", wh.JWKS)
+}
+
+// JWKS godoc
+// @Summary Get JSON Web Key Set
+// @Description Public keys to verify access tokens with, matched by the kid header of the token. Empty when tokens are signed with a shared secret
+// @Tags tokens
+// @Produce json
+// @Success 200 {object} authorization.JWKSet "Key set as defined by RFC 7517"
+// @Router /.well-known/jwks.json [get]
+func (wh wellKnownHandler) JWKS(ctx echo.Context) error {
+	keys, err := wh.auth.JWKS()
+	if err != nil {
+		return response.Error(ctx, err)
+	}
+
+	// Verifiers cache the keys, a rotated key has to be published at least this long before it signs
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return ctx.JSON(http.StatusOK, keys)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+var TYPE_AUDIT_EVENT = "auditEvent"
+
+// Actions recorded in the audit log, as entity.change
+const (
+	AUDIT_ACTION_USER_UPDATE                      = "user.update"
+	AUDIT_ACTION_USER_DELETE                      = "user.delete"
+	AUDIT_ACTION_USER_ROLES_UPDATE                = "user.roles.update"
+	AUDIT_ACTION_USER_USER_TYPES_UPDATE           = "user.userTypes.update"
+	AUDIT_ACTION_ROLE_PERMISSIONS_UPDATE          = "role.permissions.update"
+	AUDIT_ACTION_USER_TYPE_PERMISSIONS_UPDATE     = "userType.permissions.update"
+	AUDIT_ACTION_FEATURE_FLAG_CREATE              = "featureFlag.create"
+	AUDIT_ACTION_FEATURE_FLAG_UPDATE              = "featureFlag.update"
+	AUDIT_ACTION_FEATURE_FLAG_TOGGLE              = "featureFlag.toggle"
+	AUDIT_ACTION_FEATURE_FLAG_DELETE              = "featureFlag.delete"
+	AUDIT_ACTION_EVENT_UPDATE                     = "event.update"
+	AUDIT_ACTION_EVENT_CANCEL                     = "event.cancel"
+	AUDIT_ACTION_EVENT_INSTANCE_UPDATE            = "eventInstance.update"
+	AUDIT_ACTION_EVENT_INSTANCE_CANCEL            = "eventInstance.cancel"
+	AUDIT_ACTION_EVENT_REGISTRATION_STATUS_UPDATE = "eventRegistrationRecord.status.update"
+	AUDIT_ACTION_COOL_MEMBER_ADD                  = "coolMember.add"
+	AUDIT_ACTION_COOL_MEMBER_TRANSFER             = "coolMember.transfer"
+	AUDIT_ACTION_COOL_MEMBER_REMOVE               = "coolMember.remove"
+	AUDIT_ACTION_COOL_NEW_JOINER_ASSIGN           = "coolNewJoiner.assign"
+	AUDIT_ACTION_COOL_NEW_JOINER_CONVERT          = "coolNewJoiner.convert"
+	AUDIT_ACTION_COOL_UPDATE                      = "cool.update"
+	AUDIT_ACTION_COOL_LEADERSHIP_UPDATE           = "cool.leadership.update"
+	AUDIT_ACTION_COOL_DEACTIVATE                  = "cool.deactivate"
+	AUDIT_ACTION_COOL_MERGE                       = "cool.merge"
+	AUDIT_ACTION_COOL_SPLIT                       = "cool.split"
+	AUDIT_ACTION_LOGIN_LOCKOUT_LOCK               = "loginLockout.lock"
+	AUDIT_ACTION_LOGIN_LOCKOUT_UNLOCK             = "loginLockout.unlock"
+)
+
+// AUDIT_ACTOR_SYSTEM is the actor of changes the app makes on its own, e.g. locking out repeated failed logins
+const AUDIT_ACTOR_SYSTEM = "system"
+
+// AuditEvent is one change made by an actor. Before and After only hold the fields that changed.
+type AuditEvent struct {
+	ID               int64
+	ActorCommunityId string
+	Action           string
+	EntityType       string
+	EntityId         string
+	Before           map[string]interface{} `gorm:"type:jsonb;serializer:json"`
+	After            map[string]interface{} `gorm:"type:jsonb;serializer:json"`
+	RequestId        string
+	CreatedAt        time.Time
+}
+
+func (ae *AuditEvent) ToResponse() AuditEventResponse {
+	return AuditEventResponse{
+		Type:             TYPE_AUDIT_EVENT,
+		ID:               ae.ID,
+		ActorCommunityId: ae.ActorCommunityId,
+		Action:           ae.Action,
+		EntityType:       ae.EntityType,
+		EntityId:         ae.EntityId,
+		Before:           ae.Before,
+		After:            ae.After,
+		RequestId:        ae.RequestId,
+		CreatedAt:        ae.CreatedAt,
+	}
+}
+
+type (
+	GetAllAuditEventCursor struct {
+		ID        int64
+		CreatedAt time.Time
+	}
+	GetAllAuditEventCursorParam struct {
+		Direction        string `query:"direction" validate:"omitempty,oneof=next prev"`
+		Cursor           string `query:"cursor"`
+		Limit            int    `query:"limit" validate:"omitempty,min=1,max=100"`
+		ActorCommunityId string `query:"actorCommunityId" validate:"omitempty,communityId"`
+		Action           string `query:"action" example:"user.update"`
+		EntityType       string `query:"entityType" example:"user"`
+		EntityId         string `query:"entityId"`
+		RequestId        string `query:"requestId"`
+		From             string `query:"from" validate:"omitempty,yyymmddFormat" example:"2024-01-01"`
+		To               string `query:"to" validate:"omitempty,yyymmddFormat" example:"2024-01-31"`
+		// CreatedFrom and CreatedUntil are From and To as a half open range of time
+		CreatedFrom  *time.Time `query:"-"`
+		CreatedUntil *time.Time `query:"-"`
+	}
+	AuditEventResponse struct {
+		Type             string                 `json:"type" example:"auditEvent"`
+		ID               int64                  `json:"id" example:"1"`
+		ActorCommunityId string                 `json:"actorCommunityId" example:"202401010001"`
+		Action           string                 `json:"action" example:"user.update"`
+		EntityType       string                 `json:"entityType" example:"user"`
+		EntityId         string                 `json:"entityId" example:"202401010002"`
+		Before           map[string]interface{} `json:"before"`
+		After            map[string]interface{} `json:"after"`
+		RequestId        string                 `json:"requestId"`
+		CreatedAt        time.Time              `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	}
+)
+
+type requestIdKey struct{}
+
+// WithRequestId keeps the X-Request-Id of the request in its context, so usecases can record it.
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
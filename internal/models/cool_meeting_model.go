@@ -0,0 +1,208 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+var (
+	TYPE_COOL_MEETING            = "coolMeeting"
+	TYPE_COOL_MEETING_ATTENDANCE = "coolMeetingAttendance"
+)
+
+type CoolMeeting struct {
+	ID           int
+	CoolId       int
+	Title        string
+	StartAt      time.Time
+	EndAt        time.Time
+	LocationType string
+	LocationName string
+	RecurrenceAt sql.NullTime
+	Status       string
+	CreatedBy    string
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+}
+
+type CoolMeetingAttendance struct {
+	ID          int
+	MeetingId   int
+	CommunityId string
+	Status      string
+	Reason      string
+	MarkedBy    string
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+type CoolMeetingStatus int32
+
+const (
+	COOL_MEETING_STATUS_ACTIVE CoolMeetingStatus = iota
+	COOL_MEETING_STATUS_CANCELLED
+)
+
+const (
+	CoolMeetingStatusActive    = "active"
+	CoolMeetingStatusCancelled = "cancelled"
+)
+
+var (
+	MapCoolMeetingStatus = map[CoolMeetingStatus]string{
+		COOL_MEETING_STATUS_ACTIVE:    CoolMeetingStatusActive,
+		COOL_MEETING_STATUS_CANCELLED: CoolMeetingStatusCancelled,
+	}
+)
+
+type CoolAttendanceStatus int32
+
+const (
+	COOL_ATTENDANCE_STATUS_PRESENT CoolAttendanceStatus = iota
+	COOL_ATTENDANCE_STATUS_PERMIT
+	COOL_ATTENDANCE_STATUS_ABSENT
+)
+
+const (
+	CoolAttendanceStatusPresent = "present"
+	CoolAttendanceStatusPermit  = "permit"
+	CoolAttendanceStatusAbsent  = "absent"
+)
+
+var (
+	MapCoolAttendanceStatus = map[CoolAttendanceStatus]string{
+		COOL_ATTENDANCE_STATUS_PRESENT: CoolAttendanceStatusPresent,
+		COOL_ATTENDANCE_STATUS_PERMIT:  CoolAttendanceStatusPermit,
+		COOL_ATTENDANCE_STATUS_ABSENT:  CoolAttendanceStatusAbsent,
+	}
+)
+
+func (cm *CoolMeeting) ToResponse() CoolMeetingResponse {
+	return CoolMeetingResponse{
+		Type:         TYPE_COOL_MEETING,
+		ID:           cm.ID,
+		CoolId:       cm.CoolId,
+		Title:        cm.Title,
+		StartAt:      cm.StartAt,
+		EndAt:        cm.EndAt,
+		LocationType: cm.LocationType,
+		LocationName: cm.LocationName,
+		IsRecurring:  cm.RecurrenceAt.Valid,
+		Status:       cm.Status,
+		CreatedBy:    cm.CreatedBy,
+	}
+}
+
+func (a *CoolMeetingAttendanceDBOutput) ToResponse() CoolMeetingAttendanceResponse {
+	return CoolMeetingAttendanceResponse{
+		Type:        TYPE_COOL_MEETING_ATTENDANCE,
+		MeetingId:   a.MeetingId,
+		CommunityId: a.CommunityId,
+		Name:        a.Name,
+		Role:        a.Role,
+		Status:      a.Status,
+		Reason:      a.Reason,
+		MarkedBy:    a.MarkedBy,
+	}
+}
+
+type (
+	CoolMeetingParameter struct {
+		CoolId    int `validate:"required,min=1"`
+		MeetingId int `validate:"required,min=1"`
+	}
+	GetAllCoolMeetingParameter struct {
+		CoolId int    `query:"-" validate:"required,min=1"`
+		From   string `query:"from" validate:"omitempty,yyymmddFormat" example:"2024-01-01"`
+		To     string `query:"to" validate:"omitempty,yyymmddFormat" example:"2024-01-31"`
+	}
+	CreateCoolMeetingRequest struct {
+		Title        string `json:"title" validate:"max=255" example:"Christmas gathering"`
+		StartAt      string `json:"startAt" validate:"required" example:"2024-12-20T19:00:00+07:00"`
+		EndAt        string `json:"endAt" validate:"required" example:"2024-12-20T21:00:00+07:00"`
+		LocationType string `json:"locationType" validate:"omitempty,oneof=offline onsite hybrid" example:"onsite"`
+		LocationName string `json:"locationName" validate:"max=255" example:"Fellowship hall"`
+	}
+	CoolMeetingResponse struct {
+		Type         string    `json:"type" example:"coolMeeting"`
+		ID           int       `json:"id" example:"1"`
+		CoolId       int       `json:"coolId" example:"1"`
+		Title        string    `json:"title" example:"Christmas gathering"`
+		StartAt      time.Time `json:"startAt" example:"2024-12-20T19:00:00+07:00"`
+		EndAt        time.Time `json:"endAt" example:"2024-12-20T21:00:00+07:00"`
+		LocationType string    `json:"locationType" example:"onsite"`
+		LocationName string    `json:"locationName" example:"Fellowship hall"`
+		IsRecurring  bool      `json:"isRecurring"`
+		Status       string    `json:"status" example:"active"`
+		CreatedBy    string    `json:"createdBy,omitempty"`
+	}
+)
+
+type (
+	MarkCoolMeetingAttendanceRequest struct {
+		Attendances []CoolMeetingAttendanceRequest `json:"attendances" validate:"required,min=1,dive"`
+	}
+	CoolMeetingAttendanceRequest struct {
+		CommunityId string `json:"communityId" validate:"required,communityId" example:"202401010001"`
+		Status      string `json:"status" validate:"required,oneof=present permit absent" example:"present"`
+		Reason      string `json:"reason" example:"Out of town"`
+	}
+	// CoolMeetingAttendanceDBOutput is a member of the cool at the meeting, with what was marked for them if anything
+	CoolMeetingAttendanceDBOutput struct {
+		MeetingId   int
+		CommunityId string
+		Name        string
+		Role        string
+		Status      string
+		Reason      string
+		MarkedBy    string
+	}
+	CoolMeetingAttendanceResponse struct {
+		Type        string `json:"type" example:"coolMeetingAttendance"`
+		MeetingId   int    `json:"meetingId" example:"1"`
+		CommunityId string `json:"communityId" example:"202401010001"`
+		Name        string `json:"name" example:"John Doe"`
+		Role        string `json:"role" example:"member"`
+		Status      string `json:"status,omitempty" example:"present"`
+		Reason      string `json:"reason,omitempty"`
+		MarkedBy    string `json:"markedBy,omitempty"`
+	}
+)
+
+type (
+	GetCoolAttendanceParameter struct {
+		CoolId int    `query:"-" validate:"required,min=1"`
+		From   string `query:"from" validate:"omitempty,yyymmddFormat" example:"2024-01-01"`
+		To     string `query:"to" validate:"omitempty,yyymmddFormat" example:"2024-12-31"`
+	}
+	// GetCoolAttendanceDBOutput counts the meetings a member could attend, that is those held while they were in the cool
+	GetCoolAttendanceDBOutput struct {
+		CommunityId   string
+		Name          string
+		Role          string
+		IsMember      bool
+		TotalMeetings int
+		PresentCount  int
+		PermitCount   int
+	}
+	GetCoolAttendanceDetailResponse struct {
+		Type                 string  `json:"type" example:"cool"`
+		CoolId               int     `json:"coolId" example:"1"`
+		From                 string  `json:"from" example:"2024-01-01"`
+		To                   string  `json:"to" example:"2024-12-31"`
+		TotalMeetings        int     `json:"totalMeetings" example:"48"`
+		AttendancePercentage float64 `json:"attendancePercentage" example:"75.5"`
+	}
+	GetCoolAttendanceListResponse struct {
+		Type                 string  `json:"type" example:"coolMember"`
+		CommunityId          string  `json:"communityId" example:"202401010001"`
+		Name                 string  `json:"name" example:"John Doe"`
+		Role                 string  `json:"role" example:"member"`
+		IsMember             bool    `json:"isMember"`
+		AttendanceCount      int     `json:"attendanceCount" example:"40"`
+		PermitCount          int     `json:"permitCount" example:"4"`
+		AbsenceCount         int     `json:"absenceCount" example:"4"`
+		TotalMeetings        int     `json:"totalMeetings" example:"48"`
+		AttendancePercentage float64 `json:"attendancePercentage" example:"83.33"`
+	}
+)
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+var TYPE_COOL_MEMBER = "coolMember"
+
+// CoolMember is a stay of a user in a cool. The membership without LeftAt is the current one.
+type CoolMember struct {
+	ID          int
+	CoolId      int
+	CommunityId string
+	Role        string
+	JoinedAt    time.Time
+	LeftAt      sql.NullTime
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+type CoolMemberRole int32
+
+const (
+	COOL_MEMBER_ROLE_MEMBER CoolMemberRole = iota
+	COOL_MEMBER_ROLE_CORE
+	COOL_MEMBER_ROLE_LEADER
+)
+
+const (
+	CoolMemberRoleMember = "member"
+	CoolMemberRoleCore   = "core"
+	CoolMemberRoleLeader = "leader"
+)
+
+var (
+	MapCoolMemberRole = map[CoolMemberRole]string{
+		COOL_MEMBER_ROLE_MEMBER: CoolMemberRoleMember,
+		COOL_MEMBER_ROLE_CORE:   CoolMemberRoleCore,
+		COOL_MEMBER_ROLE_LEADER: CoolMemberRoleLeader,
+	}
+
+	// MapCoolMemberRoleUserType is the user type that comes with a role in a cool
+	MapCoolMemberRoleUserType = map[string]string{
+		CoolMemberRoleCore:   "cool-core",
+		CoolMemberRoleLeader: "cool-leader",
+	}
+)
+
+func (c *CoolMemberDBOutput) ToResponse() CoolMemberResponse {
+	var leftAt *time.Time
+	if c.LeftAt.Valid {
+		leftAt = &c.LeftAt.Time
+	}
+
+	return CoolMemberResponse{
+		Type:        TYPE_COOL_MEMBER,
+		CoolId:      c.CoolId,
+		CommunityId: c.CommunityId,
+		Name:        c.Name,
+		PhoneNumber: c.PhoneNumber,
+		Email:       c.Email,
+		Role:        c.Role,
+		JoinedAt:    c.JoinedAt,
+		LeftAt:      leftAt,
+	}
+}
+
+type (
+	CoolMemberParameter struct {
+		CoolId int `validate:"required,min=1"`
+	}
+	CoolMemberCommunityIdParameter struct {
+		CoolId      int    `validate:"required,min=1"`
+		CommunityId string `validate:"required,communityId"`
+	}
+	AddCoolMemberRequest struct {
+		CommunityId string `json:"communityId" validate:"required,communityId" example:"202401010001"`
+		Role        string `json:"role" validate:"omitempty,oneof=member core leader" example:"member"`
+		JoinedAt    string `json:"joinedAt" validate:"omitempty,yyymmddFormat" example:"2024-01-01"`
+	}
+	TransferCoolMemberRequest struct {
+		CoolId int    `json:"coolId" validate:"required,min=1" example:"2"`
+		Role   string `json:"role" validate:"omitempty,oneof=member core leader" example:"member"`
+	}
+	CoolMemberDBOutput struct {
+		ID          int
+		CoolId      int
+		CommunityId string
+		Name        string
+		PhoneNumber string
+		Email       string
+		Role        string
+		JoinedAt    time.Time
+		LeftAt      sql.NullTime
+	}
+	CoolMemberResponse struct {
+		Type        string     `json:"type" example:"coolMember"`
+		CoolId      int        `json:"coolId" example:"1"`
+		CommunityId string     `json:"communityId" example:"202401010001"`
+		Name        string     `json:"name" example:"John Doe"`
+		PhoneNumber string     `json:"phoneNumber,omitempty" example:"+628123456789"`
+		Email       string     `json:"email,omitempty" example:"john@gmail.com"`
+		Role        string     `json:"role" example:"member"`
+		JoinedAt    time.Time  `json:"joinedAt" example:"2024-01-01T00:00:00Z"`
+		LeftAt      *time.Time `json:"leftAt,omitempty" example:"2024-06-01T00:00:00Z"`
+	}
+)
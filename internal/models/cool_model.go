@@ -22,6 +22,10 @@ type Cool struct {
 	LocationType            string
 	LocationName            string
 	Status                  string
+	MeetingRule             string
+	MeetingStartAt          *time.Time
+	MeetingDurationMinutes  int
+	Capacity                *int
 	CreatedAt               *time.Time
 	UpdatedAt               *time.Time
 	DeletedAt               sql.NullTime
@@ -43,6 +47,7 @@ func (c *CreateCoolResponse) ToResponse() CreateCoolResponse {
 		LocationType: c.LocationType,
 		LocationName: c.LocationName,
 		Status:       c.Status,
+		Meeting:      c.Meeting,
 	}
 }
 
@@ -59,22 +64,34 @@ type (
 		Recurrence              *string  `json:"recurrence"`
 		LocationType            string   `json:"locationType" validate:"required,oneof=offline onsite hybrid"`
 		LocationName            *string  `json:"locationName"`
+		// MeetingRule repeats the meeting starting at MeetingStartAt, e.g. FREQ=WEEKLY;BYDAY=FR
+		MeetingRule            string `json:"meetingRule" example:"FREQ=WEEKLY;BYDAY=FR"`
+		MeetingStartAt         string `json:"meetingStartAt" validate:"required_with=MeetingRule" example:"2024-01-05T19:00:00+07:00"`
+		MeetingDurationMinutes int    `json:"meetingDurationMinutes" validate:"omitempty,min=1,max=1440" example:"120"`
+		// Capacity is how many members the cool takes before it is no longer open for new joiners
+		Capacity *int `json:"capacity" validate:"omitempty,min=1" example:"15"`
 	}
 	CreateCoolResponse struct {
-		Type         string                      `json:"type"`
-		Name         string                      `json:"name"`
-		Description  string                      `json:"description"`
-		CampusCode   string                      `json:"campusCode"`
-		CampusName   string                      `json:"campusName"`
-		Facilitators []CoolLeaderAndCoreResponse `json:"facilitators"`
-		Leaders      []CoolLeaderAndCoreResponse `json:"leaders"`
-		CoreTeam     []CoolLeaderAndCoreResponse `json:"coreTeam"`
-		Category     string                      `json:"category"`
-		Gender       string                      `json:"gender"`
-		Recurrence   string                      `json:"recurrence"`
-		LocationType string                      `json:"locationType"`
-		LocationName string                      `json:"locationName"`
-		Status       string                      `json:"status"`
+		Type         string                       `json:"type"`
+		Name         string                       `json:"name"`
+		Description  string                       `json:"description"`
+		CampusCode   string                       `json:"campusCode"`
+		CampusName   string                       `json:"campusName"`
+		Facilitators []CoolLeaderAndCoreResponse  `json:"facilitators"`
+		Leaders      []CoolLeaderAndCoreResponse  `json:"leaders"`
+		CoreTeam     []CoolLeaderAndCoreResponse  `json:"coreTeam"`
+		Category     string                       `json:"category"`
+		Gender       string                       `json:"gender"`
+		Recurrence   string                       `json:"recurrence"`
+		LocationType string                       `json:"locationType"`
+		LocationName string                       `json:"locationName"`
+		Status       string                       `json:"status"`
+		Meeting      *CoolMeetingScheduleResponse `json:"meeting,omitempty"`
+	}
+	CoolMeetingScheduleResponse struct {
+		Rule            string    `json:"rule" example:"FREQ=WEEKLY;BYDAY=FR"`
+		StartAt         time.Time `json:"startAt" example:"2024-01-05T19:00:00+07:00"`
+		DurationMinutes int       `json:"durationMinutes" example:"120"`
 	}
 	CoolLeaderAndCoreResponse struct {
 		Type        string `json:"type"`
@@ -114,18 +131,134 @@ type (
 )
 
 type GetCoolDetailResponse struct {
-	Type         string                      `json:"type"`
-	Name         string                      `json:"name"`
-	Description  string                      `json:"description"`
-	CampusCode   string                      `json:"campusCode"`
-	CampusName   string                      `json:"campusName"`
-	Facilitators []CoolLeaderAndCoreResponse `json:"facilitators"`
-	Leaders      []CoolLeaderAndCoreResponse `json:"leaders"`
-	CoreTeam     []CoolLeaderAndCoreResponse `json:"coreTeam"`
-	Category     string                      `json:"category"`
-	Gender       string                      `json:"gender"`
-	Recurrence   string                      `json:"recurrence"`
-	LocationType string                      `json:"locationType"`
-	LocationName string                      `json:"locationName"`
-	Status       string                      `json:"status"`
+	Type         string                       `json:"type"`
+	ID           int                          `json:"id"`
+	Name         string                       `json:"name"`
+	Description  string                       `json:"description"`
+	CampusCode   string                       `json:"campusCode"`
+	CampusName   string                       `json:"campusName"`
+	Facilitators []CoolLeaderAndCoreResponse  `json:"facilitators"`
+	Leaders      []CoolLeaderAndCoreResponse  `json:"leaders"`
+	CoreTeam     []CoolLeaderAndCoreResponse  `json:"coreTeam"`
+	Category     string                       `json:"category"`
+	Gender       string                       `json:"gender"`
+	Recurrence   string                       `json:"recurrence"`
+	LocationType string                       `json:"locationType"`
+	LocationName string                       `json:"locationName"`
+	Status       string                       `json:"status"`
+	Meeting      *CoolMeetingScheduleResponse `json:"meeting,omitempty"`
+	Capacity     *int                         `json:"capacity"`
+	// Members is only shown to the leaders and facilitators of the cool
+	Members []CoolMemberResponse `json:"members,omitempty"`
+}
+
+// MeetingSchedule is the recurrence of the meetings of the cool, or nil when it has none.
+func (c *Cool) MeetingSchedule() *CoolMeetingScheduleResponse {
+	if c.MeetingRule == "" || c.MeetingStartAt == nil {
+		return nil
+	}
+
+	return &CoolMeetingScheduleResponse{
+		Rule:            c.MeetingRule,
+		StartAt:         *c.MeetingStartAt,
+		DurationMinutes: c.MeetingDurationMinutes,
+	}
 }
+
+type (
+	// UpdateCoolRequest replaces the details of the cool. Description, Gender, Recurrence and LocationName are kept
+	// when left out.
+	UpdateCoolRequest struct {
+		Name                   string  `json:"name" validate:"required,min=1,max=50,nospecial" example:"Professionals"`
+		Description            *string `json:"description"`
+		Category               string  `json:"category" validate:"required" example:"PROFESSIONAL"`
+		Gender                 *string `json:"gender" validate:"omitempty,oneof=male female all" example:"all"`
+		Recurrence             *string `json:"recurrence"`
+		LocationType           string  `json:"locationType" validate:"required,oneof=offline onsite hybrid" example:"offline"`
+		LocationName           *string `json:"locationName"`
+		MeetingRule            string  `json:"meetingRule" example:"FREQ=WEEKLY;BYDAY=FR"`
+		MeetingStartAt         string  `json:"meetingStartAt" validate:"required_with=MeetingRule" example:"2024-01-05T19:00:00+07:00"`
+		MeetingDurationMinutes int     `json:"meetingDurationMinutes" validate:"omitempty,min=1,max=1440" example:"120"`
+		// Capacity is kept when left out, 0 takes the limit away
+		Capacity *int `json:"capacity" validate:"omitempty,min=0" example:"15"`
+	}
+	// UpdateCoolLeadershipRequest replaces the facilitators, leaders and core team of the cool. Leaders and core team
+	// members join the cool if they are not in it yet, and the ones left out stay on as members.
+	UpdateCoolLeadershipRequest struct {
+		FacilitatorCommunityIds []string `json:"facilitatorCommunityIds" validate:"required"`
+		LeaderCommunityIds      []string `json:"leaderCommunityIds" validate:"required,min=1"`
+		CoreCommunityIds        []string `json:"coreCommunityIds"`
+	}
+	MergeCoolRequest struct {
+		TargetCoolId int `json:"targetCoolId" validate:"required,min=1" example:"2"`
+	}
+	// SplitCoolRequest starts a new cool with the details of the cool, the given ones aside, and moves the listed
+	// members of the cool into it with their new role.
+	SplitCoolRequest struct {
+		Name               string   `json:"name" validate:"required,min=1,max=50,nospecial" example:"Professionals 2"`
+		Description        *string  `json:"description"`
+		LocationType       string   `json:"locationType" validate:"omitempty,oneof=offline onsite hybrid" example:"offline"`
+		LocationName       *string  `json:"locationName"`
+		LeaderCommunityIds []string `json:"leaderCommunityIds" validate:"required,min=1"`
+		CoreCommunityIds   []string `json:"coreCommunityIds"`
+		MemberCommunityIds []string `json:"memberCommunityIds"`
+	}
+)
+
+type (
+	SearchCoolCursor struct {
+		ID   int
+		Name string
+	}
+	SearchCoolCursorParam struct {
+		Direction    string `query:"direction" validate:"omitempty,oneof=next prev"`
+		Cursor       string `query:"cursor"`
+		Limit        int    `query:"limit" validate:"omitempty,min=1,max=100"`
+		Name         string `query:"name"`
+		CampusCode   string `query:"campusCode" validate:"omitempty,min=3,max=3" example:"BKS"`
+		Category     string `query:"category" example:"Professional"`
+		Gender       string `query:"gender" validate:"omitempty,oneof=male female" example:"male"`
+		MeetingDay   string `query:"meetingDay" validate:"omitempty,oneof=monday tuesday wednesday thursday friday saturday sunday" example:"friday"`
+		LocationType string `query:"locationType" validate:"omitempty,oneof=offline onsite hybrid" example:"offline"`
+		// Area is one of the locations of the campus in the campusLocationValue config
+		Area   string `query:"area" example:"Bekasi Timur"`
+		IsOpen bool   `query:"isOpen"`
+		// MeetingByDay and MeetingIsoDay are MeetingDay as a BYDAY code of a meeting rule and as an ISO day of the week
+		MeetingByDay  string `query:"-"`
+		MeetingIsoDay int    `query:"-"`
+	}
+	SearchCoolDBOutput struct {
+		ID                     int
+		Name                   string
+		Description            string
+		CampusCode             string
+		Category               string
+		Gender                 string
+		LocationType           string
+		LocationName           string
+		LeaderCommunityIds     pq.StringArray `gorm:"type:text[]"`
+		MeetingRule            string
+		MeetingStartAt         *time.Time
+		MeetingDurationMinutes int
+		Capacity               *int
+		MemberCount            int
+	}
+	SearchCoolResponse struct {
+		Type         string                       `json:"type" example:"cool"`
+		ID           int                          `json:"id" example:"1"`
+		Name         string                       `json:"name" example:"Professionals"`
+		Description  string                       `json:"description"`
+		CampusCode   string                       `json:"campusCode" example:"BKS"`
+		CampusName   string                       `json:"campusName" example:"Bekasi"`
+		Category     string                       `json:"category" example:"PROFESSIONAL"`
+		Gender       string                       `json:"gender" example:"all"`
+		LocationType string                       `json:"locationType" example:"offline"`
+		LocationName string                       `json:"locationName" example:"Bekasi Timur"`
+		Leaders      []CoolLeaderAndCoreResponse  `json:"leaders"`
+		Meeting      *CoolMeetingScheduleResponse `json:"meeting,omitempty"`
+		MeetingDays  []string                     `json:"meetingDays,omitempty" example:"friday"`
+		MemberCount  int                          `json:"memberCount" example:"12"`
+		Capacity     *int                         `json:"capacity" example:"15"`
+		IsOpen       bool                         `json:"isOpen"`
+	}
+)
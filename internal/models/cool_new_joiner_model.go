@@ -7,6 +7,11 @@ import (
 
 var TYPE_COOL_NEW_JOINER string = "coolNewJoiner"
 
+var (
+	TYPE_COOL_NEW_JOINER_NOTE       = "coolNewJoinerNote"
+	TYPE_COOL_NEW_JOINER_SUGGESTION = "coolNewJoinerSuggestion"
+)
+
 type CoolNewJoiner struct {
 	ID                  int
 	Name                string
@@ -20,11 +25,41 @@ type CoolNewJoiner struct {
 	Location            string
 	UpdatedBy           *string
 	Status              string
+	CoolId              *int
+	FollowUpCommunityId *string
+	AssignedAt          *time.Time
+	FollowedUpAt        *time.Time
+	SlaRemindedAt       *time.Time
+	CommunityId         *string
+	ConvertedAt         *time.Time
 	CreatedAt           *time.Time
 	UpdatedAt           *time.Time
 	DeletedAt           sql.NullTime
 }
 
+// CoolNewJoinerNote is a follow up note on a new joiner, with the status they had when it was written.
+type CoolNewJoinerNote struct {
+	ID          int
+	NewJoinerId int
+	Note        string
+	Status      string
+	CreatedBy   string
+	CreatedAt   *time.Time
+}
+
+func (n *CoolNewJoinerNoteDBOutput) ToResponse() CoolNewJoinerNoteResponse {
+	return CoolNewJoinerNoteResponse{
+		Type:          TYPE_COOL_NEW_JOINER_NOTE,
+		ID:            n.ID,
+		NewJoinerId:   n.NewJoinerId,
+		Note:          n.Note,
+		Status:        n.Status,
+		CreatedBy:     n.CreatedBy,
+		CreatedByName: n.CreatedByName,
+		CreatedAt:     n.CreatedAt,
+	}
+}
+
 func (e *CreateCoolNewJoinerResponse) ToResponse() *CreateCoolNewJoinerResponse {
 	return &CreateCoolNewJoinerResponse{
 		Type:                TYPE_COOL_NEW_JOINER,
@@ -73,6 +108,8 @@ type (
 		Status    string `json:"status" validate:"required,oneof=pending followed completed cancelled" example:"active"`
 		Id        int    `json:"id" validate:"required,min=1" example:"1"`
 		UpdatedBy string `json:"updatedBy" validate:"required,min=1,max=255" example:"admin"`
+		// Note is kept in the follow up history of the new joiner along with the status
+		Note string `json:"note" validate:"max=2000" example:"Called, will join this Friday"`
 	}
 	UpdateCoolNewJoinerResponse struct {
 		Type                string    `json:"type" example:"coolNewJoiner"`
@@ -112,6 +149,13 @@ type (
 		Status              string `query:"status" validate:"omitempty,oneof=pending followed completed cancelled" example:"active"`
 		Gender              string `query:"gender" validate:"omitempty,oneof=male female"`
 		Location            string `query:"location"`
+		CoolId              int    `query:"coolId" validate:"omitempty,min=1"`
+		FollowUpCommunityId string `query:"followUpCommunityId" validate:"omitempty,communityId"`
+		IsOverdue           bool   `query:"isOverdue"`
+		// CampusScope limits the list to the campuses the caller administers
+		CampusScope []string `query:"-"`
+		// OverdueBefore is when a new joiner still pending has to have joined to be overdue
+		OverdueBefore *time.Time `query:"-"`
 	}
 	GetCoolNewJoinerResponse struct {
 		Type                string       `json:"type" example:"coolNewJoiner"`
@@ -128,9 +172,100 @@ type (
 		Location            string       `json:"location" example:"Bekasi Timur"`
 		UpdatedBy           string       `json:"updatedBy" example:"admin"`
 		Status              string       `json:"status" example:"followed"`
+		CoolId              *int         `json:"coolId" example:"1"`
+		CoolName            *string      `json:"coolName" example:"COOL Bekasi 1"`
+		FollowUpCommunityId *string      `json:"followUpCommunityId" example:"202401010001"`
+		FollowUpName        *string      `json:"followUpName" example:"Jane Doe"`
+		AssignedAt          *time.Time   `json:"assignedAt" example:"2023-01-01T00:00:00Z"`
+		FollowedUpAt        *time.Time   `json:"followedUpAt" example:"2023-01-02T00:00:00Z"`
+		CommunityId         *string      `json:"communityId" example:"202401010002"`
+		ConvertedAt         *time.Time   `json:"convertedAt" example:"2023-01-09T00:00:00Z"`
+		IsOverdue           bool         `json:"isOverdue"`
 		CreatedAt           time.Time    `json:"createdAt" example:"2023-01-01T00:00:00Z"`
 		UpdatedAt           time.Time    `json:"updatedAt" example:"2023-01-01T00:00:00Z"`
 		DeletedAt           sql.NullTime `json:"-" example:"2023-01-01T00:00:00Z"`
 		DeletedAtString     string       `json:"deletedAt" example:"2023-01-01T00:00:00Z"`
 	}
 )
+
+type (
+	CoolNewJoinerParameter struct {
+		ID int `validate:"required,min=1"`
+	}
+	AssignCoolNewJoinerRequest struct {
+		CoolId int `json:"coolId" validate:"required,min=1" example:"1"`
+		// FollowUpCommunityId is a leader, core team member or facilitator of the cool; the least busy leader by default
+		FollowUpCommunityId string `json:"followUpCommunityId" validate:"omitempty,communityId" example:"202401010001"`
+	}
+	AssignCoolNewJoinerResponse struct {
+		Type                string    `json:"type" example:"coolNewJoiner"`
+		ID                  int       `json:"id" example:"1"`
+		Name                string    `json:"name" example:"John Doe"`
+		CoolId              int       `json:"coolId" example:"1"`
+		CoolName            string    `json:"coolName" example:"COOL Bekasi 1"`
+		FollowUpCommunityId string    `json:"followUpCommunityId" example:"202401010001"`
+		FollowUpName        string    `json:"followUpName" example:"Jane Doe"`
+		AssignedAt          time.Time `json:"assignedAt" example:"2023-01-01T00:00:00Z"`
+		Status              string    `json:"status" example:"pending"`
+	}
+	CoolNewJoinerSuggestionResponse struct {
+		Type         string                     `json:"type" example:"coolNewJoinerSuggestion"`
+		CoolId       int                        `json:"coolId" example:"1"`
+		CoolName     string                     `json:"coolName" example:"COOL Bekasi 1"`
+		CampusCode   string                     `json:"campusCode" example:"BKS"`
+		Category     string                     `json:"category" example:"PROFESSIONAL"`
+		Gender       string                     `json:"gender" example:"male"`
+		LocationName string                     `json:"locationName" example:"Bekasi Timur"`
+		Score        int                        `json:"score" example:"7"`
+		Reasons      []string                   `json:"reasons" example:"category,location"`
+		FollowUp     *CoolLeaderAndCoreResponse `json:"followUp"`
+	}
+	// CountCoolNewJoinerByFollowUpDBOutput is how many new joiners a follow up has that did not join yet
+	CountCoolNewJoinerByFollowUpDBOutput struct {
+		FollowUpCommunityId string
+		Total               int
+	}
+)
+
+type (
+	CreateCoolNewJoinerNoteRequest struct {
+		Note string `json:"note" validate:"required,min=1,max=2000" example:"Called, will join this Friday"`
+	}
+	CoolNewJoinerNoteDBOutput struct {
+		ID            int
+		NewJoinerId   int
+		Note          string
+		Status        string
+		CreatedBy     string
+		CreatedByName string
+		CreatedAt     time.Time
+	}
+	CoolNewJoinerNoteResponse struct {
+		Type          string    `json:"type" example:"coolNewJoinerNote"`
+		ID            int       `json:"id" example:"1"`
+		NewJoinerId   int       `json:"newJoinerId" example:"1"`
+		Note          string    `json:"note" example:"Called, will join this Friday"`
+		Status        string    `json:"status" example:"followed"`
+		CreatedBy     string    `json:"createdBy" example:"202401010001"`
+		CreatedByName string    `json:"createdByName" example:"Jane Doe"`
+		CreatedAt     time.Time `json:"createdAt" example:"2023-01-01T00:00:00Z"`
+	}
+)
+
+type (
+	// ConvertCoolNewJoinerRequest makes a user of a new joiner, who sets their password through forgot password
+	ConvertCoolNewJoinerRequest struct {
+		Email     string   `json:"email" validate:"omitempty,email" example:"john@gmail.com"`
+		UserTypes []string `json:"userTypes" validate:"required,min=1" example:"member"`
+	}
+	ConvertCoolNewJoinerResponse struct {
+		Type           string `json:"type" example:"user"`
+		NewJoinerId    int    `json:"newJoinerId" example:"1"`
+		CommunityId    string `json:"communityId" example:"202401010002"`
+		Name           string `json:"name" example:"John Doe"`
+		PhoneNumber    string `json:"phoneNumber" example:"+628123456789"`
+		Email          string `json:"email" example:"john@gmail.com"`
+		CoolId         int    `json:"coolId" example:"1"`
+		IsExistingUser bool   `json:"isExistingUser"`
+	}
+)
@@ -46,6 +46,22 @@ var (
 	ErrorAlreadyCancelled            = errors.New("your registration is already cancelled")
 	ErrorForbiddenStatus             = errors.New("you are not allowed to use this status on this event")
 	ErrorReasonEmpty                 = errors.New("reason cannot be empty when you entered for permission")
+	ErrorRegistrationWaitlisted      = errors.New("your registration is still on the waitlist, wait until a seat is available")
+	ErrorAlreadyCheckedOut           = errors.New("your registration is already checked out")
+	ErrorCheckOutNotAllowed          = errors.New("this event does not use check out")
+	ErrorNotCheckedInYet             = errors.New("your registration has to be checked in before checking out")
+	ErrorCheckOutBeforeCheckIn       = errors.New("check out time cannot be before the check in time")
+	ErrorRegistrationChanged         = errors.New("the registration was updated by someone else in the meantime, try again")
+	ErrorInvalidRecurrence           = errors.New("recurrence should be a valid rule, e.g. FREQ=WEEKLY;BYDAY=SU")
+	ErrorEventNotRecurring           = errors.New("event is not recurring")
+	ErrorNotAnOccurrence             = errors.New("the time given is not an occurrence of the event recurrence")
+	ErrorOccurrenceAlreadyGenerated  = errors.New("the occurrence already has an instance, update the instance instead")
+	ErrorSeatsBelowBooked            = errors.New("total seats cannot be less than the seats already booked")
+	ErrorRegisterFlowLocked          = errors.New("register flow cannot be changed once the session has registrations")
+	ErrorEventAlreadyCancelled       = errors.New("the event or session is already cancelled")
+	ErrorInstanceOutsideEvent        = errors.New("a session has to take place within the time of its event")
+	ErrorAnswerRequired              = errors.New("a required question has not been answered")
+	ErrorInvalidAnswer               = errors.New("an answer does not match the type, options or rules of its question")
 
 	// Google Error
 	ErrorFetchGoogle = errors.New("error while retrieving user from google")
@@ -57,6 +73,15 @@ var (
 	ErrorEmptyToken     = errors.New("token is empty")
 	ErrorForbiddenRole  = errors.New("you are not allowed to access this feature")
 	ErrorLoggedOut      = errors.New("you are already logged out")
+	ErrorLoginTooSoon   = errors.New("too many failed logins, please wait a moment before trying again")
+	ErrorAccountLocked  = errors.New("too many failed logins, the account is locked for a while. please try again later or contact an admin")
+
+	// Ticket Error
+	ErrorInvalidTicket       = errors.New("ticket is invalid")
+	ErrorExpiredTicket       = errors.New("ticket is expired")
+	ErrorTicketNotYetValid   = errors.New("ticket cannot be used yet")
+	ErrorTicketRequired      = errors.New("the ticket of the registration is required to check in or out")
+	ErrorTicketNotConfigured = errors.New("tickets are not configured, auth.ticket_secret is missing")
 
 	// API Auth Error
 	ErrorInvalidAPIKey = errors.New("api key is invalid")
@@ -68,10 +93,11 @@ var (
 
 	// Idempotency Error
 	ErrorEmptyRequestID     = errors.New("request id is empty")
-	ErrorProcessedRequestID = errors.New("request id has already been processed")
+	ErrorProcessedRequestID = errors.New("a request with this request id is still being processed, please try again in a moment")
+	ErrorReusedRequestID    = errors.New("request id has already been used for a different request")
 
 	// Rate Limiter Error
-	ErrorRateLimiterExceeds = errors.New("too much input, please try again later")
+	ErrorRateLimiterExceeds = errors.New("too many requests, please try again later")
 
 	// User Error
 	ErrorDidNotFillKKJNumber   = errors.New("please input the kkj number if you input jemaat id")
@@ -80,8 +106,25 @@ var (
 
 	// Update User Error
 	ErrorDifferentCommunityId   = errors.New("you cannot take action with this different account from your account")
+	ErrorOutOfCampusScope       = errors.New("you cannot take action on a campus you do not administer")
+	ErrorInvalidResetToken      = errors.New("reset token is invalid, expired or already used")
+	ErrorResetUnavailable       = errors.New("password reset cannot be sent to this identifier, no sender is configured for it")
 	ErrorConflictRelationDelete = errors.New("its not allowed to place the same user relation in update and delete")
 
+	// Cool Error
+	ErrorAlreadyCoolMember      = errors.New("user is already a member of a cool, transfer them instead")
+	ErrorCoolMeetingCancelled   = errors.New("the meeting is cancelled")
+	ErrorCoolMeetingNotStarted  = errors.New("attendance can only be marked once the meeting has started")
+	ErrorNotCoolMemberAtMeeting = errors.New("user was not a member of the cool when the meeting started")
+	ErrorCoolNotRecurring       = errors.New("cool has no meeting rule")
+	ErrorNotCoolFollowUp        = errors.New("follow up has to be a leader, core team member or facilitator of the cool")
+	ErrorCoolOutsideCampus      = errors.New("new joiner can only be assigned to a cool of their campus")
+	ErrorNewJoinerNotAssigned   = errors.New("new joiner has to be assigned to a cool first")
+	ErrorNewJoinerConverted     = errors.New("new joiner has already joined as a user")
+	ErrorNotCoolMember          = errors.New("user is not a member of the cool")
+	ErrorCoolWithoutLeader      = errors.New("cool has to keep at least one leader")
+	ErrorCoolInactive           = errors.New("cool is not active")
+
 	// Time error
 	ErrorStartDateLater = errors.New("start time cannot be later than end time")
 
@@ -276,6 +319,36 @@ func ErrorMapping(err error) Response {
 			Status:  "FORBIDDEN_REGISTRATION",
 			Message: err.Error(),
 		}
+	case ErrorLoginTooSoon:
+		return Response{
+			Code:    http.StatusTooManyRequests,
+			Status:  "TOO_MANY_ATTEMPTS",
+			Message: err.Error(),
+		}
+	case ErrorProcessedRequestID:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "REQUEST_IN_PROGRESS",
+			Message: err.Error(),
+		}
+	case ErrorReusedRequestID:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "REQUEST_ID_REUSED",
+			Message: err.Error(),
+		}
+	case ErrorRateLimiterExceeds:
+		return Response{
+			Code:    http.StatusTooManyRequests,
+			Status:  "TOO_MANY_REQUESTS",
+			Message: err.Error(),
+		}
+	case ErrorAccountLocked:
+		return Response{
+			Code:    http.StatusLocked,
+			Status:  "ACCOUNT_LOCKED",
+			Message: err.Error(),
+		}
 	case ErrorLoggedOut:
 		return Response{
 			Code:    http.StatusUnauthorized,
@@ -372,12 +445,228 @@ func ErrorMapping(err error) Response {
 			Status:  "MISSING_FIELDS",
 			Message: err.Error(),
 		}
+	case ErrorRegistrationWaitlisted:
+		return Response{
+			Code:    http.StatusForbidden,
+			Status:  "WAITLISTED_REGISTRATION",
+			Message: err.Error(),
+		}
+	case ErrorAlreadyCheckedOut:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "ALREADY_UPDATED",
+			Message: err.Error(),
+		}
+	case ErrorRegistrationChanged:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "DATA_CHANGED",
+			Message: err.Error(),
+		}
+	case ErrorCheckOutNotAllowed:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "FORBIDDEN_STATUS",
+			Message: err.Error(),
+		}
+	case ErrorNotCheckedInYet:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "NOT_CHECKED_IN",
+			Message: err.Error(),
+		}
+	case ErrorCheckOutBeforeCheckIn:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "INVALID_CHECK_OUT_TIME",
+			Message: err.Error(),
+		}
+	case ErrorInvalidTicket:
+		return Response{
+			Code:    http.StatusUnauthorized,
+			Status:  "INVALID_TICKET",
+			Message: err.Error(),
+		}
+	case ErrorExpiredTicket:
+		return Response{
+			Code:    http.StatusUnauthorized,
+			Status:  "EXPIRED_TICKET",
+			Message: err.Error(),
+		}
+	case ErrorTicketNotYetValid:
+		return Response{
+			Code:    http.StatusForbidden,
+			Status:  "TICKET_NOT_YET_VALID",
+			Message: err.Error(),
+		}
+	case ErrorTicketRequired:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "MISSING_FIELDS",
+			Message: err.Error(),
+		}
+	case ErrorTicketNotConfigured:
+		return Response{
+			Code:    http.StatusInternalServerError,
+			Status:  "CONFIG_ERROR",
+			Message: err.Error(),
+		}
+	case ErrorInvalidRecurrence:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "INVALID_RECURRENCE",
+			Message: err.Error(),
+		}
+	case ErrorEventNotRecurring:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "NOT_RECURRING",
+			Message: err.Error(),
+		}
+	case ErrorNotAnOccurrence:
+		return Response{
+			Code:    http.StatusNotFound,
+			Status:  "OCCURRENCE_NOT_FOUND",
+			Message: err.Error(),
+		}
+	case ErrorOccurrenceAlreadyGenerated:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "ALREADY_GENERATED",
+			Message: err.Error(),
+		}
+	case ErrorSeatsBelowBooked:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "INVALID_VALUES",
+			Message: err.Error(),
+		}
+	case ErrorRegisterFlowLocked:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "ALREADY_REGISTERED",
+			Message: err.Error(),
+		}
+	case ErrorEventAlreadyCancelled:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "ALREADY_UPDATED",
+			Message: err.Error(),
+		}
+	case ErrorInstanceOutsideEvent:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "INVALID_TIME_WINDOW",
+			Message: err.Error(),
+		}
+	case ErrorAnswerRequired:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "MISSING_FIELDS",
+			Message: err.Error(),
+		}
+	case ErrorInvalidAnswer:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "INVALID_ANSWER",
+			Message: err.Error(),
+		}
+	case ErrorAlreadyCoolMember:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "ALREADY_COOL_MEMBER",
+			Message: err.Error(),
+		}
+	case ErrorCoolMeetingCancelled:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "MEETING_CANCELLED",
+			Message: err.Error(),
+		}
+	case ErrorCoolMeetingNotStarted:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "MEETING_NOT_STARTED",
+			Message: err.Error(),
+		}
+	case ErrorNotCoolMemberAtMeeting:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "NOT_COOL_MEMBER",
+			Message: err.Error(),
+		}
+	case ErrorCoolNotRecurring:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "NOT_RECURRING",
+			Message: err.Error(),
+		}
+	case ErrorNotCoolFollowUp:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "NOT_COOL_FOLLOW_UP",
+			Message: err.Error(),
+		}
+	case ErrorCoolOutsideCampus:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "OUTSIDE_CAMPUS",
+			Message: err.Error(),
+		}
+	case ErrorNewJoinerNotAssigned:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "NOT_ASSIGNED",
+			Message: err.Error(),
+		}
+	case ErrorNewJoinerConverted:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "ALREADY_CONVERTED",
+			Message: err.Error(),
+		}
+	case ErrorNotCoolMember:
+		return Response{
+			Code:    http.StatusUnprocessableEntity,
+			Status:  "NOT_COOL_MEMBER",
+			Message: err.Error(),
+		}
+	case ErrorCoolWithoutLeader:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "NO_COOL_LEADER",
+			Message: err.Error(),
+		}
+	case ErrorCoolInactive:
+		return Response{
+			Code:    http.StatusConflict,
+			Status:  "COOL_INACTIVE",
+			Message: err.Error(),
+		}
+	case ErrorOutOfCampusScope:
+		return Response{
+			Code:    http.StatusForbidden,
+			Status:  "OUT_OF_CAMPUS_SCOPE",
+			Message: err.Error(),
+		}
 	case ErrorDifferentCommunityId:
 		return Response{
 			Code:    http.StatusForbidden,
 			Status:  "FORBIDDEN_ACTION",
 			Message: err.Error(),
 		}
+	case ErrorInvalidResetToken:
+		return Response{
+			Code:    http.StatusBadRequest,
+			Status:  "INVALID_TOKEN",
+			Message: err.Error(),
+		}
+	case ErrorResetUnavailable:
+		return Response{
+			Code:    http.StatusServiceUnavailable,
+			Status:  "SERVICE_UNAVAILABLE",
+			Message: err.Error(),
+		}
 	case ErrorConflictRelationDelete:
 		return Response{
 			Code:    http.StatusConflict,
@@ -3,18 +3,48 @@ package models
 import (
 	"database/sql"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"strings"
 	"time"
 )
 
-type Answer struct {
-	ID             uuid.UUID    `json:"id"`
-	QuestionID     uuid.UUID    `json:"question_id"`
-	RegistrationId uuid.UUID    `json:"registration_id"`
-	Value          string       `json:"value"`  // For single value answers (text, radio, select)
-	Values         []string     `json:"values"` // For multiple value answers (checkboxes)
-	CreatedAt      time.Time    `json:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at"`
-	DeletedAt      sql.NullTime `json:"deleted_at,omitempty"`
+var TYPE_EVENT_ANSWER = "eventAnswer"
+
+type EventAnswer struct {
+	ID             uuid.UUID      `json:"id"`
+	QuestionID     uuid.UUID      `json:"question_id"`
+	RegistrationId uuid.UUID      `json:"registration_id"`
+	Value          string         `json:"value"`                     // For single value answers (text, radio, select)
+	Values         pq.StringArray `json:"values" gorm:"type:text[]"` // For multiple value answers (checkboxes)
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      sql.NullTime   `json:"deleted_at,omitempty"`
+}
+
+func (a *EventAnswer) ToResponse(question *EventQuestion) CreateAnswerResponse {
+	return CreateAnswerResponse{
+		Type: TYPE_EVENT_ANSWER,
+		ID:   a.ID,
+		Question: QuestionResponseForAnswer{
+			Type:         TYPE_EVENT_QUESTION,
+			ID:           question.ID,
+			Question:     question.Question,
+			Description:  question.Description,
+			QuestionType: string(question.Type),
+			Options:      question.Options,
+		},
+		Value:  a.Value,
+		Values: strings.Join(a.Values, ","),
+	}
+}
+
+// FormatAnswer joins the values of a multiple choice answer so it fits in a single cell of an export.
+func (a *EventAnswer) FormatAnswer() string {
+	if len(a.Values) > 0 {
+		return strings.Join(a.Values, ", ")
+	}
+
+	return a.Value
 }
 
 type (
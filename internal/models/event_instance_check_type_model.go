@@ -0,0 +1,31 @@
+package models
+
+type CheckType int32
+
+const (
+	CHECK_TYPE_CHECK_IN CheckType = iota
+	CHECK_TYPE_CHECK_OUT
+	CHECK_TYPE_BOTH
+	CHECK_TYPE_NONE
+)
+
+const (
+	CheckTypeCheckIn  = "check-in"
+	CheckTypeCheckOut = "check-out"
+	CheckTypeBoth     = "both"
+	CheckTypeNone     = "none"
+)
+
+var (
+	MapCheckType = map[CheckType]string{
+		CHECK_TYPE_CHECK_IN:  CheckTypeCheckIn,
+		CHECK_TYPE_CHECK_OUT: CheckTypeCheckOut,
+		CHECK_TYPE_BOTH:      CheckTypeBoth,
+		CHECK_TYPE_NONE:      CheckTypeNone,
+	}
+)
+
+// IsCheckOutAllowed reports whether attendees of an instance with the given check type can be checked out.
+func IsCheckOutAllowed(checkType string) bool {
+	return checkType == MapCheckType[CHECK_TYPE_CHECK_OUT] || checkType == MapCheckType[CHECK_TYPE_BOTH]
+}
@@ -24,17 +24,46 @@ type EventInstance struct {
 	MaxPerTransaction int
 	IsOnePerAccount   bool
 	IsOnePerTicket    bool
+	IsWaitlistEnabled bool
 	RegisterFlow      string
 	CheckType         string
 	TotalSeats        int
 	BookedSeats       int
 	ScannedSeats      int
+	CheckedOutSeats   int
 	Status            string
+	RecurrenceAt      sql.NullTime
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeletedAt         sql.NullTime
 }
 
+func (ei *EventInstance) ToCreateResponse() CreateInstanceResponse {
+	return CreateInstanceResponse{
+		Type:              TYPE_EVENT_INSTANCE,
+		InstanceCode:      ei.Code,
+		EventCode:         ei.EventCode,
+		Title:             ei.Title,
+		Description:       ei.Description,
+		InstanceStartAt:   ei.InstanceStartAt,
+		InstanceEndAt:     ei.InstanceEndAt,
+		RegisterStartAt:   ei.RegisterStartAt,
+		RegisterEndAt:     ei.RegisterEndAt,
+		AllowVerifyAt:     ei.AllowVerifyAt,
+		DisallowVerifyAt:  ei.DisallowVerifyAt,
+		LocationType:      ei.LocationType,
+		LocationName:      ei.LocationName,
+		MaxPerTransaction: ei.MaxPerTransaction,
+		IsOnePerAccount:   ei.IsOnePerAccount,
+		IsOnePerTicket:    ei.IsOnePerTicket,
+		IsWaitlistEnabled: ei.IsWaitlistEnabled,
+		RegisterFlow:      ei.RegisterFlow,
+		CheckType:         ei.CheckType,
+		TotalSeats:        ei.TotalSeats,
+		Status:            ei.Status,
+	}
+}
+
 func (ir *CreateInstanceResponse) ToResponse() *CreateInstanceResponse {
 	return &CreateInstanceResponse{
 		Type:              ir.Type,
@@ -53,6 +82,7 @@ func (ir *CreateInstanceResponse) ToResponse() *CreateInstanceResponse {
 		MaxPerTransaction: ir.MaxPerTransaction,
 		IsOnePerAccount:   ir.IsOnePerAccount,
 		IsOnePerTicket:    ir.IsOnePerTicket,
+		IsWaitlistEnabled: ir.IsWaitlistEnabled,
 		RegisterFlow:      ir.RegisterFlow,
 		CheckType:         ir.CheckType,
 		TotalSeats:        ir.TotalSeats,
@@ -75,6 +105,7 @@ type (
 		MaxPerTransaction int    `json:"maxPerTransaction"`
 		IsOnePerAccount   bool   `json:"isOnePerAccount"`
 		IsOnePerTicket    bool   `json:"isOnePerTicket"`
+		IsWaitlistEnabled bool   `json:"isWaitlistEnabled"`
 		RegisterFlow      string `json:"registerFlow" validate:"oneof=personal-qr event-qr both-qr none"`
 		CheckType         string `json:"checkType" validate:"omitempty,oneof=check-in check-out both none"`
 		TotalSeats        int    `json:"totalSeats"`
@@ -96,6 +127,7 @@ type (
 		MaxPerTransaction int       `json:"maxPerTransaction,omitempty"`
 		IsOnePerAccount   bool      `json:"isOnePerAccount"`
 		IsOnePerTicket    bool      `json:"isOnePerTicket"`
+		IsWaitlistEnabled bool      `json:"isWaitlistEnabled"`
 		RegisterFlow      string    `json:"registerFlow"`
 		TotalSeats        int       `json:"totalSeats,omitempty"`
 		CheckType         string    `json:"checkType,omitempty"`
@@ -119,6 +151,7 @@ type GetInstanceByCodeDBOutput struct {
 	InstanceMaxPerTransaction int       `json:"instance_max_register"`
 	InstanceIsOnePerAccount   bool      `json:"instance_is_one_per_account"`
 	InstanceIsOnePerTicket    bool      `json:"instance_is_one_per_ticket"`
+	InstanceIsWaitlistEnabled bool      `json:"instance_is_waitlist_enabled"`
 	InstanceRegisterFlow      string    `json:"instance_register_flow"`
 	InstanceCheckType         string    `json:"instance_check_type"`
 	InstanceTotalSeats        int       `json:"instance_total_seats"`
@@ -132,11 +165,14 @@ type GetSeatsAndNamesByInstanceCodeDBOutput struct {
 	TotalSeats               int       `json:"total_seats"`
 	BookedSeats              int       `json:"booked_seats"`
 	ScannedSeats             int       `json:"scanned_seats"`
+	CheckedOutSeats          int       `json:"checked_out_seats"`
+	CheckType                string    `json:"check_type"`
 	EventInstanceTitle       string    `json:"event_instance_title"`
 	EventTitle               string    `json:"event_title"`
 	TotalRemainingSeats      int       `json:"total_remaining_seats"`
 	InstanceAllowVerifyAt    time.Time `json:"instance_allow_verify_at"`
 	InstanceDisallowVerifyAt time.Time `json:"instance_disallow_verify_at"`
+	IsWaitlistEnabled        bool      `json:"is_waitlist_enabled"`
 }
 
 type (
@@ -155,9 +191,56 @@ type (
 		MaxPerTransaction int    `json:"maxPerTransaction"`
 		IsOnePerAccount   bool   `json:"isOnePerAccount"`
 		IsOnePerTicket    bool   `json:"isOnePerTicket"`
+		IsWaitlistEnabled bool   `json:"isWaitlistEnabled"`
 		RegisterFlow      string `json:"registerFlow" validate:"oneof=personal-qr event-qr both-qr none"`
 		CheckType         string `json:"checkType" validate:"omitempty,oneof=check-in check-out both none"`
 		TotalSeats        int    `json:"totalSeats"`
 		IsUpdateEventTime bool   `json:"isUpdateEventTime"`
 	}
 )
+
+// UpdateInstanceRequest only changes the fields that are sent, so every field is optional
+type (
+	UpdateInstanceRequest struct {
+		Code              string  `json:"-" validate:"required"`
+		Title             *string `json:"title" validate:"omitempty,min=1"`
+		Description       *string `json:"description"`
+		InstanceStartAt   *string `json:"instanceStartAt"`
+		InstanceEndAt     *string `json:"instanceEndAt"`
+		RegisterStartAt   *string `json:"registerStartAt"`
+		RegisterEndAt     *string `json:"registerEndAt"`
+		AllowVerifyAt     *string `json:"allowVerifyAt"`
+		DisallowVerifyAt  *string `json:"disallowVerifyAt"`
+		LocationType      *string `json:"locationType" validate:"omitempty,oneof=online onsite hybrid"`
+		LocationName      *string `json:"locationName" validate:"omitempty,min=1"`
+		MaxPerTransaction *int    `json:"maxPerTransaction" validate:"omitempty,min=0"`
+		IsOnePerAccount   *bool   `json:"isOnePerAccount"`
+		IsOnePerTicket    *bool   `json:"isOnePerTicket"`
+		IsWaitlistEnabled *bool   `json:"isWaitlistEnabled"`
+		RegisterFlow      *string `json:"registerFlow" validate:"omitempty,oneof=personal-qr event-qr both-qr none"`
+		CheckType         *string `json:"checkType" validate:"omitempty,oneof=check-in check-out both none"`
+		TotalSeats        *int    `json:"totalSeats" validate:"omitempty,min=0"`
+	}
+	UpdateInstanceResponse struct {
+		CreateInstanceResponse
+		BookedSeats int                                          `json:"bookedSeats"`
+		Promoted    []CreateOtherEventRegistrationRecordResponse `json:"promoted,omitempty"`
+		Cancelled   []AffectedRegistrationResponse               `json:"cancelled,omitempty"`
+	}
+)
+
+type (
+	CancelInstanceParameter struct {
+		Code   string `json:"-" validate:"required"`
+		Reason string `query:"reason" validate:"omitempty,max=255"`
+	}
+	CancelInstanceResponse struct {
+		Type          string                         `json:"type"`
+		InstanceCode  string                         `json:"instanceCode"`
+		EventCode     string                         `json:"eventCode"`
+		Title         string                         `json:"title"`
+		Status        string                         `json:"status" example:"inactive"`
+		TotalAffected int                            `json:"totalAffected"`
+		Affected      []AffectedRegistrationResponse `json:"affected"`
+	}
+)
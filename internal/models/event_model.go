@@ -107,6 +107,87 @@ type (
 	}
 )
 
+func (e *Event) ToUpdateResponse() *UpdateEventResponse {
+	return &UpdateEventResponse{
+		Type:               TYPE_EVENT,
+		Code:               e.Code,
+		Title:              e.Title,
+		Topics:             e.Topics,
+		Description:        e.Description,
+		TermsAndConditions: e.TermsAndConditions,
+		AllowedFor:         e.AllowedFor,
+		AllowedUsers:       e.AllowedUsers,
+		AllowedRoles:       e.AllowedRoles,
+		AllowedCampuses:    e.AllowedCampuses,
+		IsRecurring:        e.IsRecurring,
+		Recurrence:         e.Recurrence,
+		EventStartAt:       e.EventStartAt,
+		EventEndAt:         e.EventEndAt,
+		RegisterStartAt:    e.RegisterStartAt,
+		RegisterEndAt:      e.RegisterEndAt,
+		LocationType:       e.LocationType,
+		LocationName:       e.LocationName,
+		Status:             e.Status,
+	}
+}
+
+// UpdateEventRequest only changes the fields that are sent, so every field is optional
+type (
+	UpdateEventRequest struct {
+		Code               string   `json:"-" validate:"required,min=7,max=7"`
+		Title              *string  `json:"name" validate:"omitempty,min=1"`
+		Topics             []string `json:"topics"`
+		Description        *string  `json:"description"`
+		TermsAndConditions *string  `json:"termsAndConditions"`
+		AllowedFor         *string  `json:"allowedFor" validate:"omitempty,oneof=public private"`
+		AllowedUsers       []string `json:"allowedUsers"`
+		AllowedRoles       []string `json:"allowedRoles"`
+		AllowedCampuses    []string `json:"allowedCampuses" validate:"omitempty,dive,min=3"`
+		EventStartAt       *string  `json:"eventStartAt"`
+		EventEndAt         *string  `json:"eventEndAt"`
+		RegisterStartAt    *string  `json:"registerStartAt"`
+		RegisterEndAt      *string  `json:"registerEndAt"`
+		LocationType       *string  `json:"locationType" validate:"omitempty,oneof=online onsite hybrid"`
+		LocationName       *string  `json:"locationName" validate:"omitempty,min=1"`
+	}
+	UpdateEventResponse struct {
+		Type               string    `json:"type" example:"event"`
+		Code               string    `json:"code" example:"bhfe382"`
+		Title              string    `json:"title" example:"Homebase"`
+		Topics             []string  `json:"topics"`
+		Description        string    `json:"description" example:"This event blabla"`
+		TermsAndConditions string    `json:"termsAndConditions" example:"This event blabla"`
+		AllowedFor         string    `json:"allowedFor" example:"public"`
+		AllowedUsers       []string  `json:"allowedUsers,omitempty"`
+		AllowedRoles       []string  `json:"allowedRoles,omitempty"`
+		AllowedCampuses    []string  `json:"allowedCampuses,omitempty"`
+		IsRecurring        bool      `json:"isRecurring" example:"true"`
+		Recurrence         string    `json:"recurrence,omitempty" example:"monthly"`
+		EventStartAt       time.Time `json:"eventStartAt,omitempty" example:""`
+		EventEndAt         time.Time `json:"eventEndAt,omitempty" example:""`
+		RegisterStartAt    time.Time `json:"registerStartAt,omitempty" example:""`
+		RegisterEndAt      time.Time `json:"registerEndAt,omitempty" example:""`
+		LocationType       string    `json:"locationType" example:"offline"`
+		LocationName       string    `json:"locationName" example:"PIOT 6 Lt. 6"`
+		Status             string    `json:"status,omitempty" example:"active"`
+	}
+)
+
+type (
+	CancelEventParameter struct {
+		Code   string `json:"-" validate:"required,min=7,max=7"`
+		Reason string `query:"reason" validate:"omitempty,max=255"`
+	}
+	CancelEventResponse struct {
+		Type          string                   `json:"type" example:"event"`
+		Code          string                   `json:"code" example:"bhfe382"`
+		Title         string                   `json:"title" example:"Homebase"`
+		Status        string                   `json:"status" example:"inactive"`
+		TotalAffected int                      `json:"totalAffected" example:"10"`
+		Instances     []CancelInstanceResponse `json:"instances"`
+	}
+)
+
 func (e *GetAllEventsResponse) ToResponse() GetAllEventsResponse {
 	return GetAllEventsResponse{
 		Type:               TYPE_EVENT,
@@ -242,6 +323,7 @@ type (
 		InstanceMaxPerTransaction int       `json:"instance_max_register"`
 		InstanceIsOnePerAccount   bool      `json:"instance_is_one_per_account"`
 		InstanceIsOnePerTicket    bool      `json:"instance_is_one_per_ticket"`
+		InstanceIsWaitlistEnabled bool      `json:"instance_is_waitlist_enabled"`
 		InstanceRegisterFlow      string    `json:"instance_register_flow"`
 		InstanceCheckType         string    `json:"instance_check_type"`
 		InstanceTotalSeats        int       `json:"instance_total_seats"`
@@ -293,6 +375,7 @@ type (
 		MaxPerTransaction   int       `json:"maxPerTransaction,omitempty"`
 		IsOnePerAccount     bool      `json:"isOnePerAccount"`
 		IsOnePerTicket      bool      `json:"isOnePerTicket"`
+		IsWaitlistEnabled   bool      `json:"isWaitlistEnabled"`
 		RegisterFlow        string    `json:"registerFlow"`
 		CheckType           string    `json:"checkType"`
 		TotalSeats          int       `json:"totalSeats" example:"0"`
@@ -322,6 +405,8 @@ type (
 		InstanceLocationType           string
 		InstanceLocationName           string
 		InstanceStatus                 string
+		InstanceAllowVerifyAt          time.Time
+		InstanceDisallowVerifyAt       time.Time
 		RegistrationRecordID           uuid.UUID
 		RegistrationRecordName         string
 		RegistrationRecordIdentifier   string
@@ -374,6 +459,7 @@ type (
 		VerifiedAt         string    `json:"verifiedAt,omitempty"`
 		IsPersonalQr       bool      `json:"isPersonalQr"`
 		RegistrationStatus string    `json:"registrationStatus"`
+		Ticket             string    `json:"ticket,omitempty"`
 	}
 )
 
@@ -399,35 +485,41 @@ type (
 
 func (e GetEventSummaryDBOutput) ToResponse() *GetEventSummaryResponse {
 	return &GetEventSummaryResponse{
-		Type:              TYPE_EVENT,
-		Code:              e.EventCode,
-		Title:             e.EventTitle,
-		AllowedFor:        e.EventAllowedFor,
-		AllowedRoles:      e.EventAllowedRoles,
-		AllowedUsers:      e.EventAllowedUsers,
-		AllowedCampuses:   e.EventAllowedCampuses,
-		TotalBookedSeats:  e.TotalBookedSeats,
-		TotalScannedSeats: e.TotalScannedSeats,
-		TotalUsers:        e.TotalUsers,
-		Status:            e.EventStatus,
+		Type:                 TYPE_EVENT,
+		Code:                 e.EventCode,
+		Title:                e.EventTitle,
+		AllowedFor:           e.EventAllowedFor,
+		AllowedRoles:         e.EventAllowedRoles,
+		AllowedUsers:         e.EventAllowedUsers,
+		AllowedCampuses:      e.EventAllowedCampuses,
+		TotalBookedSeats:     e.TotalBookedSeats,
+		TotalScannedSeats:    e.TotalScannedSeats,
+		TotalCheckedOutSeats: e.TotalCheckedOutSeats,
+		TotalUsers:           e.TotalUsers,
+		Status:               e.EventStatus,
 	}
 }
 
 func (e GetInstanceSummaryDBOutput) ToResponse() GetInstanceSummaryResponse {
 	return GetInstanceSummaryResponse{
-		Type:                TYPE_EVENT_INSTANCE,
-		EventCode:           e.InstanceEventCode,
-		Code:                e.InstanceCode,
-		Title:               e.InstanceTitle,
-		RegisterFlow:        e.InstanceRegisterFlow,
-		CheckType:           e.InstanceCheckType,
-		TotalSeats:          e.InstanceTotalSeats,
-		BookedSeats:         e.InstanceBookedSeats,
-		ScannedSeats:        e.InstanceScannedSeats,
-		TotalRemainingSeats: e.TotalRemainingSeats,
-		MaxPerTransaction:   e.InstanceMaxPerTransaction,
-		AttendPercentage:    e.AttendancePercentage,
-		Status:              e.InstanceStatus,
+		Type:                   TYPE_EVENT_INSTANCE,
+		EventCode:              e.InstanceEventCode,
+		Code:                   e.InstanceCode,
+		Title:                  e.InstanceTitle,
+		RegisterFlow:           e.InstanceRegisterFlow,
+		CheckType:              e.InstanceCheckType,
+		TotalSeats:             e.InstanceTotalSeats,
+		BookedSeats:            e.InstanceBookedSeats,
+		ScannedSeats:           e.InstanceScannedSeats,
+		CheckedOutSeats:        e.InstanceCheckedOutSeats,
+		TotalRemainingSeats:    e.TotalRemainingSeats,
+		MaxPerTransaction:      e.InstanceMaxPerTransaction,
+		AttendPercentage:       e.AttendancePercentage,
+		AverageAttendedMinutes: e.AverageAttendedMinutes,
+		MinAttendedMinutes:     e.MinAttendedMinutes,
+		MaxAttendedMinutes:     e.MaxAttendedMinutes,
+		FullSessionCount:       e.FullSessionCount,
+		Status:                 e.InstanceStatus,
 	}
 }
 
@@ -441,6 +533,7 @@ type (
 		EventAllowedCampuses pq.StringArray `gorm:"type:text[]"`
 		TotalBookedSeats     int
 		TotalScannedSeats    int
+		TotalCheckedOutSeats int
 		TotalUsers           int
 		EventStatus          string
 	}
@@ -453,38 +546,49 @@ type (
 		InstanceTotalSeats        int     `json:"instance_total_seats"`
 		InstanceBookedSeats       int     `json:"instance_booked_seats"`
 		InstanceScannedSeats      int     `json:"instance_scanned_seats"`
+		InstanceCheckedOutSeats   int     `json:"instance_checked_out_seats"`
 		InstanceMaxPerTransaction int     `json:"instance_max_per_transaction"`
 		InstanceStatus            string  `json:"instance_status"`
 		TotalRemainingSeats       int     `json:"total_remaining_seats"`
 		AttendancePercentage      float64 `json:"attendance_percentage"`
+		AverageAttendedMinutes    float64 `json:"average_attended_minutes"`
+		MinAttendedMinutes        float64 `json:"min_attended_minutes"`
+		MaxAttendedMinutes        float64 `json:"max_attended_minutes"`
+		FullSessionCount          int     `json:"full_session_count"`
 	}
 	GetEventSummaryResponse struct {
-		Type              string   `json:"type" example:"event"`
-		Code              string   `json:"code" example:"event-1"`
-		Title             string   `json:"title" example:"Event 1"`
-		AllowedFor        string   `json:"allowedFor" example:"volunteer"`
-		AllowedRoles      []string `json:"allowedRoles" example:"event-view-volunteer, event-edit-volunteer"`
-		AllowedUsers      []string `json:"allowedUsers" example:"user-1, user-2"`
-		AllowedCampuses   []string `json:"allowedCampuses" example:"BKS, BKT"`
-		TotalBookedSeats  int      `json:"totalBookedSeats" example:"3003"`
-		TotalScannedSeats int      `json:"totalScannedSeats" example:"309"`
-		TotalUsers        int      `json:"totalUsers" example:"309"`
-		Status            string   `json:"status" example:"active"`
+		Type                 string   `json:"type" example:"event"`
+		Code                 string   `json:"code" example:"event-1"`
+		Title                string   `json:"title" example:"Event 1"`
+		AllowedFor           string   `json:"allowedFor" example:"volunteer"`
+		AllowedRoles         []string `json:"allowedRoles" example:"event-view-volunteer, event-edit-volunteer"`
+		AllowedUsers         []string `json:"allowedUsers" example:"user-1, user-2"`
+		AllowedCampuses      []string `json:"allowedCampuses" example:"BKS, BKT"`
+		TotalBookedSeats     int      `json:"totalBookedSeats" example:"3003"`
+		TotalScannedSeats    int      `json:"totalScannedSeats" example:"309"`
+		TotalCheckedOutSeats int      `json:"totalCheckedOutSeats" example:"250"`
+		TotalUsers           int      `json:"totalUsers" example:"309"`
+		Status               string   `json:"status" example:"active"`
 	}
 	GetInstanceSummaryResponse struct {
-		Type                string  `json:"type" example:"instance"`
-		EventCode           string  `json:"eventCode" example:"event-1"`
-		Code                string  `json:"code" example:"instance-1"`
-		Title               string  `json:"title" example:"Instance 1"`
-		RegisterFlow        string  `json:"registerFlow" example:"online"`
-		CheckType           string  `json:"checkType" example:"online"`
-		TotalSeats          int     `json:"totalSeats" example:"100"`
-		BookedSeats         int     `json:"bookedSeats" example:"50"`
-		ScannedSeats        int     `json:"scannedSeats" example:"50"`
-		MaxPerTransaction   int     `json:"maxPerTransaction" example:"5"`
-		TotalRemainingSeats int     `json:"totalRemainingSeats" example:"50"`
-		AttendPercentage    float64 `json:"attendPercentage" example:"50.0"`
-		Status              string  `json:"status" example:"active"`
+		Type                   string  `json:"type" example:"instance"`
+		EventCode              string  `json:"eventCode" example:"event-1"`
+		Code                   string  `json:"code" example:"instance-1"`
+		Title                  string  `json:"title" example:"Instance 1"`
+		RegisterFlow           string  `json:"registerFlow" example:"online"`
+		CheckType              string  `json:"checkType" example:"online"`
+		TotalSeats             int     `json:"totalSeats" example:"100"`
+		BookedSeats            int     `json:"bookedSeats" example:"50"`
+		ScannedSeats           int     `json:"scannedSeats" example:"50"`
+		CheckedOutSeats        int     `json:"checkedOutSeats" example:"40"`
+		MaxPerTransaction      int     `json:"maxPerTransaction" example:"5"`
+		TotalRemainingSeats    int     `json:"totalRemainingSeats" example:"50"`
+		AttendPercentage       float64 `json:"attendPercentage" example:"50.0"`
+		AverageAttendedMinutes float64 `json:"averageAttendedMinutes" example:"95.5"`
+		MinAttendedMinutes     float64 `json:"minAttendedMinutes" example:"30"`
+		MaxAttendedMinutes     float64 `json:"maxAttendedMinutes" example:"120"`
+		FullSessionCount       int     `json:"fullSessionCount" example:"35"`
+		Status                 string  `json:"status" example:"active"`
 	}
 )
 
@@ -4,8 +4,14 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/lib/pq"
 	"go-community/internal/constants"
+	"regexp"
+	"slices"
+	"strconv"
 	"time"
+	"unicode/utf8"
 )
 
 var TYPE_EVENT_QUESTION = "eventQuestion"
@@ -13,12 +19,12 @@ var TYPE_EVENT_QUESTION = "eventQuestion"
 type EventQuestion struct {
 	ID                    uuid.UUID              `json:"id"`
 	EventCode             string                 `json:"event_code"`
-	InstanceCode          []string               `json:"instance_code"` // Optional - if null, it's a general event question
+	InstanceCode          pq.StringArray         `json:"instance_code" gorm:"type:text[]"` // Optional - if null, it's a general event question
 	Question              string                 `json:"question"`
 	Description           string                 `json:"description"`
 	Type                  constants.QuestionType `json:"type"`
-	Options               []string               `json:"options,omitempty"` // For choice questions
-	IsMainRequired        bool                   `json:"is_required"`
+	Options               pq.StringArray         `json:"options,omitempty" gorm:"type:text[]"` // For choice questions
+	IsMainRequired        bool                   `json:"is_required" gorm:"column:is_required"`
 	IsRegistrantRequired  bool                   `json:"is_registrant_required"`
 	DisplayOrder          *int                   `json:"display_order"`
 	IsVisibleToRegistrant bool                   `json:"is_visible_to_registrant"`
@@ -30,8 +36,22 @@ type EventQuestion struct {
 }
 
 type QuestionRules struct {
-	MinString *int `json:"min_string,omitempty"` // Minimum length for string answers
-	MaxString *int `json:"max_string,omitempty"` // Maximum length for string answers
+	MinString     *int               `json:"min_string,omitempty"`     // Minimum length for string answers
+	MaxString     *int               `json:"max_string,omitempty"`     // Maximum length for string answers
+	MinNumber     *float64           `json:"min_number,omitempty"`     // Lowest value for number answers
+	MaxNumber     *float64           `json:"max_number,omitempty"`     // Highest value for number answers
+	Pattern       string             `json:"pattern,omitempty"`        // Regular expression string answers must match
+	MinDate       string             `json:"min_date,omitempty"`       // Earliest date for date answers, as 2006-01-02
+	MaxDate       string             `json:"max_date,omitempty"`       // Latest date for date answers, as 2006-01-02
+	MaxSelections *int               `json:"max_selections,omitempty"` // Most options a multiple choice answer can pick
+	ShowIf        *QuestionCondition `json:"show_if,omitempty"`        // Only ask the question when another one is answered a certain way
+}
+
+// QuestionCondition shows a question only when the answer to another question of the form is one of Values,
+// or for a multiple choice answer, picks at least one of them.
+type QuestionCondition struct {
+	QuestionId uuid.UUID `json:"question_id"`
+	Values     []string  `json:"values"`
 }
 
 type (
@@ -80,32 +100,77 @@ type (
 	}
 )
 
-func CreateQuestionSetup(qType constants.QuestionType, desc string, options []string, rules QuestionRules) (*constants.QuestionType, string, error) {
-	var questionType constants.QuestionType
+type (
+	GetQuestionsParameter struct {
+		EventCode    string `json:"-" validate:"required,min=7,max=7"`
+		InstanceCode string `query:"instanceCode" validate:"omitempty,min=15,max=15"`
+		IsRegistrant bool   `query:"registrant"`
+	}
+	UpdateQuestionRequest struct {
+		ID                    string         `json:"-" validate:"required,uuid"`
+		InstanceCode          []string       `json:"instanceCode" validate:"omitempty,dive,min=15,max=15"`
+		Question              *string        `json:"question" validate:"omitempty,min=1,max=255"`
+		Description           *string        `json:"description" validate:"omitempty,max=255"`
+		IsMainRequired        *bool          `json:"isMainRequired"`
+		IsRegistrantRequired  *bool          `json:"isRegistrantRequired"`
+		Options               []string       `json:"options" validate:"omitempty,dive,min=1,max=255"`
+		DisplayOrder          *int           `json:"displayOrder"`
+		IsVisibleToRegistrant *bool          `json:"isVisibleToRegistrant"`
+		Rules                 *QuestionRules `json:"rules"`
+		Status                *string        `json:"status" validate:"omitempty,oneof=active inactive"`
+	}
+	DeleteQuestionParameter struct {
+		ID string `json:"-" validate:"required,uuid"`
+	}
+)
+
+func (q *EventQuestion) ToResponse() CreateQuestionResponse {
+	return CreateQuestionResponse{
+		Type:                  TYPE_EVENT_QUESTION,
+		ID:                    q.ID,
+		EventCode:             q.EventCode,
+		InstanceCode:          q.InstanceCode,
+		Question:              q.Question,
+		Description:           q.Description,
+		QuestionType:          q.Type,
+		IsMainRequired:        q.IsMainRequired,
+		IsRegistrantRequired:  q.IsRegistrantRequired,
+		Options:               q.Options,
+		DisplayOrder:          q.DisplayOrder,
+		IsVisibleToRegistrant: q.IsVisibleToRegistrant,
+		Rules:                 q.Rules,
+		Status:                q.Status,
+	}
+}
+
+// IsAskedTo tells whether the question is on the form of the main registrant or of the people registered with them.
+// The main registrant is asked every question, the others only the ones visible to registrants.
+func (q *EventQuestion) IsAskedTo(isMain bool) bool {
+	return isMain || q.IsVisibleToRegistrant
+}
+
+func CreateQuestionSetup(qType constants.QuestionType, desc string, options []string, rules *QuestionRules) (*constants.QuestionType, string, error) {
+	questionType := qType
 	var description string
 
 	switch {
 	case qType == constants.QuestionTypeShortText || qType == constants.QuestionTypeLongText:
-		if desc != "" {
-			description = desc
+		var minString, maxString int
+		if rules != nil && rules.MinString != nil {
+			minString = *rules.MinString
 		}
-
-		// Validate min and max
-		if *rules.MinString < 0 {
-			return nil, "", fmt.Errorf("min cannot be below 0")
-		}
-		if *rules.MaxString < 0 {
-			return nil, "", fmt.Errorf("max cannot be below 0")
+		if rules != nil && rules.MaxString != nil {
+			maxString = *rules.MaxString
 		}
 
 		// Build description dynamically
 		switch {
-		case *rules.MinString != 0 && *rules.MaxString != 0:
-			description = fmt.Sprintf("minimum of %d maximum of %d", *rules.MinString, *rules.MaxString)
-		case *rules.MinString != 0:
-			description = fmt.Sprintf("minimum of %d", *rules.MinString)
-		case *rules.MaxString != 0:
-			description = fmt.Sprintf("maximum of %d", *rules.MaxString)
+		case minString != 0 && maxString != 0:
+			description = fmt.Sprintf("minimum of %d maximum of %d", minString, maxString)
+		case minString != 0:
+			description = fmt.Sprintf("minimum of %d", minString)
+		case maxString != 0:
+			description = fmt.Sprintf("maximum of %d", maxString)
 		default:
 			description = desc // No description if both are 0
 		}
@@ -143,10 +208,251 @@ func CreateQuestionSetup(qType constants.QuestionType, desc string, options []st
 		} else {
 			description = desc
 		}
+	case qType == constants.QuestionTypeNumber:
+		if desc == "" {
+			description = "Please enter a number. Example: 10"
+		} else {
+			description = desc
+		}
 	default:
-		questionType = qType
 		description = desc
 	}
 
 	return &questionType, description, nil
 }
+
+// Validate checks the rules fit the type and options of the question, reporting every problem on its own field under field.
+func (r *QuestionRules) Validate(qType constants.QuestionType, options []string, field string) error {
+	if r == nil {
+		return nil
+	}
+
+	var errs *multierror.Error
+	invalid := func(name string, message string) {
+		errs = multierror.Append(errs, ErrorValidateResponse{
+			Code:    "INVALID_RULES",
+			Field:   fmt.Sprintf("%s.%s", field, name),
+			Message: message,
+		})
+	}
+
+	isText := qType == constants.QuestionTypeShortText || qType == constants.QuestionTypeLongText
+	switch {
+	case (r.MinString != nil || r.MaxString != nil) && !isText:
+		invalid("min_string", "min_string and max_string are only for short_text or long_text questions")
+	case r.MinString != nil && *r.MinString < 0:
+		invalid("min_string", "min_string cannot be below 0")
+	case r.MaxString != nil && *r.MaxString < 0:
+		invalid("max_string", "max_string cannot be below 0")
+	case r.MinString != nil && r.MaxString != nil && *r.MaxString != 0 && *r.MinString > *r.MaxString:
+		invalid("max_string", "max_string cannot be below min_string")
+	}
+
+	switch {
+	case (r.MinNumber != nil || r.MaxNumber != nil) && qType != constants.QuestionTypeNumber:
+		invalid("min_number", "min_number and max_number are only for number questions")
+	case r.MinNumber != nil && r.MaxNumber != nil && *r.MinNumber > *r.MaxNumber:
+		invalid("max_number", "max_number cannot be below min_number")
+	}
+
+	if r.Pattern != "" {
+		if !isText {
+			invalid("pattern", "pattern is only for short_text or long_text questions")
+		} else if _, err := regexp.Compile(r.Pattern); err != nil {
+			invalid("pattern", "pattern is not a valid regular expression")
+		}
+	}
+
+	if r.MinDate != "" || r.MaxDate != "" {
+		minDate, minErr := time.Parse(answerDateLayout, r.MinDate)
+		maxDate, maxErr := time.Parse(answerDateLayout, r.MaxDate)
+		switch {
+		case qType != constants.QuestionTypeDate:
+			invalid("min_date", "min_date and max_date are only for date questions")
+		case r.MinDate != "" && minErr != nil:
+			invalid("min_date", "min_date must be formatted as 2006-01-02")
+		case r.MaxDate != "" && maxErr != nil:
+			invalid("max_date", "max_date must be formatted as 2006-01-02")
+		case r.MinDate != "" && r.MaxDate != "" && minDate.After(maxDate):
+			invalid("max_date", "max_date cannot be before min_date")
+		}
+	}
+
+	if r.MaxSelections != nil {
+		switch {
+		case qType != constants.QuestionTypeMultiple:
+			invalid("max_selections", "max_selections is only for multiple_choice questions")
+		case *r.MaxSelections < 1 || *r.MaxSelections > len(options):
+			invalid("max_selections", "max_selections must be between 1 and the number of options")
+		}
+	}
+
+	if r.ShowIf != nil {
+		if r.ShowIf.QuestionId == uuid.Nil {
+			invalid("show_if.question_id", "show_if.question_id is required")
+		}
+
+		if len(r.ShowIf.Values) == 0 {
+			invalid("show_if.values", "show_if.values cannot be empty")
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// IsMetBy tells whether an answer to the question the condition refers to shows the question holding the condition.
+func (c *QuestionCondition) IsMetBy(value string, values []string) bool {
+	if value != "" && slices.Contains(c.Values, value) {
+		return true
+	}
+
+	for _, v := range values {
+		if slices.Contains(c.Values, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsShown tells whether the question is on the form of a registrant, given their other answers.
+// A question is hidden when its show-if condition is not met, or when the question the condition refers to is hidden itself.
+func (q *EventQuestion) IsShown(isMain bool, questions map[uuid.UUID]EventQuestion, answers map[uuid.UUID]CreateAnswerRequest) bool {
+	question := *q
+	// Conditions can only chain through every question once, going further means they loop
+	for range len(questions) + 1 {
+		if !question.IsAskedTo(isMain) {
+			return false
+		}
+
+		if question.Rules == nil || question.Rules.ShowIf == nil {
+			return true
+		}
+
+		condition := question.Rules.ShowIf
+		answer, answered := answers[condition.QuestionId]
+		if !answered || !condition.IsMetBy(answer.Value, answer.Values) {
+			return false
+		}
+
+		if question, answered = questions[condition.QuestionId]; !answered {
+			return false
+		}
+	}
+
+	return false
+}
+
+const answerDateLayout = "2006-01-02"
+
+var (
+	answerEmailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	answerPhonePattern = regexp.MustCompile(`^(\+62|0)8\d{7,12}$`)
+)
+
+// ValidateAnswer checks an answer against the type, options and rules of the question.
+// Multiple choice questions are answered through values, every other type through value.
+// The error is an ErrorValidateResponse without a field, which the caller knows.
+func (q *EventQuestion) ValidateAnswer(value string, values []string) error {
+	rules := q.Rules
+	if rules == nil {
+		rules = &QuestionRules{}
+	}
+
+	if q.Type == constants.QuestionTypeMultiple {
+		if value != "" || len(values) == 0 {
+			return invalidAnswer("pick at least one option through values")
+		}
+
+		for i, v := range values {
+			if !slices.Contains(q.Options, v) {
+				return invalidAnswer("%q is not one of the options", v)
+			}
+
+			if slices.Contains(values[:i], v) {
+				return invalidAnswer("%q is picked more than once", v)
+			}
+		}
+
+		if rules.MaxSelections != nil && len(values) > *rules.MaxSelections {
+			return invalidAnswer("pick at most %d options", *rules.MaxSelections)
+		}
+
+		return nil
+	}
+
+	if len(values) != 0 || value == "" {
+		return invalidAnswer("answer through value")
+	}
+
+	switch q.Type {
+	case constants.QuestionTypeShortText, constants.QuestionTypeLongText:
+		length := utf8.RuneCountInString(value)
+		if rules.MinString != nil && length < *rules.MinString {
+			return invalidAnswer("must be at least %d characters", *rules.MinString)
+		}
+
+		if rules.MaxString != nil && *rules.MaxString != 0 && length > *rules.MaxString {
+			return invalidAnswer("must be at most %d characters", *rules.MaxString)
+		}
+
+		if rules.Pattern != "" {
+			pattern, err := regexp.Compile(rules.Pattern)
+			if err != nil || !pattern.MatchString(value) {
+				return invalidAnswer("does not match the expected format")
+			}
+		}
+	case constants.QuestionTypeSingle:
+		if !slices.Contains(q.Options, value) {
+			return invalidAnswer("%q is not one of the options", value)
+		}
+	case constants.QuestionTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return invalidAnswer("must be a number")
+		}
+
+		if rules.MinNumber != nil && number < *rules.MinNumber {
+			return invalidAnswer("must be at least %v", *rules.MinNumber)
+		}
+
+		if rules.MaxNumber != nil && number > *rules.MaxNumber {
+			return invalidAnswer("must be at most %v", *rules.MaxNumber)
+		}
+	case constants.QuestionTypeDate:
+		date, err := time.Parse(answerDateLayout, value)
+		if err != nil {
+			return invalidAnswer("must be a date formatted as 2006-01-02")
+		}
+
+		// The bounds are checked on create, so one that does not parse here is left out
+		if minDate, err := time.Parse(answerDateLayout, rules.MinDate); err == nil && date.Before(minDate) {
+			return invalidAnswer("cannot be before %s", rules.MinDate)
+		}
+
+		if maxDate, err := time.Parse(answerDateLayout, rules.MaxDate); err == nil && date.After(maxDate) {
+			return invalidAnswer("cannot be after %s", rules.MaxDate)
+		}
+	case constants.QuestionTypeTime:
+		if _, err := time.Parse("15:04", value); err != nil {
+			return invalidAnswer("must be a time formatted as 15:04")
+		}
+	case constants.QuestionTypeEmail:
+		if !answerEmailPattern.MatchString(value) {
+			return invalidAnswer("must be a valid email address")
+		}
+	case constants.QuestionTypePhone:
+		if !answerPhonePattern.MatchString(value) {
+			return invalidAnswer("must be a valid phone number")
+		}
+	}
+
+	return nil
+}
+
+func invalidAnswer(format string, args ...interface{}) error {
+	return ErrorValidateResponse{
+		Code:    "INVALID_ANSWER",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
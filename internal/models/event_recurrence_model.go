@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+var TYPE_EVENT_RECURRENCE = "eventRecurrence"
+
+type EventRecurrenceException struct {
+	ID              int
+	EventCode       string
+	OccurrenceAt    time.Time
+	IsSkipped       bool
+	Title           sql.NullString
+	LocationName    sql.NullString
+	InstanceStartAt sql.NullTime
+	InstanceEndAt   sql.NullTime
+	TotalSeats      sql.NullInt32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type OccurrenceStatus int32
+
+const (
+	OCCURRENCE_STATUS_SCHEDULED OccurrenceStatus = iota
+	OCCURRENCE_STATUS_OVERRIDDEN
+	OCCURRENCE_STATUS_SKIPPED
+	OCCURRENCE_STATUS_GENERATED
+)
+
+const (
+	OccurrenceStatusScheduled  = "scheduled"
+	OccurrenceStatusOverridden = "overridden"
+	OccurrenceStatusSkipped    = "skipped"
+	OccurrenceStatusGenerated  = "generated"
+)
+
+var (
+	MapOccurrenceStatus = map[OccurrenceStatus]string{
+		OCCURRENCE_STATUS_SCHEDULED:  OccurrenceStatusScheduled,
+		OCCURRENCE_STATUS_OVERRIDDEN: OccurrenceStatusOverridden,
+		OCCURRENCE_STATUS_SKIPPED:    OccurrenceStatusSkipped,
+		OCCURRENCE_STATUS_GENERATED:  OccurrenceStatusGenerated,
+	}
+)
+
+type (
+	GetRecurrencePreviewParameter struct {
+		EventCode string `json:"eventCode" validate:"required,min=7,max=7"`
+		From      string `query:"from"`
+		Until     string `query:"until"`
+	}
+	GetRecurrencePreviewResponse struct {
+		Type            string    `json:"type"`
+		EventCode       string    `json:"eventCode"`
+		OccurrenceAt    time.Time `json:"occurrenceAt"`
+		InstanceCode    string    `json:"instanceCode,omitempty"`
+		Title           string    `json:"title"`
+		LocationName    string    `json:"locationName"`
+		InstanceStartAt time.Time `json:"instanceStartAt"`
+		InstanceEndAt   time.Time `json:"instanceEndAt"`
+		TotalSeats      int       `json:"totalSeats"`
+		Status          string    `json:"status" example:"scheduled"`
+	}
+)
+
+type (
+	GenerateRecurrenceParameter struct {
+		EventCode string `json:"eventCode" validate:"required,min=7,max=7"`
+	}
+)
+
+type (
+	UpsertRecurrenceExceptionRequest struct {
+		EventCode       string `json:"-" validate:"required,min=7,max=7"`
+		OccurrenceAt    string `json:"occurrenceAt" validate:"required"`
+		IsSkipped       bool   `json:"isSkipped"`
+		Title           string `json:"title"`
+		LocationName    string `json:"locationName"`
+		InstanceStartAt string `json:"instanceStartAt"`
+		InstanceEndAt   string `json:"instanceEndAt"`
+		TotalSeats      *int   `json:"totalSeats" validate:"omitempty,min=0"`
+	}
+	DeleteRecurrenceExceptionParameter struct {
+		EventCode    string `json:"eventCode" validate:"required,min=7,max=7"`
+		OccurrenceAt string `query:"occurrenceAt" validate:"required"`
+	}
+	RecurrenceExceptionResponse struct {
+		Type            string     `json:"type"`
+		EventCode       string     `json:"eventCode"`
+		OccurrenceAt    time.Time  `json:"occurrenceAt"`
+		IsSkipped       bool       `json:"isSkipped"`
+		Title           string     `json:"title,omitempty"`
+		LocationName    string     `json:"locationName,omitempty"`
+		InstanceStartAt *time.Time `json:"instanceStartAt,omitempty"`
+		InstanceEndAt   *time.Time `json:"instanceEndAt,omitempty"`
+		TotalSeats      *int       `json:"totalSeats,omitempty"`
+	}
+)
+
+func (e *EventRecurrenceException) ToResponse() *RecurrenceExceptionResponse {
+	res := &RecurrenceExceptionResponse{
+		Type:         TYPE_EVENT_RECURRENCE,
+		EventCode:    e.EventCode,
+		OccurrenceAt: e.OccurrenceAt,
+		IsSkipped:    e.IsSkipped,
+		Title:        e.Title.String,
+		LocationName: e.LocationName.String,
+	}
+
+	if e.InstanceStartAt.Valid {
+		res.InstanceStartAt = &e.InstanceStartAt.Time
+	}
+
+	if e.InstanceEndAt.Valid {
+		res.InstanceEndAt = &e.InstanceEndAt.Time
+	}
+
+	if e.TotalSeats.Valid {
+		totalSeats := int(e.TotalSeats.Int32)
+		res.TotalSeats = &totalSeats
+	}
+
+	return res
+}
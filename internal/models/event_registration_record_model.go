@@ -8,6 +8,7 @@ import (
 
 var (
 	TYPE_EVENT_REGISTRATION_RECORD = "eventRegistrationRecord"
+	TYPE_EVENT_REGISTRATION_TICKET = "eventRegistrationTicket"
 )
 
 type EventRegistrationRecord struct {
@@ -25,6 +26,7 @@ type EventRegistrationRecord struct {
 	Description       string
 	RegisteredAt      time.Time
 	VerifiedAt        sql.NullTime
+	CheckedOutAt      sql.NullTime
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeletedAt         sql.NullTime
@@ -46,6 +48,8 @@ func (erer *CreateEventRegistrationRecordResponse) ToResponse() *CreateEventRegi
 		TotalRegistrants: erer.TotalRegistrants,
 		RegisterAt:       erer.RegisterAt,
 		Registrants:      erer.Registrants,
+		Ticket:           erer.Ticket,
+		Answers:          erer.Answers,
 	}
 }
 
@@ -61,9 +65,11 @@ type (
 		Description  string                                      `json:"description"`
 		RegisterAt   string                                      `json:"registerAt" validate:"required"`
 		Registrants  []CreateOtherEventRegistrationRecordRequest `json:"registrants" validate:"dive,required"`
+		Answers      []CreateAnswerRequest                       `json:"answers" validate:"omitempty,dive"`
 	}
 	CreateOtherEventRegistrationRecordRequest struct {
-		Name string `json:"name" validate:"required"`
+		Name    string                `json:"name" validate:"required"`
+		Answers []CreateAnswerRequest `json:"answers" validate:"omitempty,dive"`
 	}
 	CreateEventRegistrationRecordResponse struct {
 		Type             string                                       `json:"type"`
@@ -80,12 +86,29 @@ type (
 		TotalRegistrants int                                          `json:"totalRegistrants"`
 		RegisterAt       time.Time                                    `json:"registerAt"`
 		Registrants      []CreateOtherEventRegistrationRecordResponse `json:"registrants,omitempty"`
+		Ticket           string                                       `json:"ticket,omitempty"`
+		Answers          []CreateAnswerResponse                       `json:"answers,omitempty"`
 	}
 	CreateOtherEventRegistrationRecordResponse struct {
-		Type   string    `json:"type"`
-		ID     uuid.UUID `json:"id"`
-		Status string    `json:"status"`
-		Name   string    `json:"name"`
+		Type    string                 `json:"type"`
+		ID      uuid.UUID              `json:"id"`
+		Status  string                 `json:"status"`
+		Name    string                 `json:"name"`
+		Ticket  string                 `json:"ticket,omitempty"`
+		Answers []CreateAnswerResponse `json:"answers,omitempty"`
+	}
+)
+
+type (
+	VerifyRegistrationTicketRequest struct {
+		Ticket string `json:"ticket" validate:"required"`
+	}
+	VerifyRegistrationTicketResponse struct {
+		Type         string    `json:"type"`
+		ID           uuid.UUID `json:"registrationId"`
+		InstanceCode string    `json:"instanceCode"`
+		ValidFrom    time.Time `json:"validFrom"`
+		ValidUntil   time.Time `json:"validUntil"`
 	}
 )
 
@@ -103,6 +126,36 @@ func (erer *UpdateRegistrationStatusResponse) ToResponse() *UpdateRegistrationSt
 		InstanceTitle: erer.InstanceTitle,
 		UpdatedBy:     erer.UpdatedBy,
 		VerifiedAt:    erer.VerifiedAt,
+		CheckedOutAt:  erer.CheckedOutAt,
+		Promoted:      erer.Promoted,
+	}
+}
+
+// AffectedRegistrationResponse is a registration whose status was changed by an update to its event or instance,
+// with who registered it, so the organizer can reach out to them.
+type AffectedRegistrationResponse struct {
+	Type              string    `json:"type"`
+	ID                uuid.UUID `json:"id"`
+	Name              string    `json:"name"`
+	Identifier        string    `json:"identifier,omitempty"`
+	CommunityID       string    `json:"communityId,omitempty"`
+	IdentifierOrigin  string    `json:"identifierOrigin,omitempty"`
+	CommunityIdOrigin string    `json:"communityIdOrigin,omitempty"`
+	PreviousStatus    string    `json:"previousStatus" example:"pending"`
+	Status            string    `json:"status" example:"cancelled"`
+}
+
+func (e *EventRegistrationRecord) ToAffectedResponse(previousStatus string) AffectedRegistrationResponse {
+	return AffectedRegistrationResponse{
+		Type:              TYPE_EVENT_REGISTRATION_RECORD,
+		ID:                e.ID,
+		Name:              e.Name,
+		Identifier:        e.Identifier,
+		CommunityID:       e.CommunityId,
+		IdentifierOrigin:  e.IdentifierOrigin,
+		CommunityIdOrigin: e.CommunityIdOrigin,
+		PreviousStatus:    previousStatus,
+		Status:            e.Status,
 	}
 }
 
@@ -111,24 +164,50 @@ type (
 		ID string `json:"id" validate:"required,uuid"`
 	}
 	UpdateRegistrationStatusRequest struct {
-		Status    string `json:"status" validate:"required,oneof=success cancelled permit" example:"success"`
+		Status    string `json:"status" validate:"required,oneof=success cancelled permit checked-out" example:"success"`
 		Reason    string `json:"reason"`
 		UpdatedAt string `json:"updatedAt" validate:"required"`
+		// Ticket is the signed QR of the registration, required to check in or out while ticketing is on
+		Ticket string `json:"ticket"`
 	}
 	UpdateRegistrationStatusResponse struct {
-		Type          string    `json:"type"`
-		ID            uuid.UUID `json:"registrationId"`
-		Status        string    `json:"status"`
-		Reason        string    `json:"reason,omitempty"`
-		Name          string    `json:"name"`
-		Identifier    string    `json:"identifier,omitempty"`
-		CommunityID   string    `json:"communityId,omitempty"`
-		EventCode     string    `json:"eventCode"`
-		EventTitle    string    `json:"eventTitle"`
-		InstanceCode  string    `json:"instanceCode"`
-		InstanceTitle string    `json:"instanceTitle"`
-		UpdatedBy     string    `json:"updatedBy"`
-		VerifiedAt    time.Time `json:"verifiedAt"`
+		Type          string                                       `json:"type"`
+		ID            uuid.UUID                                    `json:"registrationId"`
+		Status        string                                       `json:"status"`
+		Reason        string                                       `json:"reason,omitempty"`
+		Name          string                                       `json:"name"`
+		Identifier    string                                       `json:"identifier,omitempty"`
+		CommunityID   string                                       `json:"communityId,omitempty"`
+		EventCode     string                                       `json:"eventCode"`
+		EventTitle    string                                       `json:"eventTitle"`
+		InstanceCode  string                                       `json:"instanceCode"`
+		InstanceTitle string                                       `json:"instanceTitle"`
+		UpdatedBy     string                                       `json:"updatedBy"`
+		VerifiedAt    *time.Time                                   `json:"verifiedAt,omitempty"`
+		CheckedOutAt  *time.Time                                   `json:"checkedOutAt,omitempty"`
+		Promoted      []CreateOtherEventRegistrationRecordResponse `json:"promoted,omitempty"`
+	}
+)
+
+type (
+	SyncRegistrationStatusRequest struct {
+		Scans []SyncRegistrationStatusItemRequest `json:"scans" validate:"required,min=1,max=500,dive"`
+	}
+	SyncRegistrationStatusItemRequest struct {
+		ID        string `json:"registrationId" validate:"required,uuid"`
+		Status    string `json:"status" validate:"required,oneof=success cancelled checked-out" example:"success"`
+		Reason    string `json:"reason"`
+		UpdatedAt string `json:"updatedAt" validate:"required"`
+		Ticket    string `json:"ticket"`
+	}
+	SyncRegistrationStatusResponse struct {
+		Type      string `json:"type"`
+		ID        string `json:"registrationId"`
+		Status    string `json:"status"`
+		UpdatedAt string `json:"updatedAt"`
+		Result    string `json:"result" example:"applied"`
+		Reason    string `json:"reason,omitempty" example:"ALREADY_UPDATED"`
+		Message   string `json:"message,omitempty"`
 	}
 )
 
@@ -206,6 +285,8 @@ type (
 		CampusCode     string `query:"campusCode" validate:"omitempty,min=3,max=3"`
 		DepartmentCode string `query:"departmentCode"`
 		CoolId         string `query:"coolId" validate:"omitempty,numeric"`
+		// CampusScope limits the list to the campuses the caller administers
+		CampusScope []string `query:"-"`
 	}
 	GetAllRegisteredCursorResponse struct {
 		Type              string    `json:"type"`
@@ -277,5 +358,7 @@ type (
 		DepartmentCode string `query:"departmentCode"`
 		NameSearch     string `query:"name"`
 		CoolId         string `query:"coolId" validate:"omitempty,numeric"`
+		// CampusScope limits the download to the campuses the caller administers
+		CampusScope []string `query:"-"`
 	}
 )
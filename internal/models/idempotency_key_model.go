@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type IdempotencyKey struct {
+	RequestId    string
+	Caller       string
+	Route        string
+	Fingerprint  string
+	Status       string
+	ResponseCode int
+	ResponseBody []byte
+	ContentType  string
+	LockedUntil  time.Time
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type IdempotencyStatus int32
+
+const (
+	IDEMPOTENCY_STATUS_PROCESSING IdempotencyStatus = iota
+	IDEMPOTENCY_STATUS_COMPLETED
+)
+
+const (
+	IdempotencyStatusProcessing = "processing"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+var (
+	MapIdempotencyStatus = map[IdempotencyStatus]string{
+		IDEMPOTENCY_STATUS_PROCESSING: IdempotencyStatusProcessing,
+		IDEMPOTENCY_STATUS_COMPLETED:  IdempotencyStatusCompleted,
+	}
+)
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	TYPE_LOGIN_UNLOCK  = "loginUnlock"
+	TYPE_LOGIN_LOCKOUT = "loginLockout"
+)
+
+type LoginFailure struct {
+	Scope         string
+	Value         string
+	Failures      int
+	LastFailedAt  time.Time
+	NextAttemptAt time.Time
+	LockedUntil   sql.NullTime
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type LoginLockout struct {
+	ID          uuid.UUID
+	Scope       string
+	Value       string
+	Failures    int
+	LockedUntil time.Time
+	UnlockedAt  sql.NullTime
+	UnlockedBy  sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type LoginScope int32
+
+const (
+	LOGIN_SCOPE_IDENTIFIER LoginScope = iota
+	LOGIN_SCOPE_IP
+)
+
+const (
+	LoginScopeIdentifier = "identifier"
+	LoginScopeIP         = "ip"
+)
+
+var (
+	MapLoginScope = map[LoginScope]string{
+		LOGIN_SCOPE_IDENTIFIER: LoginScopeIdentifier,
+		LOGIN_SCOPE_IP:         LoginScopeIP,
+	}
+)
+
+type (
+	UnlockLoginRequest struct {
+		Identifier string `json:"identifier" validate:"required_without=IpAddress,omitempty,emailPhoneFormat"`
+		IpAddress  string `json:"ipAddress" validate:"required_without=Identifier,omitempty,ip"`
+	}
+	UnlockLoginResponse struct {
+		Type       string `json:"type" example:"loginUnlock"`
+		Identifier string `json:"identifier,omitempty"`
+		IpAddress  string `json:"ipAddress,omitempty"`
+		Unlocked   int64  `json:"unlocked"`
+	}
+)
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"time"
+)
+
+type NotificationOutbox struct {
+	ID            uuid.UUID
+	Event         string
+	Channel       string
+	Recipient     string
+	Subject       string
+	Body          string
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	SentAt        sql.NullTime
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type NotificationStatus int32
+
+const (
+	NOTIFICATION_STATUS_PENDING NotificationStatus = iota
+	NOTIFICATION_STATUS_SENT
+	NOTIFICATION_STATUS_FAILED
+)
+
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+var (
+	MapNotificationStatus = map[NotificationStatus]string{
+		NOTIFICATION_STATUS_PENDING: NotificationStatusPending,
+		NOTIFICATION_STATUS_SENT:    NotificationStatusSent,
+		NOTIFICATION_STATUS_FAILED:  NotificationStatusFailed,
+	}
+)
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordResetToken struct {
+	ID          uuid.UUID
+	CommunityId string
+	TokenHash   string
+	ExpiresAt   time.Time
+	UsedAt      sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type (
+	ForgotPasswordRequest struct {
+		Identifier string `json:"identifier" validate:"required,emailPhoneFormat"`
+	}
+	ResetPasswordRequest struct {
+		Token           string `json:"token" validate:"required"`
+		Password        string `json:"password" validate:"required,min=6,max=50,noStartEndSpaces"`
+		ConfirmPassword string `json:"confirmPassword" validate:"required,min=6,max=50,noStartEndSpaces,eqfield=Password"`
+	}
+	ChangePasswordRequest struct {
+		CommunityId     string `json:"-" validate:"required"`
+		CurrentPassword string `json:"currentPassword" validate:"required"`
+		Password        string `json:"password" validate:"required,min=6,max=50,noStartEndSpaces"`
+		ConfirmPassword string `json:"confirmPassword" validate:"required,min=6,max=50,noStartEndSpaces,eqfield=Password"`
+	}
+)
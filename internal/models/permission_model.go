@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+var TYPE_PERMISSION = "permission"
+
+// Permissions checked by the code. They are registered in the permissions table and granted to roles and user types.
+const (
+	PERMISSION_ALL                       = "*"
+	PERMISSION_INTERNAL_ACCESS           = "internal:access"
+	PERMISSION_EVENT_REGISTRATION_VERIFY = "event-registration:verify"
+	PERMISSION_USER_SESSION_MANAGE       = "user-session:manage"
+	PERMISSION_PERMISSION_MANAGE         = "permission:manage"
+	PERMISSION_AUDIT_VIEW                = "audit:view"
+	PERMISSION_USER_MANAGE               = "user:manage"
+)
+
+type Permission struct {
+	ID          int
+	Permission  string
+	Resource    string
+	Action      string
+	Description string
+	SelfService bool
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+func (p *Permission) ToResponse() PermissionResponse {
+	return PermissionResponse{
+		Type:        TYPE_PERMISSION,
+		Permission:  p.Permission,
+		Resource:    p.Resource,
+		Action:      p.Action,
+		Description: p.Description,
+		SelfService: p.SelfService,
+	}
+}
+
+// GrantCovers reports whether a granted permission covers permission: itself, every action of its resource
+// with resource:*, or everything with *.
+func GrantCovers(grant string, permission string) bool {
+	if grant == PERMISSION_ALL || grant == permission {
+		return true
+	}
+
+	resource, action, found := strings.Cut(grant, ":")
+	return found && action == "*" && strings.HasPrefix(permission, resource+":")
+}
+
+type (
+	UpdateRolePermissionsParameter struct {
+		Role string `json:"role" validate:"required"`
+	}
+	UpdateUserTypePermissionsParameter struct {
+		UserType string `json:"userType" validate:"required"`
+	}
+	UpdatePermissionsRequest struct {
+		Permissions []string `json:"permissions" validate:"required,dive,required" example:"internal:access,event-registration:*"`
+	}
+	PermissionResponse struct {
+		Type        string `json:"type" example:"permission"`
+		Permission  string `json:"permission" example:"event-registration:verify"`
+		Resource    string `json:"resource" example:"event-registration"`
+		Action      string `json:"action" example:"verify"`
+		Description string `json:"description,omitempty" example:"Scan, verify and check out registrations"`
+		SelfService bool   `json:"selfService" example:"false"`
+	}
+	GrantedPermissionsResponse struct {
+		Type        string   `json:"type" example:"role"`
+		Name        string   `json:"name" example:"event-verify-record"`
+		Permissions []string `json:"permissions" example:"event-registration:verify"`
+	}
+)
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"github.com/lib/pq"
+	"time"
+)
 
 var TYPE_ROLE = "role"
 
@@ -8,6 +11,7 @@ type Role struct {
 	ID          int
 	Role        string
 	Description string
+	Permissions pq.StringArray `gorm:"type:text[]"`
 	CreatedAt   *time.Time
 	UpdatedAt   *time.Time
 }
@@ -17,6 +21,7 @@ func (r *Role) ToResponse() *RoleResponse {
 		Type:        TYPE_ROLE,
 		Role:        r.Role,
 		Description: r.Description,
+		Permissions: r.Permissions,
 	}
 }
 
@@ -26,8 +31,9 @@ type (
 		Description string `json:"description" example:"View specifically for event"`
 	}
 	RoleResponse struct {
-		Type        string `json:"type" example:"role"`
-		Role        string `json:"role" example:"event-view-volunteer"`
-		Description string `json:"description,omitempty" example:"View specifically for event"`
+		Type        string   `json:"type" example:"role"`
+		Role        string   `json:"role" example:"event-view-volunteer"`
+		Description string   `json:"description,omitempty" example:"View specifically for event"`
+		Permissions []string `json:"permissions" example:"internal:access"`
 	}
 )
@@ -8,22 +8,50 @@ const (
 	REGISTER_STATUS_FAILED
 	REGISTER_STATUS_CANCELLED
 	REGISTER_STATUS_PERMIT
+	REGISTER_STATUS_WAITLISTED
+	REGISTER_STATUS_CHECKED_OUT
 )
 
 const (
-	RegisterStatusSuccess   = "success"
-	RegisterStatusPending   = "pending"
-	RegisterStatusFailed    = "failed"
-	RegisterStatusCancelled = "cancelled"
-	RegisterStatusPermitted = "permit"
+	RegisterStatusSuccess    = "success"
+	RegisterStatusPending    = "pending"
+	RegisterStatusFailed     = "failed"
+	RegisterStatusCancelled  = "cancelled"
+	RegisterStatusPermitted  = "permit"
+	RegisterStatusWaitlisted = "waitlisted"
+	RegisterStatusCheckedOut = "checked-out"
 )
 
 var (
 	MapRegisterStatus = map[RegistrationStatus]string{
-		REGISTER_STATUS_SUCCESS:   RegisterStatusSuccess,
-		REGISTER_STATUS_PENDING:   RegisterStatusPending,
-		REGISTER_STATUS_FAILED:    RegisterStatusFailed,
-		REGISTER_STATUS_CANCELLED: RegisterStatusCancelled,
-		REGISTER_STATUS_PERMIT:    RegisterStatusPermitted,
+		REGISTER_STATUS_SUCCESS:     RegisterStatusSuccess,
+		REGISTER_STATUS_PENDING:     RegisterStatusPending,
+		REGISTER_STATUS_FAILED:      RegisterStatusFailed,
+		REGISTER_STATUS_CANCELLED:   RegisterStatusCancelled,
+		REGISTER_STATUS_PERMIT:      RegisterStatusPermitted,
+		REGISTER_STATUS_WAITLISTED:  RegisterStatusWaitlisted,
+		REGISTER_STATUS_CHECKED_OUT: RegisterStatusCheckedOut,
+	}
+)
+
+type SyncResult int32
+
+const (
+	SYNC_RESULT_APPLIED SyncResult = iota
+	SYNC_RESULT_DUPLICATE
+	SYNC_RESULT_REJECTED
+)
+
+const (
+	SyncResultApplied   = "applied"
+	SyncResultDuplicate = "duplicate"
+	SyncResultRejected  = "rejected"
+)
+
+var (
+	MapSyncResult = map[SyncResult]string{
+		SYNC_RESULT_APPLIED:   SyncResultApplied,
+		SYNC_RESULT_DUPLICATE: SyncResultDuplicate,
+		SYNC_RESULT_REJECTED:  SyncResultRejected,
 	}
 )
@@ -19,6 +19,7 @@ type User struct {
 	UserTypes        pq.StringArray `gorm:"type:text[]"`
 	Status           string
 	Roles            pq.StringArray `gorm:"type:text[]"`
+	CampusScopes     pq.StringArray `gorm:"type:text[]"`
 	Gender           string
 	Address          string
 	CampusCode       string
@@ -258,8 +259,9 @@ func (u *LoginUserResponse) ToLogin() LoginUserResponse {
 
 type (
 	LoginUserRequest struct {
-		Identifier string `json:"identifier" validate:"required,emailPhoneFormat"`
-		Password   string `json:"password" validate:"required,noStartEndSpaces"`
+		Identifier string        `json:"identifier" validate:"required,emailPhoneFormat"`
+		Password   string        `json:"password" validate:"required,noStartEndSpaces"`
+		Client     SessionClient `json:"-"`
 	}
 	LoginUserResponse struct {
 		Type           string        `json:"type" example:"coolCategory"`
@@ -372,13 +374,6 @@ func (u *User) ToUpdatePassword() UpdateUserPasswordResponse {
 }
 
 type (
-	UpdateUserPasswordParam struct {
-		Identifier string `json:"identifier" validate:"required,emailPhoneFormat"`
-	}
-	UpdateUserPasswordRequest struct {
-		Password        string `json:"password" validate:"required,min=6,max=50,noStartEndSpaces"`
-		ConfirmPassword string `json:"confirmPassword" validate:"required,min=6,max=50,noStartEndSpaces,eqfield=Password"`
-	}
 	UpdateUserPasswordResponse struct {
 		Type        string   `json:"type" example:"coolCategory"`
 		Name        string   `json:"name" example:"Profesionals"`
@@ -439,6 +434,8 @@ type (
 		CampusCode string `query:"campusCode"`
 		CoolId     int    `query:"coolId"`
 		Department string `query:"departmentCode"`
+		// CampusScope limits the list to the campuses the caller administers
+		CampusScope []string `query:"-"`
 	}
 	GetAllUserCursorResponse struct {
 		Type           string     `json:"type"`
@@ -612,6 +609,7 @@ type (
 		PhoneNumber      *string                 `json:"phoneNumber" validate:"omitempty,phoneFormat"`
 		UserTypes        []string                `json:"userTypes" validate:"required" example:"volunteer"`
 		Roles            []string                `json:"roles" validate:"required" example:"volunteer"`
+		CampusScopes     *[]string               `json:"campusScopes" validate:"omitempty,dive,min=3,max=3" example:"BKS"`
 		Gender           string                  `json:"gender" validate:"required,oneof=male female"`
 		Address          *string                 `json:"address"`
 		CampusCode       string                  `json:"campusCode" validate:"required,min=3,max=3" example:"001"`
@@ -638,6 +636,7 @@ type (
 		Email            string                  `json:"email"`
 		Roles            []string                `json:"roles"`
 		UserTypes        []string                `json:"userTypes"`
+		CampusScopes     []string                `json:"campusScopes"`
 		Gender           string                  `json:"gender"`
 		Address          string                  `json:"address"`
 		CampusCode       string                  `json:"campusCode"`
@@ -837,4 +836,5 @@ type GetRBACByCommunityIdDBOutput struct {
 	UserTypes     pq.StringArray `gorm:"type:text[]"`
 	Roles         pq.StringArray `gorm:"type:text[]"`
 	CombinedRoles pq.StringArray `gorm:"type:text[]"`
+	CampusScopes  pq.StringArray `gorm:"type:text[]"`
 }
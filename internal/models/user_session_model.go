@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"time"
+)
+
+var TYPE_USER_SESSION = "userSession"
+
+// UserSession is one refresh token. FamilyId stays the same across rotations and identifies the login on a device.
+type UserSession struct {
+	ID            uuid.UUID
+	FamilyId      uuid.UUID
+	CommunityId   string
+	ReplacedBy    uuid.NullUUID
+	UserAgent     string
+	IpAddress     string
+	ExpiresAt     time.Time
+	RevokedAt     sql.NullTime
+	RevokedReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type SessionRevokedReason int32
+
+const (
+	SESSION_REVOKED_ROTATED SessionRevokedReason = iota
+	SESSION_REVOKED_LOGOUT
+	SESSION_REVOKED_LOGOUT_ALL
+	SESSION_REVOKED_REUSED
+	SESSION_REVOKED_PASSWORD
+	SESSION_REVOKED_REMOVED
+)
+
+const (
+	SessionRevokedRotated   = "rotated"
+	SessionRevokedLogout    = "logout"
+	SessionRevokedLogoutAll = "logout-all"
+	SessionRevokedReused    = "reuse-detected"
+	SessionRevokedPassword  = "password-changed"
+	SessionRevokedRemoved   = "removed"
+)
+
+var (
+	MapSessionRevokedReason = map[SessionRevokedReason]string{
+		SESSION_REVOKED_ROTATED:    SessionRevokedRotated,
+		SESSION_REVOKED_LOGOUT:     SessionRevokedLogout,
+		SESSION_REVOKED_LOGOUT_ALL: SessionRevokedLogoutAll,
+		SESSION_REVOKED_REUSED:     SessionRevokedReused,
+		SESSION_REVOKED_PASSWORD:   SessionRevokedPassword,
+		SESSION_REVOKED_REMOVED:    SessionRevokedRemoved,
+	}
+)
+
+type (
+	SessionClient struct {
+		UserAgent string `json:"-"`
+		IpAddress string `json:"-"`
+	}
+	RefreshSessionRequest struct {
+		SessionId   string
+		CommunityId string
+		Client      SessionClient
+	}
+)
+
+type (
+	GetUserSessionsParameter struct {
+		CommunityId string `json:"communityId" validate:"required"`
+	}
+	DeleteUserSessionParameter struct {
+		CommunityId string `json:"communityId" validate:"required"`
+		SessionId   string `json:"sessionId" validate:"required,uuid"`
+	}
+	GetUserSessionDBOutput struct {
+		FamilyId   uuid.UUID
+		UserAgent  string
+		IpAddress  string
+		ExpiresAt  time.Time
+		LastUsedAt time.Time
+		CreatedAt  time.Time
+	}
+	UserSessionResponse struct {
+		Type       string    `json:"type" example:"userSession"`
+		Id         string    `json:"id"`
+		UserAgent  string    `json:"userAgent"`
+		IpAddress  string    `json:"ipAddress"`
+		IsCurrent  bool      `json:"isCurrent"`
+		CreatedAt  time.Time `json:"createdAt"`
+		LastUsedAt time.Time `json:"lastUsedAt"`
+		ExpiresAt  time.Time `json:"expiresAt"`
+	}
+)
+
+func (s *GetUserSessionDBOutput) ToResponse(currentSessionId string) UserSessionResponse {
+	return UserSessionResponse{
+		Type:       TYPE_USER_SESSION,
+		Id:         s.FamilyId.String(),
+		UserAgent:  s.UserAgent,
+		IpAddress:  s.IpAddress,
+		IsCurrent:  s.FamilyId.String() == currentSessionId,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
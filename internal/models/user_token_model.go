@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"github.com/labstack/echo/v4"
 	"time"
 )
@@ -77,6 +78,8 @@ type TokenValues struct {
 	Id        string   `json:"id"`
 	UserTypes []string `json:"userTypes"`
 	Roles     []string `json:"roles"`
+	SessionId string   `json:"sessionId"`
+	Campuses  []string `json:"campuses"`
 }
 
 func GetValueFromToken(ctx echo.Context) (TokenValues, error) {
@@ -95,9 +98,27 @@ func GetValueFromToken(ctx echo.Context) (TokenValues, error) {
 		return TokenValues{}, echo.ErrInternalServerError
 	}
 
+	// Access tokens of guests and the ones issued before sessions existed have no session
+	sessionId, _ := ctx.Get("sessionId").(string)
+	campuses, _ := ctx.Get("campuses").([]string)
+
 	return TokenValues{
 		Id:        id,
 		UserTypes: userTypes,
 		Roles:     roles,
+		SessionId: sessionId,
+		Campuses:  campuses,
 	}, nil
 }
+
+type tokenValuesKey struct{}
+
+// WithTokenValues keeps the values of the access token in the request context, so usecases can authorize with it.
+func WithTokenValues(ctx context.Context, value TokenValues) context.Context {
+	return context.WithValue(ctx, tokenValuesKey{}, value)
+}
+
+func TokenValuesFromContext(ctx context.Context) (TokenValues, bool) {
+	value, ok := ctx.Value(tokenValuesKey{}).(TokenValues)
+	return value, ok
+}
@@ -13,6 +13,7 @@ type UserType struct {
 	Name        string
 	Description string
 	Roles       pq.StringArray `gorm:"type:text[]"`
+	Permissions pq.StringArray `gorm:"type:text[]"`
 	Category    string
 	CreatedAt   *time.Time
 	UpdatedAt   *time.Time
@@ -24,6 +25,7 @@ func (ut *UserType) ToResponse() *UserTypeResponse {
 		UserType:    ut.Type,
 		Name:        ut.Name,
 		Roles:       ut.Roles,
+		Permissions: ut.Permissions,
 		Description: ut.Description,
 		Category:    ut.Category,
 	}
@@ -43,6 +45,7 @@ type (
 		Name        string   `json:"name" example:"Volunteer"`
 		Description string   `json:"description" example:"Volunteer"`
 		Roles       []string `json:"roles" example:"event-view-event-viewer"`
+		Permissions []string `json:"permissions" example:"event-registration:verify"`
 		Category    string   `json:"category" example:"general"`
 	}
 )
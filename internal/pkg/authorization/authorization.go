@@ -1,11 +1,16 @@
 package authorization
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"go-community/internal/common"
 	"go-community/internal/config"
 	"go-community/internal/models"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,10 +27,22 @@ type Authorization interface {
 type Auth struct {
 	bearerKey       string
 	bearerSecret    string
+	bearerSecrets   map[string]string
 	bearerDuration  int
 	refreshKey      string
 	refreshSecret   string
 	refreshDuration int
+
+	// Access tokens are signed with HS256 and the bearer secret, unless an asymmetric algorithm is configured.
+	// Every configured key is accepted and published, the one of signingKeyId signs.
+	algorithm    string
+	signingKeyId string
+	signingKey   crypto.Signer
+	publicKeys   map[string]crypto.PublicKey
+
+	// legacyHS256Until is when tokens signed with the bearer secret stop being accepted next to an asymmetric
+	// algorithm. It is zero when they are not accepted at all.
+	legacyHS256Until time.Time
 }
 
 func NewAuthorization(config *config.Configuration) (*Auth, error) {
@@ -45,10 +62,65 @@ func NewAuthorization(config *config.Configuration) (*Auth, error) {
 	auth := &Auth{
 		bearerKey:       bearerKey,
 		bearerSecret:    config.Auth.BearerSecret[bearerKey],
+		bearerSecrets:   config.Auth.BearerSecret,
 		bearerDuration:  config.Auth.BearerDuration,
 		refreshKey:      refreshKey,
 		refreshSecret:   config.Auth.RefreshSecret[refreshKey],
 		refreshDuration: config.Auth.RefreshDuration,
+		algorithm:       config.Auth.SigningAlgorithm,
+		publicKeys:      make(map[string]crypto.PublicKey),
+	}
+
+	if auth.algorithm == "" {
+		auth.algorithm = AlgorithmHS256
+	}
+
+	switch auth.algorithm {
+	case AlgorithmHS256:
+		return auth, nil
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", auth.algorithm)
+	}
+
+	if len(config.Auth.SigningKeys) == 0 {
+		return nil, errors.New("signing keys are missing")
+	}
+
+	keyId := config.Auth.SigningKeyId
+	if keyId == "" {
+		if len(config.Auth.SigningKeys) > 1 {
+			return nil, errors.New("signing key id is required when there are multiple signing keys")
+		}
+
+		for sKey := range config.Auth.SigningKeys {
+			keyId = sKey
+		}
+	}
+
+	if _, exists := config.Auth.SigningKeys[keyId]; !exists {
+		return nil, errors.New("signing key id does not match any signing key")
+	}
+
+	if config.Auth.AcceptLegacyHS256 {
+		until, err := time.Parse(time.RFC3339, config.Auth.LegacyHS256Until)
+		if err != nil {
+			return nil, errors.New("legacy hs256 until has to be an RFC3339 time when legacy hs256 tokens are accepted")
+		}
+		auth.legacyHS256Until = until
+	}
+
+	for sKey, path := range config.Auth.SigningKeys {
+		key, err := loadSigningKey(auth.algorithm, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if sKey == keyId {
+			auth.signingKeyId = sKey
+			auth.signingKey = key
+		}
+		auth.publicKeys[sKey] = key.Public()
 	}
 
 	return auth, nil
@@ -60,10 +132,11 @@ type Claim struct {
 	AuthorizedParty string   `json:"azp"`
 	UserTypes       []string `json:"userTypes"`
 	Roles           []string `json:"roles"`
+	SessionId       string   `json:"sid,omitempty"`
+	Campuses        []string `json:"campuses,omitempty"`
 }
 
-func (a *Auth) GenerateAccessToken(id string, userTypes []string, role []string) (string, error) {
-	now := common.Now()
+func (a *Auth) GenerateAccessToken(id string, userTypes []string, role []string, campuses []string, sessionId string, now time.Time) (string, error) {
 	expired := now.Add(time.Duration(a.bearerDuration) * time.Minute)
 	keyId := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString([]byte(a.bearerKey))
 	claims := &Claim{
@@ -78,6 +151,14 @@ func (a *Auth) GenerateAccessToken(id string, userTypes []string, role []string)
 		Type:            "access",
 		UserTypes:       userTypes,
 		Roles:           role,
+		SessionId:       sessionId,
+		Campuses:        campuses,
+	}
+
+	if a.signingKey != nil {
+		token := jwt.NewWithClaims(jwt.GetSigningMethod(a.algorithm), claims)
+		token.Header["kid"] = encodeKeyId(a.signingKeyId)
+		return token.SignedString(a.signingKey)
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -90,9 +171,8 @@ func (a *Auth) GenerateAccessToken(id string, userTypes []string, role []string)
 	return tokenString, nil
 }
 
-func (a *Auth) GenerateRefreshToken(id string) (string, error) {
-	now := common.Now()
-	expired := now.Add(time.Duration(a.bearerDuration) * time.Minute)
+func (a *Auth) GenerateRefreshToken(id string, tokenId string, now time.Time) (string, error) {
+	expired := a.RefreshExpiry(now)
 	keyId := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString([]byte(a.refreshKey))
 	claims := &Claim{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -101,6 +181,7 @@ func (a *Auth) GenerateRefreshToken(id string) (string, error) {
 			Audience:  jwt.ClaimStrings{"otw"},
 			Subject:   id,
 			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        tokenId,
 		},
 		Type:            "refresh",
 		AuthorizedParty: "otw",
@@ -116,23 +197,87 @@ func (a *Auth) GenerateRefreshToken(id string) (string, error) {
 	return tokenString, nil
 }
 
-func (a *Auth) GenerateTokens(id string, userTypes []string, role []string) (*models.UserToken, error) {
-	access, err := a.GenerateAccessToken(id, userTypes, role)
+// GenerateTokens issues an access and a refresh token. For users, tokenId is the id of the session row of the refresh token
+// and sessionId the session it belongs to; guests have neither. campuses limits the roles to those campuses, none is every campus.
+func (a *Auth) GenerateTokens(id string, userTypes []string, role []string, campuses []string, sessionId string, tokenId string) (*models.UserToken, error) {
+	now := common.Now()
+	access, err := a.GenerateAccessToken(id, userTypes, role, campuses, sessionId, now)
 	if err != nil {
 		return nil, err
 	}
 
-	refresh, err := a.GenerateRefreshToken(id)
+	refresh, err := a.GenerateRefreshToken(id, tokenId, now)
 	if err != nil {
 		return nil, err
 	}
 
 	tokens := models.UserToken{
 		AccessToken:   access,
-		AccessExpiry:  time.Now().Add(time.Duration(a.bearerDuration) * time.Minute),
+		AccessExpiry:  now.Add(time.Duration(a.bearerDuration) * time.Minute),
 		RefreshToken:  refresh,
-		RefreshExpiry: time.Now().Add(time.Duration(a.refreshDuration) * 24 * time.Hour),
+		RefreshExpiry: a.RefreshExpiry(now),
 	}
 
 	return &tokens, nil
 }
+
+// ParseAccessToken verifies an access token into claims. Next to an asymmetric algorithm, tokens signed with
+// a bearer secret are only accepted when configured to, until their sunset, so switching does not log everyone out.
+func (a *Auth) ParseAccessToken(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	methods := []string{a.algorithm}
+	if a.algorithm != AlgorithmHS256 && common.Now().Before(a.legacyHS256Until) {
+		methods = append(methods, AlgorithmHS256)
+	}
+
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("token has no key id")
+		}
+
+		keyId, err := base64.RawURLEncoding.DecodeString(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if secret, exists := a.bearerSecrets[string(keyId)]; exists {
+				return []byte(secret), nil
+			}
+		case *jwt.SigningMethodRSA:
+			if key, ok := a.publicKeys[string(keyId)].(*rsa.PublicKey); ok {
+				return key, nil
+			}
+		case *jwt.SigningMethodEd25519:
+			if key, ok := a.publicKeys[string(keyId)].(ed25519.PublicKey); ok {
+				return key, nil
+			}
+		}
+
+		return nil, errors.New("unknown key id")
+	}, jwt.WithValidMethods(methods))
+}
+
+// JWKS lists the public keys access tokens can be verified with. It is empty when they are signed with HS256.
+func (a *Auth) JWKS() (*JWKSet, error) {
+	set := &JWKSet{Keys: make([]JWK, 0, len(a.publicKeys))}
+	for keyId, key := range a.publicKeys {
+		jwk, err := toJWK(keyId, a.algorithm, key)
+		if err != nil {
+			return nil, err
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyId < set.Keys[j].KeyId
+	})
+
+	return set, nil
+}
+
+func (a *Auth) RefreshExpiry(issuedAt time.Time) time.Time {
+	return issuedAt.Add(time.Duration(a.refreshDuration) * 24 * time.Hour)
+}
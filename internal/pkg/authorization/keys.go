@@ -0,0 +1,90 @@
+package authorization
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+type (
+	// JWK is the public part of a signing key, as published in the JWKS endpoint (RFC 7517).
+	JWK struct {
+		KeyType   string `json:"kty"`
+		KeyId     string `json:"kid"`
+		Use       string `json:"use"`
+		Algorithm string `json:"alg"`
+		N         string `json:"n,omitempty"`
+		E         string `json:"e,omitempty"`
+		Curve     string `json:"crv,omitempty"`
+		X         string `json:"x,omitempty"`
+	}
+	JWKSet struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+// loadSigningKey reads a PEM encoded private key for the algorithm, PKCS#1 or PKCS#8 for RS256 and PKCS#8 for EdDSA.
+func loadSigningKey(algorithm string, path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case AlgorithmRS256:
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return key, nil
+	case AlgorithmEdDSA:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return key.(crypto.Signer), nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+func toJWK(keyId string, algorithm string, key crypto.PublicKey) (JWK, error) {
+	jwk := JWK{
+		KeyId:     encodeKeyId(keyId),
+		Use:       "sig",
+		Algorithm: algorithm,
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return JWK{}, errors.New("unsupported public key type")
+	}
+
+	return jwk, nil
+}
+
+// encodeKeyId turns a configured key id into the kid header of a token.
+func encodeKeyId(keyId string) string {
+	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString([]byte(keyId))
+}
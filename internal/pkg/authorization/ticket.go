@@ -0,0 +1,146 @@
+package authorization
+
+import (
+	"encoding/base64"
+	"errors"
+	"go-community/internal/common"
+	"go-community/internal/config"
+	"go-community/internal/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Ticket signs and verifies the QR payload of a registration. Every configured secret is accepted when verifying,
+// so a new key id can be rolled out while tickets signed with the previous one are still in circulation.
+// Without a configured secret, ticketing is off: registrations get no ticket and are checked in by their id alone,
+// and reading a ticket fails with ErrorTicketNotConfigured.
+type Ticket struct {
+	keyId   string
+	secrets map[string]string
+}
+
+func NewTicket(config *config.Configuration) (*Ticket, error) {
+	if len(config.Auth.TicketSecret) == 0 {
+		return &Ticket{}, nil
+	}
+
+	keyId := config.Auth.TicketKeyId
+	if keyId == "" {
+		if len(config.Auth.TicketSecret) > 1 {
+			return nil, errors.New("ticket key id is required when there are multiple ticket secrets")
+		}
+
+		for tKey := range config.Auth.TicketSecret {
+			keyId = tKey
+		}
+	}
+
+	if _, exists := config.Auth.TicketSecret[keyId]; !exists {
+		return nil, errors.New("ticket key id does not match any ticket secret")
+	}
+
+	return &Ticket{
+		keyId:   keyId,
+		secrets: config.Auth.TicketSecret,
+	}, nil
+}
+
+// Enabled reports whether a ticket secret is configured, so tickets are issued and have to be scanned.
+func (t *Ticket) Enabled() bool {
+	return len(t.secrets) > 0
+}
+
+type TicketClaim struct {
+	Type         string `json:"typ"`
+	InstanceCode string `json:"ins"`
+	jwt.RegisteredClaims
+}
+
+func (t *Ticket) GenerateTicket(registrationId string, instanceCode string, validFrom time.Time, validUntil time.Time) (string, error) {
+	if len(t.secrets) == 0 {
+		return "", models.ErrorTicketNotConfigured
+	}
+
+	keyId := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString([]byte(t.keyId))
+	claims := &TicketClaim{
+		Type:         "ticket",
+		InstanceCode: instanceCode,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   registrationId,
+			NotBefore: jwt.NewNumericDate(validFrom),
+			ExpiresAt: jwt.NewNumericDate(validUntil),
+			IssuedAt:  jwt.NewNumericDate(common.Now()),
+			Issuer:    "otw",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = keyId
+	tokenString, err := token.SignedString([]byte(t.secrets[t.keyId]))
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// ValidateTicket verifies a ticket and that it is valid right now.
+func (t *Ticket) ValidateTicket(ticket string) (*TicketClaim, error) {
+	return t.parseTicket(ticket, jwt.WithIssuer("otw"), jwt.WithExpirationRequired())
+}
+
+// ParseTicket only verifies the signature of a ticket. Scans are checked against the verify window of the instance
+// at the time they were made instead, since an offline scan or a check-out can be applied after the ticket expires.
+func (t *Ticket) ParseTicket(ticket string) (*TicketClaim, error) {
+	claims, err := t.parseTicket(ticket, jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Issuer != "otw" {
+		return nil, models.ErrorInvalidTicket
+	}
+
+	return claims, nil
+}
+
+func (t *Ticket) parseTicket(ticket string, options ...jwt.ParserOption) (*TicketClaim, error) {
+	if len(t.secrets) == 0 {
+		return nil, models.ErrorTicketNotConfigured
+	}
+
+	token, err := jwt.ParseWithClaims(ticket, &TicketClaim{}, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, models.ErrorInvalidTicket
+		}
+
+		keyId, err := base64.RawURLEncoding.DecodeString(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		secret, exists := t.secrets[string(keyId)]
+		if !exists {
+			return nil, models.ErrorInvalidTicket
+		}
+
+		return []byte(secret), nil
+	}, append([]jwt.ParserOption{jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})}, options...)...)
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, models.ErrorExpiredTicket
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return nil, models.ErrorTicketNotYetValid
+	case err != nil:
+		return nil, models.ErrorInvalidTicket
+	}
+
+	claims, ok := token.Claims.(*TicketClaim)
+	if !ok || !token.Valid || claims.Type != "ticket" || claims.Subject == "" || claims.InstanceCode == "" {
+		return nil, models.ErrorInvalidTicket
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,16 @@
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// SecretToken returns a random URL-safe token with 256 bits of entropy, for links and one-time secrets.
+func SecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
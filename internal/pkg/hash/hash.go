@@ -1,6 +1,11 @@
 package hash
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func Generate(password []byte) (hashed string, err error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
@@ -14,3 +19,10 @@ func Generate(password []byte) (hashed string, err error) {
 func Validate(hashed string, input string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(input))
 }
+
+// Token hashes a high-entropy secret token for storage. Unlike passwords, such tokens cannot be guessed,
+// so a fast hash is enough and the stored value can be looked up directly.
+func Token(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
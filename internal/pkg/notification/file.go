@@ -0,0 +1,43 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"go-community/internal/config"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileSender writes every message as a line of JSON instead of delivering it, for local development and tests.
+// An empty path or "stdout" writes to the standard output.
+type FileSender struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewFileSender(config config.NotificationSender) (*FileSender, error) {
+	if config.Path == "" || config.Path == "stdout" {
+		return &FileSender{writer: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSender{writer: file}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, message Message) error {
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.writer.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,115 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-community/internal/config"
+	"strings"
+	"time"
+)
+
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+
+	DriverSMTP    = "smtp"
+	DriverWebhook = "webhook"
+	DriverFile    = "file"
+)
+
+var ErrNoSender = errors.New("no sender is configured for the channel")
+
+type Message struct {
+	Channel   string `json:"channel"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject,omitempty"`
+	Body      string `json:"body"`
+}
+
+// Sender delivers a message through one channel. An error means the message may be retried later.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Notifier routes messages to the sender configured for their channel.
+type Notifier struct {
+	senders      map[string]Sender
+	phoneChannel string
+}
+
+func NewNotifier(config *config.Configuration) (*Notifier, error) {
+	n := &Notifier{
+		senders:      make(map[string]Sender),
+		phoneChannel: strings.ToLower(config.Notification.PhoneChannel),
+	}
+
+	if n.phoneChannel == "" {
+		n.phoneChannel = ChannelWhatsApp
+	}
+
+	if n.phoneChannel != ChannelSMS && n.phoneChannel != ChannelWhatsApp {
+		return nil, fmt.Errorf("phone channel %q is neither %s nor %s", n.phoneChannel, ChannelSMS, ChannelWhatsApp)
+	}
+
+	for channel, sender := range config.Notification.Senders {
+		switch strings.ToLower(sender.Driver) {
+		case DriverSMTP:
+			n.senders[channel] = NewSMTPSender(sender)
+		case DriverWebhook:
+			n.senders[channel] = NewWebhookSender(sender)
+		case DriverFile:
+			fileSender, err := NewFileSender(sender)
+			if err != nil {
+				return nil, err
+			}
+			n.senders[channel] = fileSender
+		default:
+			return nil, fmt.Errorf("unknown notification driver %q for channel %s", sender.Driver, channel)
+		}
+	}
+
+	return n, nil
+}
+
+// ChannelFor picks the channel a recipient is reached through, an email address by email and
+// a phone number through the configured phone channel. It is empty when no sender can reach the recipient.
+func (n *Notifier) ChannelFor(recipient string) string {
+	recipient = strings.TrimSpace(recipient)
+	channel := n.phoneChannel
+	switch {
+	case recipient == "":
+		return ""
+	case strings.Contains(recipient, "@"):
+		channel = ChannelEmail
+	}
+
+	if _, exists := n.senders[channel]; !exists {
+		return ""
+	}
+
+	return channel
+}
+
+func (n *Notifier) Send(ctx context.Context, message Message) error {
+	sender, exists := n.senders[message.Channel]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrNoSender, message.Channel)
+	}
+
+	return sender.Send(ctx, message)
+}
+
+// Backoff is how long to wait before the next attempt after attempt failed, doubling from a minute up to an hour.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	if attempt > 7 {
+		return time.Hour
+	}
+
+	return min(time.Minute<<(attempt-1), time.Hour)
+}
@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"go-community/internal/config"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends the message as a plain text email. Every send is bound to both the context and the timeout,
+// so a server that stops responding fails the delivery instead of holding the worker past its lease.
+type SMTPSender struct {
+	host    string
+	address string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+func NewSMTPSender(config config.NotificationSender) *SMTPSender {
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &SMTPSender{
+		host:    config.Host,
+		address: net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		auth:    auth,
+		from:    config.From,
+		timeout: timeout,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message Message) error {
+	// The addresses go into the headers and the SMTP commands as they are, like smtp.SendMail they cannot span lines
+	if strings.ContainsAny(s.from+message.Recipient, "\r\n") {
+		return errors.New("smtp: an address cannot contain CR or LF")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", s.from)
+	fmt.Fprintf(&body, "To: %s\r\n", message.Recipient)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The deadline covers every read and write of the conversation, and cancelling ctx cuts it short
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	if err := s.send(conn, message.Recipient, body.String()); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %v", ctx.Err(), err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// send is smtp.SendMail over a connection that is already open.
+func (s *SMTPSender) send(conn net.Conn, recipient string, body string) error {
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(s.auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(recipient); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	EventRegistrationCreated     = "registration.created"
+	EventRegistrationVerified    = "registration.verified"
+	EventCoolJoinerStatusChanged = "cool-joiner.status-changed"
+	EventPasswordResetRequested  = "password.reset-requested"
+	EventCoolJoinerAssigned      = "cool-joiner.assigned"
+	EventCoolJoinerOverdue       = "cool-joiner.overdue"
+)
+
+type (
+	RegistrationData struct {
+		Name             string
+		EventTitle       string
+		InstanceTitle    string
+		Status           string
+		TotalRegistrants int
+		At               time.Time
+	}
+	CoolJoinerData struct {
+		Name     string
+		Status   string
+		Location string
+	}
+	// CoolJoinerFollowUpData is sent to whoever follows up the new joiner
+	CoolJoinerFollowUpData struct {
+		Name              string
+		JoinerName        string
+		JoinerPhoneNumber string
+		Location          string
+		CoolName          string
+		Since             time.Time
+	}
+	PasswordResetData struct {
+		Name    string
+		URL     string
+		Token   string
+		Minutes int
+	}
+)
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+	// sensitive messages carry a credential, so their body is not kept once the message is done with
+	sensitive bool
+}
+
+var templates = map[string]messageTemplate{
+	EventRegistrationCreated: newTemplate(EventRegistrationCreated,
+		`Registration for {{.EventTitle}}`,
+		`Hi {{.Name}},
+
+{{if eq .Status "waitlisted"}}{{.InstanceTitle}} of {{.EventTitle}} is full, so you are on the waitlist. We will let you know when a seat opens up.
+{{else}}You are registered for {{.InstanceTitle}} of {{.EventTitle}}.
+{{end}}{{if gt .TotalRegistrants 1}}The registration covers {{.TotalRegistrants}} people, including you.
+{{end}}
+Registered at {{.At.Format "02 Jan 2006 15:04"}}.`),
+	EventRegistrationVerified: newTemplate(EventRegistrationVerified,
+		`Welcome to {{.EventTitle}}`,
+		`Hi {{.Name}},
+
+You have been checked in to {{.InstanceTitle}} of {{.EventTitle}} at {{.At.Format "15:04"}}. Enjoy the session!`),
+	EventCoolJoinerStatusChanged: newTemplate(EventCoolJoinerStatusChanged,
+		`Your request to join a COOL`,
+		`Hi {{.Name}},
+
+Your request to join a COOL{{if .Location}} in {{.Location}}{{end}} is now {{.Status}}.`),
+	EventCoolJoinerAssigned: newTemplate(EventCoolJoinerAssigned,
+		`New joiner for {{.CoolName}}`,
+		`Hi {{.Name}},
+
+{{.JoinerName}}{{if .Location}} from {{.Location}}{{end}} would like to join a COOL and has been assigned to {{.CoolName}} for you to follow up. You can reach them at {{.JoinerPhoneNumber}}.`),
+	EventCoolJoinerOverdue: newTemplate(EventCoolJoinerOverdue,
+		`{{.JoinerName}} is waiting to be followed up`,
+		`Hi {{.Name}},
+
+{{.JoinerName}} has been waiting to join {{.CoolName}} since {{.Since.Format "02 Jan 2006 15:04"}}. Please reach them at {{.JoinerPhoneNumber}} and update their status.`),
+	EventPasswordResetRequested: newSensitiveTemplate(EventPasswordResetRequested,
+		`Reset your password`,
+		`Hi {{.Name}},
+
+{{if .URL}}Open {{.URL}} to set a new password.{{else}}Use this code to set a new password: {{.Token}}{{end}}
+It can be used once within {{.Minutes}} minutes. If you did not ask for it, you can ignore this message.`),
+}
+
+func newTemplate(name string, subject string, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New(name + ".subject").Parse(subject)),
+		body:    template.Must(template.New(name + ".body").Parse(body)),
+	}
+}
+
+func newSensitiveTemplate(name string, subject string, body string) messageTemplate {
+	t := newTemplate(name, subject, body)
+	t.sensitive = true
+	return t
+}
+
+// IsSensitive tells whether the message of event carries a credential, e.g. a password reset token.
+func IsSensitive(event string) bool {
+	return templates[event].sensitive
+}
+
+// Render builds the subject and body of the message sent for event.
+func Render(event string, data interface{}) (subject string, body string, err error) {
+	t, exists := templates[event]
+	if !exists {
+		return "", "", fmt.Errorf("no template for notification event %q", event)
+	}
+
+	var s, b strings.Builder
+	if err = t.subject.Execute(&s, data); err != nil {
+		return "", "", err
+	}
+
+	if err = t.body.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+
+	return s.String(), b.String(), nil
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Counter counts the requests of a key within the window starting at windowStart, shared by every instance of the app.
+type Counter interface {
+	Hit(ctx context.Context, key string, windowStart time.Time, expiresAt time.Time) (count int, err error)
+}
+
+// CounterStore limits with fixed windows of the policy period, counted by a Counter. Burst does not apply.
+type CounterStore struct {
+	counter Counter
+}
+
+func NewCounterStore(counter Counter) *CounterStore {
+	return &CounterStore{counter: counter}
+}
+
+func (s *CounterStore) Take(ctx context.Context, key string, policy *Policy, now time.Time) (Result, error) {
+	windowStart := now.Truncate(policy.Period)
+	windowEnd := windowStart.Add(policy.Period)
+
+	count, err := s.counter.Hit(ctx, key, windowStart, windowEnd)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Allowed:   count <= policy.Requests,
+		Limit:     policy.Requests,
+		Remaining: max(policy.Requests-count, 0),
+		Reset:     windowEnd.Sub(now),
+	}
+
+	if !result.Allowed {
+		result.RetryAfter = result.Reset
+	}
+
+	return result, nil
+}
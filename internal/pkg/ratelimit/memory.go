@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// MemoryStore keeps a token bucket per key in the process. Past maxKeys the least recently used bucket is dropped;
+// an idle bucket has refilled anyway, so dropping it loses nothing.
+type MemoryStore struct {
+	mu      sync.Mutex
+	maxKeys int
+	buckets map[string]*list.Element
+	order   *list.List
+}
+
+type bucket struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	if maxKeys <= 0 {
+		maxKeys = 10000
+	}
+
+	return &MemoryStore{
+		maxKeys: maxKeys,
+		buckets: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, policy *Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, exists := s.buckets[key]
+	if exists {
+		s.order.MoveToFront(element)
+	} else {
+		every := rate.Every(policy.Period / time.Duration(policy.Requests))
+		element = s.order.PushFront(&bucket{key: key, limiter: rate.NewLimiter(every, policy.Burst)})
+		s.buckets[key] = element
+
+		for s.order.Len() > s.maxKeys {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.buckets, oldest.Value.(*bucket).key)
+		}
+	}
+
+	limiter := element.Value.(*bucket).limiter
+	allowed := limiter.AllowN(now, 1)
+	tokens := limiter.TokensAt(now)
+	perToken := float64(policy.Period) / float64(policy.Requests)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     policy.Burst,
+		Remaining: max(int(tokens), 0),
+		Reset:     time.Duration((float64(policy.Burst) - tokens) * perToken),
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * perToken)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"go-community/internal/config"
+	"strings"
+	"time"
+)
+
+const (
+	KeyIP     = "ip"
+	KeyClient = "client"
+	KeyUser   = "user"
+
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+
+	// DefaultPolicy applies to every route that no other policy lists
+	DefaultPolicy = "default"
+)
+
+type (
+	Policy struct {
+		Name     string
+		Key      string
+		Requests int
+		Period   time.Duration
+		Burst    int
+	}
+	// Result is the state of the bucket of a key after a request. Reset is when it is full again,
+	// RetryAfter how long a rejected request has to wait.
+	Result struct {
+		Allowed    bool
+		Limit      int
+		Remaining  int
+		Reset      time.Duration
+		RetryAfter time.Duration
+	}
+)
+
+type Store interface {
+	Take(ctx context.Context, key string, policy *Policy, now time.Time) (Result, error)
+}
+
+// Limiter finds the policy of a route and takes a request from the bucket of the caller under it.
+type Limiter struct {
+	store    Store
+	routes   map[string]*Policy
+	fallback *Policy
+}
+
+// New builds the limiter from the configured policies. The Postgres store counts through counter, so every
+// instance of the app shares the same limits; the memory store keeps them per instance.
+func New(cfg config.RateLimit, counter Counter) (*Limiter, error) {
+	l := &Limiter{routes: make(map[string]*Policy)}
+
+	switch cfg.Store {
+	case "", StoreMemory:
+		l.store = NewMemoryStore(cfg.MaxKeys)
+	case StorePostgres:
+		if counter == nil {
+			return nil, fmt.Errorf("rate limit store %q needs a counter", cfg.Store)
+		}
+		l.store = NewCounterStore(counter)
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store %q", cfg.Store)
+	}
+
+	for name, p := range cfg.Policies {
+		policy := &Policy{
+			Name:     name,
+			Key:      p.Key,
+			Requests: p.Requests,
+			Period:   p.Period,
+			Burst:    p.Burst,
+		}
+
+		if policy.Key == "" {
+			policy.Key = KeyIP
+		}
+
+		if policy.Key != KeyIP && policy.Key != KeyClient && policy.Key != KeyUser {
+			return nil, fmt.Errorf("rate limit policy %q has unsupported key %q", name, policy.Key)
+		}
+
+		if policy.Requests <= 0 || policy.Period <= 0 {
+			return nil, fmt.Errorf("rate limit policy %q needs requests and period", name)
+		}
+
+		if policy.Burst <= 0 {
+			policy.Burst = policy.Requests
+		}
+
+		if name == DefaultPolicy {
+			l.fallback = policy
+		}
+
+		for _, route := range p.Routes {
+			route = normalizeRoute(route)
+			if other, exists := l.routes[route]; exists {
+				return nil, fmt.Errorf("route %q is in both rate limit policies %q and %q", route, other.Name, name)
+			}
+			l.routes[route] = policy
+		}
+	}
+
+	return l, nil
+}
+
+// PolicyFor returns the policy of the route, listed as "METHOD path" or as a path for every method,
+// or nil when it is not limited.
+func (l *Limiter) PolicyFor(method string, path string) *Policy {
+	if policy, exists := l.routes[method+" "+path]; exists {
+		return policy
+	}
+
+	if policy, exists := l.routes[path]; exists {
+		return policy
+	}
+
+	return l.fallback
+}
+
+func (l *Limiter) Take(ctx context.Context, policy *Policy, key string) (Result, error) {
+	return l.store.Take(ctx, policy.Name+":"+key, policy, time.Now())
+}
+
+func normalizeRoute(route string) string {
+	method, path, found := strings.Cut(strings.TrimSpace(route), " ")
+	if !found {
+		return method
+	}
+
+	return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+}
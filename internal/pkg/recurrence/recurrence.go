@@ -0,0 +1,360 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is the subset of an RFC 5545 RRULE that events need: FREQ, INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY.
+// The plain frequencies stored before rules existed ("daily", "weekly", "monthly", "yearly") are accepted as well.
+type Rule struct {
+	Frequency string
+	Interval  int
+	Count     int
+	Until     time.Time
+	// UntilDate is set when UNTIL is a date without a time. The rule then lasts until the end of that day in the
+	// location of its start, rather than until midnight UTC.
+	UntilDate  bool
+	ByDay      []Weekday
+	ByMonthDay []int
+}
+
+// Weekday is a BYDAY entry. Nth is zero for every such weekday in the period, or the 1-based position
+// from the start (positive) or the end (negative) of the month or year.
+type Weekday struct {
+	Nth int
+	Day time.Weekday
+}
+
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+	FrequencyYearly  = "YEARLY"
+
+	// maxPeriods stops rules that can never produce an occurrence (e.g. BYMONTHDAY=31 with FREQ=MONTHLY;INTERVAL=12 starting in June)
+	maxPeriods = 10000
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid recurrence rule")
+
+	weekdays = map[string]time.Weekday{
+		"SU": time.Sunday,
+		"MO": time.Monday,
+		"TU": time.Tuesday,
+		"WE": time.Wednesday,
+		"TH": time.Thursday,
+		"FR": time.Friday,
+		"SA": time.Saturday,
+	}
+)
+
+func Parse(rule string) (*Rule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, ErrInvalidRule
+	}
+
+	switch strings.ToUpper(rule) {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+		return &Rule{Frequency: strings.ToUpper(rule), Interval: 1}, nil
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Frequency = strings.ToUpper(value)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = ErrInvalidRule
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = ErrInvalidRule
+			}
+		case "UNTIL":
+			r.Until, r.UntilDate, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		default:
+			err = ErrInvalidRule
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+	}
+
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return nil, fmt.Errorf("%w: unsupported frequency %q", ErrInvalidRule, r.Frequency)
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL cannot be used together", ErrInvalidRule)
+	}
+
+	for _, d := range r.ByDay {
+		if d.Nth != 0 && r.Frequency != FrequencyMonthly && r.Frequency != FrequencyYearly {
+			return nil, fmt.Errorf("%w: numbered BYDAY is only allowed for MONTHLY or YEARLY", ErrInvalidRule)
+		}
+	}
+
+	if len(r.ByMonthDay) > 0 && r.Frequency == FrequencyWeekly {
+		return nil, fmt.Errorf("%w: BYMONTHDAY is not allowed for WEEKLY", ErrInvalidRule)
+	}
+
+	return r, nil
+}
+
+// Between returns the occurrences of the rule starting at start that fall within [from, to], in order.
+// Candidates before start are dropped, and COUNT is counted from start regardless of from.
+func (r *Rule) Between(start time.Time, from time.Time, to time.Time) []time.Time {
+	var occurrences []time.Time
+	until := r.until(start.Location())
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.candidates(start, period)
+		if len(candidates) == 0 && r.periodStart(start, period).After(to) {
+			break
+		}
+
+		for _, c := range candidates {
+			if c.Before(start) {
+				continue
+			}
+
+			if c.After(to) || (!until.IsZero() && c.After(until)) {
+				return occurrences
+			}
+
+			count++
+			if r.Count > 0 && count > r.Count {
+				return occurrences
+			}
+
+			if !c.Before(from) {
+				occurrences = append(occurrences, c)
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// until is the last moment an occurrence can start at, or zero without UNTIL.
+func (r *Rule) until(location *time.Location) time.Time {
+	if !r.UntilDate {
+		return r.Until
+	}
+
+	year, month, day := r.Until.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, location).Add(-time.Nanosecond)
+}
+
+func (r *Rule) periodStart(start time.Time, period int) time.Time {
+	step := period * r.Interval
+	switch r.Frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, step)
+	case FrequencyWeekly:
+		return startOfWeek(start).AddDate(0, 0, 7*step)
+	case FrequencyMonthly:
+		return time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, start.Location())
+	default:
+		return time.Date(start.Year()+step, time.January, 1, 0, 0, 0, 0, start.Location())
+	}
+}
+
+// candidates lists the occurrences of one period, sorted and at the time of day of start.
+func (r *Rule) candidates(start time.Time, period int) []time.Time {
+	periodStart := r.periodStart(start, period)
+	var days []time.Time
+	switch r.Frequency {
+	case FrequencyDaily:
+		days = []time.Time{periodStart}
+	case FrequencyWeekly:
+		if len(r.ByDay) == 0 {
+			days = []time.Time{periodStart.AddDate(0, 0, int(start.Weekday()+6)%7)}
+			break
+		}
+		for i := 0; i < 7; i++ {
+			days = append(days, periodStart.AddDate(0, 0, i))
+		}
+	case FrequencyMonthly:
+		days = r.daysInRange(start, periodStart, periodStart.AddDate(0, 1, 0))
+	default:
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			day := time.Date(periodStart.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+			if day.Month() == start.Month() {
+				days = []time.Time{day}
+			}
+			break
+		}
+		days = r.daysInRange(start, periodStart, periodStart.AddDate(1, 0, 0))
+	}
+
+	var occurrences []time.Time
+	for _, d := range days {
+		if !r.matchByDay(d, days) || !r.matchByMonthDay(d) {
+			continue
+		}
+
+		occurrences = append(occurrences, time.Date(d.Year(), d.Month(), d.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location()))
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Before(occurrences[j])
+	})
+
+	return occurrences
+}
+
+// daysInRange returns every day in [from, to) when BYDAY or BYMONTHDAY narrows them down,
+// otherwise only the day of the month of start, if the month has it.
+func (r *Rule) daysInRange(start time.Time, from time.Time, to time.Time) []time.Time {
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		day := time.Date(from.Year(), from.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+		if day.Month() != from.Month() {
+			return nil
+		}
+		return []time.Time{day}
+	}
+
+	var days []time.Time
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+
+	return days
+}
+
+// matchByDay checks a day against BYDAY, where a numbered weekday counts within the days of the period.
+func (r *Rule) matchByDay(day time.Time, period []time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, w := range r.ByDay {
+		if day.Weekday() != w.Day {
+			continue
+		}
+
+		if w.Nth == 0 {
+			return true
+		}
+
+		var same []time.Time
+		for _, p := range period {
+			if p.Weekday() == w.Day {
+				same = append(same, p)
+			}
+		}
+
+		index := w.Nth - 1
+		if w.Nth < 0 {
+			index = len(same) + w.Nth
+		}
+
+		if index >= 0 && index < len(same) && same[index].Equal(day) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Rule) matchByMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, md := range r.ByMonthDay {
+		if md == day.Day() || (md < 0 && lastDay+md+1 == day.Day()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func startOfWeek(t time.Time) time.Time {
+	// RRULE weeks start on Monday unless WKST says otherwise
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// parseUntil also reports whether UNTIL is a date only, see Rule.UntilDate.
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+
+	for _, layout := range []string{"20060102T150405Z", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, false, nil
+		}
+	}
+
+	return time.Time{}, false, ErrInvalidRule
+}
+
+func parseByDay(value string) ([]Weekday, error) {
+	var days []Weekday
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, ErrInvalidRule
+		}
+
+		day, exists := weekdays[item[len(item)-2:]]
+		if !exists {
+			return nil, ErrInvalidRule
+		}
+
+		var nth int
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, ErrInvalidRule
+			}
+			nth = n
+		}
+
+		days = append(days, Weekday{Nth: nth, Day: day})
+	}
+
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, ErrInvalidRule
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,169 @@
+package recurrence
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    *Rule
+		wantErr bool
+	}{
+		{
+			name: "plain frequency",
+			rule: "weekly",
+			want: &Rule{Frequency: FrequencyWeekly, Interval: 1},
+		},
+		{
+			name: "rrule prefix",
+			rule: "RRULE:FREQ=DAILY;INTERVAL=2",
+			want: &Rule{Frequency: FrequencyDaily, Interval: 2},
+		},
+		{
+			name: "count and weekdays",
+			rule: "FREQ=WEEKLY;COUNT=4;BYDAY=SU,WE",
+			want: &Rule{Frequency: FrequencyWeekly, Interval: 1, Count: 4, ByDay: []Weekday{{Day: time.Sunday}, {Day: time.Wednesday}}},
+		},
+		{
+			name: "numbered weekday",
+			rule: "FREQ=MONTHLY;BYDAY=-1FR",
+			want: &Rule{Frequency: FrequencyMonthly, Interval: 1, ByDay: []Weekday{{Nth: -1, Day: time.Friday}}},
+		},
+		{
+			name: "month days",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=1,-1",
+			want: &Rule{Frequency: FrequencyMonthly, Interval: 1, ByMonthDay: []int{1, -1}},
+		},
+		{
+			name: "until date",
+			rule: "FREQ=WEEKLY;UNTIL=20261231",
+			want: &Rule{Frequency: FrequencyWeekly, Interval: 1, Until: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), UntilDate: true},
+		},
+		{
+			name: "until date time",
+			rule: "FREQ=WEEKLY;UNTIL=20261231T120000Z",
+			want: &Rule{Frequency: FrequencyWeekly, Interval: 1, Until: time.Date(2026, 12, 31, 12, 0, 0, 0, time.UTC)},
+		},
+		{name: "empty", rule: "", wantErr: true},
+		{name: "missing frequency", rule: "INTERVAL=2", wantErr: true},
+		{name: "unsupported frequency", rule: "FREQ=HOURLY", wantErr: true},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "unknown part", rule: "FREQ=DAILY;WKST=MO", wantErr: true},
+		{name: "count with until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20261231", wantErr: true},
+		{name: "numbered weekday weekly", rule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "month days weekly", rule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{name: "invalid weekday", rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "invalid month day", rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{name: "invalid until", rule: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.rule)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRule) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidRule", tt.rule, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	jakarta := time.FixedZone("Asia/Jakarta", 7*60*60)
+	at := func(year int, month time.Month, day int, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, jakarta)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		from  time.Time
+		to    time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "weekly on the weekday of start",
+			rule:  "FREQ=WEEKLY",
+			start: at(2026, 1, 4, 9),
+			from:  at(2026, 1, 1, 0),
+			to:    at(2026, 1, 25, 0),
+			want:  []time.Time{at(2026, 1, 4, 9), at(2026, 1, 11, 9), at(2026, 1, 18, 9)},
+		},
+		{
+			name:  "count is counted from start",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: at(2026, 1, 1, 9),
+			from:  at(2026, 1, 2, 0),
+			to:    at(2026, 1, 31, 0),
+			want:  []time.Time{at(2026, 1, 2, 9), at(2026, 1, 3, 9)},
+		},
+		{
+			name:  "last friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: at(2026, 1, 1, 19),
+			from:  at(2026, 1, 1, 0),
+			to:    at(2026, 3, 31, 0),
+			want:  []time.Time{at(2026, 1, 30, 19), at(2026, 2, 27, 19), at(2026, 3, 27, 19)},
+		},
+		{
+			name:  "month day missing in short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: at(2026, 1, 31, 9),
+			from:  at(2026, 1, 1, 0),
+			to:    at(2026, 5, 31, 23),
+			want:  []time.Time{at(2026, 1, 31, 9), at(2026, 3, 31, 9), at(2026, 5, 31, 9)},
+		},
+		{
+			name:  "until date includes the evening of that day",
+			rule:  "FREQ=DAILY;UNTIL=20261231",
+			start: at(2026, 12, 29, 19),
+			from:  at(2026, 12, 1, 0),
+			to:    at(2027, 1, 31, 0),
+			want:  []time.Time{at(2026, 12, 29, 19), at(2026, 12, 30, 19), at(2026, 12, 31, 19)},
+		},
+		{
+			name:  "until date time is exact",
+			rule:  "FREQ=DAILY;UNTIL=20261231T100000Z",
+			start: at(2026, 12, 29, 19),
+			from:  at(2026, 12, 1, 0),
+			to:    at(2027, 1, 31, 0),
+			want:  []time.Time{at(2026, 12, 29, 19), at(2026, 12, 30, 19)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+
+			got := rule.Between(tt.start, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Between()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
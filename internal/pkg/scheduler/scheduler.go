@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"go-community/internal/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is work that runs once when the scheduler starts and then every Interval until it stops.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(jobs ...Job) *Scheduler {
+	return &Scheduler{jobs: jobs}
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				s.run(ctx, job)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+}
+
+// Stop cancels the running jobs and waits for them to return, or until ctx is done.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Logger.Error("[SCHEDULER-PANIC]", zap.String("job", job.Name), zap.Any("panic", r))
+		}
+	}()
+
+	logger.Logger.Info("[SCHEDULER]", zap.String("job", job.Name), zap.String("status", "start"))
+	if err := job.Run(ctx); err != nil {
+		logger.Logger.Error("[SCHEDULER-ERROR]", zap.String("job", job.Name), zap.Error(err))
+		return
+	}
+	logger.Logger.Info("[SCHEDULER]", zap.String("job", job.Name), zap.String("status", "success"))
+}
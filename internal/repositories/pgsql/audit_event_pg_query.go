@@ -0,0 +1,111 @@
+package pgsql
+
+import (
+	"fmt"
+	"go-community/internal/models"
+	"go-community/internal/pkg/cursor"
+	"strings"
+)
+
+var (
+	baseQueryGetAllAuditEvent = `
+	SELECT
+		ae.id AS id,
+		ae.actor_community_id AS actor_community_id,
+		ae.action AS action,
+		ae.entity_type AS entity_type,
+		ae.entity_id AS entity_id,
+		ae.before AS before,
+		ae.after AS after,
+		ae.request_id AS request_id,
+		ae.created_at AS created_at
+	FROM
+		audit_events ae
+	WHERE
+		1=1`
+
+	queryCountAllAuditEvent = `
+	SELECT COUNT(*)
+	FROM audit_events ae
+	WHERE
+		1=1`
+)
+
+func buildAuditEventFilter(queryBuilder *strings.Builder, param models.GetAllAuditEventCursorParam) []interface{} {
+	var args []interface{}
+
+	if param.ActorCommunityId != "" {
+		queryBuilder.WriteString(" AND ae.actor_community_id = ?")
+		args = append(args, param.ActorCommunityId)
+	}
+	if param.Action != "" {
+		queryBuilder.WriteString(" AND ae.action = ?")
+		args = append(args, param.Action)
+	}
+	if param.EntityType != "" {
+		queryBuilder.WriteString(" AND ae.entity_type = ?")
+		args = append(args, param.EntityType)
+	}
+	if param.EntityId != "" {
+		queryBuilder.WriteString(" AND ae.entity_id = ?")
+		args = append(args, param.EntityId)
+	}
+	if param.RequestId != "" {
+		queryBuilder.WriteString(" AND ae.request_id = ?")
+		args = append(args, param.RequestId)
+	}
+	if param.CreatedFrom != nil {
+		queryBuilder.WriteString(" AND ae.created_at >= ?")
+		args = append(args, *param.CreatedFrom)
+	}
+	if param.CreatedUntil != nil {
+		queryBuilder.WriteString(" AND ae.created_at < ?")
+		args = append(args, *param.CreatedUntil)
+	}
+
+	return args
+}
+
+func BuildCountGetAllAuditEvent(param models.GetAllAuditEventCursorParam) (string, []interface{}, error) {
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString(queryCountAllAuditEvent)
+	args := buildAuditEventFilter(&queryBuilder, param)
+
+	return queryBuilder.String(), args, nil
+}
+
+func BuildQueryGetAllAuditEvent(param models.GetAllAuditEventCursorParam) (string, []interface{}, error) {
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString(baseQueryGetAllAuditEvent)
+	args := buildAuditEventFilter(&queryBuilder, param)
+
+	isForward := param.Direction != "prev"
+	if param.Cursor != "" {
+		var auditCursor models.GetAllAuditEventCursor
+		if _, err := cursor.DecryptCursorToStruct(param.Cursor, &auditCursor); err != nil {
+			return "", nil, err
+		}
+
+		operator := "<"
+		if !isForward {
+			operator = ">"
+		}
+
+		queryBuilder.WriteString(fmt.Sprintf(" AND (ae.created_at, ae.id) %s (?, ?)", operator))
+		args = append(args, auditCursor.CreatedAt, auditCursor.ID)
+	}
+
+	// Going back walks up from the cursor, the records are reversed again afterwards
+	if isForward {
+		queryBuilder.WriteString(" ORDER BY ae.created_at DESC, ae.id DESC")
+	} else {
+		queryBuilder.WriteString(" ORDER BY ae.created_at ASC, ae.id ASC")
+	}
+
+	queryBuilder.WriteString(" LIMIT ?")
+	args = append(args, param.Limit+1)
+
+	return queryBuilder.String(), args, nil
+}
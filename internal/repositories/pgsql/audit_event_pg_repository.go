@@ -0,0 +1,102 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"go-community/internal/models"
+	"go-community/internal/pkg/cursor"
+
+	"gorm.io/gorm"
+)
+
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) (err error)
+	GetAll(ctx context.Context, param models.GetAllAuditEventCursorParam) (output []models.AuditEvent, pagination *models.PaginationOutput, err error)
+}
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+func (aer *auditEventRepository) Create(ctx context.Context, event *models.AuditEvent) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return aer.db.Create(event).Error
+}
+
+func (aer *auditEventRepository) GetAll(ctx context.Context, param models.GetAllAuditEventCursorParam) (output []models.AuditEvent, pagination *models.PaginationOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	if param.Limit <= 0 {
+		param.Limit = 10
+	}
+
+	queryList, paramList, err := BuildQueryGetAllAuditEvent(param)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build list query: %w", err)
+	}
+
+	var records []models.AuditEvent
+	if err := aer.db.Raw(queryList, paramList...).Scan(&records).Error; err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+
+	queryCount, paramCount, err := BuildCountGetAllAuditEvent(param)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+	if err := aer.db.Raw(queryCount, paramCount...).Scan(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("count query execution failed: %w", err)
+	}
+
+	var next, prev string
+	if len(records) > 0 {
+		hasMore := len(records) > param.Limit
+		isForward := param.Direction != "prev" && param.Cursor != ""
+		isBackward := param.Direction == "prev" && param.Cursor != ""
+
+		if hasMore {
+			records = records[:param.Limit]
+		}
+
+		if isBackward {
+			for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+				records[i], records[j] = records[j], records[i]
+			}
+		}
+
+		if isBackward || hasMore {
+			lastRecord := records[len(records)-1]
+			next = cursor.EncryptCursorFromStruct(models.GetAllAuditEventCursor{
+				ID:        lastRecord.ID,
+				CreatedAt: lastRecord.CreatedAt,
+			})
+		}
+
+		if isForward || (hasMore && isBackward) {
+			firstRecord := records[0]
+			prev = cursor.EncryptCursorFromStruct(models.GetAllAuditEventCursor{
+				ID:        firstRecord.ID,
+				CreatedAt: firstRecord.CreatedAt,
+			})
+		}
+	}
+
+	pagination = &models.PaginationOutput{
+		Next:  next,
+		Prev:  prev,
+		Total: total,
+	}
+
+	return records, pagination, nil
+}
@@ -0,0 +1,44 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CoolMeetingAttendanceRepository interface {
+	Upsert(ctx context.Context, attendances *[]models.CoolMeetingAttendance) (err error)
+	GetManyByMeetingId(ctx context.Context, meetingId int) (output []models.CoolMeetingAttendanceDBOutput, err error)
+}
+
+type coolMeetingAttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewCoolMeetingAttendanceRepository(db *gorm.DB) CoolMeetingAttendanceRepository {
+	return &coolMeetingAttendanceRepository{db: db}
+}
+
+func (cmar *coolMeetingAttendanceRepository) Upsert(ctx context.Context, attendances *[]models.CoolMeetingAttendance) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmar.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "meeting_id"}, {Name: "community_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "reason", "marked_by", "updated_at"}),
+	}).Create(attendances).Error
+}
+
+// GetManyByMeetingId lists the members of the cool when the meeting started, with their attendance if it was marked.
+func (cmar *coolMeetingAttendanceRepository) GetManyByMeetingId(ctx context.Context, meetingId int) (output []models.CoolMeetingAttendanceDBOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cmar.db.Raw(queryGetCoolMeetingAttendances, meetingId).Scan(&output).Error
+
+	return output, err
+}
@@ -0,0 +1,52 @@
+package pgsql
+
+var (
+	queryGetCoolMeetingAttendances = `
+	SELECT
+		m.id AS meeting_id,
+		cm.community_id AS community_id,
+		u.name AS name,
+		cm.role AS role,
+		COALESCE(a.status, '') AS status,
+		COALESCE(a.reason, '') AS reason,
+		COALESCE(a.marked_by, '') AS marked_by
+	FROM
+		cool_meetings m
+	JOIN cool_members cm ON cm.cool_id = m.cool_id AND cm.joined_at <= m.start_at AND (cm.left_at IS NULL OR cm.left_at > m.start_at)
+	JOIN users u ON u.community_id = cm.community_id AND u.deleted_at IS NULL
+	LEFT JOIN cool_meeting_attendances a ON a.meeting_id = m.id AND a.community_id = cm.community_id
+	WHERE
+		m.id = ?
+	ORDER BY
+		CASE cm.role WHEN 'leader' THEN 0 WHEN 'core' THEN 1 ELSE 2 END, u.name ASC`
+
+	queryCountHeldCoolMeetings = `
+	SELECT
+		COUNT(*)
+	FROM
+		cool_meetings
+	WHERE
+		cool_id = ? AND status = 'active' AND start_at >= ? AND start_at < ? AND start_at <= ?`
+
+	queryGetCoolAttendanceReport = `
+	SELECT
+		u.community_id AS community_id,
+		u.name AS name,
+		COALESCE(cur.role, '') AS role,
+		cur.id IS NOT NULL AS is_member,
+		COUNT(DISTINCT m.id) AS total_meetings,
+		COUNT(DISTINCT m.id) FILTER (WHERE a.status = 'present') AS present_count,
+		COUNT(DISTINCT m.id) FILTER (WHERE a.status = 'permit') AS permit_count
+	FROM
+		cool_meetings m
+	JOIN cool_members cm ON cm.cool_id = m.cool_id AND cm.joined_at <= m.start_at AND (cm.left_at IS NULL OR cm.left_at > m.start_at)
+	JOIN users u ON u.community_id = cm.community_id AND u.deleted_at IS NULL
+	LEFT JOIN cool_meeting_attendances a ON a.meeting_id = m.id AND a.community_id = cm.community_id
+	LEFT JOIN cool_members cur ON cur.community_id = cm.community_id AND cur.cool_id = m.cool_id AND cur.left_at IS NULL
+	WHERE
+		m.cool_id = ? AND m.status = 'active' AND m.start_at >= ? AND m.start_at < ? AND m.start_at <= ?
+	GROUP BY
+		u.community_id, u.name, cur.id, cur.role
+	ORDER BY
+		is_member DESC, u.name ASC`
+)
@@ -0,0 +1,135 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CoolMeetingRepository interface {
+	Create(ctx context.Context, meeting *models.CoolMeeting) (err error)
+	BulkCreate(ctx context.Context, meetings *[]models.CoolMeeting) (err error)
+	GetOneById(ctx context.Context, id int) (meeting models.CoolMeeting, err error)
+	GetManyByCoolId(ctx context.Context, coolId int, from time.Time, until time.Time) (meetings []models.CoolMeeting, err error)
+	GetRecurrenceAtsByCoolId(ctx context.Context, coolId int, from time.Time) (recurrenceAts []time.Time, err error)
+	UpdateStatusById(ctx context.Context, id int, status string) (err error)
+	CancelUpcomingByCoolId(ctx context.Context, coolId int, from time.Time) (err error)
+	DeleteUpcomingRecurringByCoolId(ctx context.Context, coolId int, from time.Time) (err error)
+	CountHeldByCoolId(ctx context.Context, coolId int, from time.Time, until time.Time, now time.Time) (count int64, err error)
+	GetAttendanceReport(ctx context.Context, coolId int, from time.Time, until time.Time, now time.Time) (output []models.GetCoolAttendanceDBOutput, err error)
+}
+
+type coolMeetingRepository struct {
+	db *gorm.DB
+}
+
+func NewCoolMeetingRepository(db *gorm.DB) CoolMeetingRepository {
+	return &coolMeetingRepository{db: db}
+}
+
+func (cmr *coolMeetingRepository) Create(ctx context.Context, meeting *models.CoolMeeting) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Create(meeting).Error
+}
+
+// BulkCreate skips the occurrences that were generated already, so concurrent generation of a cool cannot duplicate them.
+func (cmr *coolMeetingRepository) BulkCreate(ctx context.Context, meetings *[]models.CoolMeeting) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(meetings).Error
+}
+
+func (cmr *coolMeetingRepository) GetOneById(ctx context.Context, id int) (meeting models.CoolMeeting, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cmr.db.Where("id = ?", id).Find(&meeting).Error
+
+	return meeting, err
+}
+
+func (cmr *coolMeetingRepository) GetManyByCoolId(ctx context.Context, coolId int, from time.Time, until time.Time) (meetings []models.CoolMeeting, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cmr.db.Where("cool_id = ? AND start_at >= ? AND start_at < ?", coolId, from, until).Order("start_at ASC").Find(&meetings).Error
+
+	return meetings, err
+}
+
+// GetRecurrenceAtsByCoolId lists the occurrences of the meeting rule from the time that have a meeting, cancelled or not.
+func (cmr *coolMeetingRepository) GetRecurrenceAtsByCoolId(ctx context.Context, coolId int, from time.Time) (recurrenceAts []time.Time, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cmr.db.Model(&models.CoolMeeting{}).Where("cool_id = ? AND recurrence_at >= ?", coolId, from).Pluck("recurrence_at", &recurrenceAts).Error
+
+	return recurrenceAts, err
+}
+
+func (cmr *coolMeetingRepository) UpdateStatusById(ctx context.Context, id int, status string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Model(&models.CoolMeeting{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// CancelUpcomingByCoolId cancels the active meetings of the cool that start after the time.
+func (cmr *coolMeetingRepository) CancelUpcomingByCoolId(ctx context.Context, coolId int, from time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Model(&models.CoolMeeting{}).Where("cool_id = ? AND status = ? AND start_at > ?", coolId, models.CoolMeetingStatusActive, from).Updates(map[string]interface{}{
+		"status":     models.CoolMeetingStatusCancelled,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// DeleteUpcomingRecurringByCoolId removes the meetings generated from the meeting rule of the cool that start after the
+// time, so they can be generated again from a new rule. Attendance cannot be marked on them yet.
+func (cmr *coolMeetingRepository) DeleteUpcomingRecurringByCoolId(ctx context.Context, coolId int, from time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Where("cool_id = ? AND recurrence_at IS NOT NULL AND start_at > ?", coolId, from).Delete(&models.CoolMeeting{}).Error
+}
+
+// CountHeldByCoolId counts the active meetings of the cool that started within [from, until) by now.
+func (cmr *coolMeetingRepository) CountHeldByCoolId(ctx context.Context, coolId int, from time.Time, until time.Time, now time.Time) (count int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cmr.db.Raw(queryCountHeldCoolMeetings, coolId, from, until, now).Scan(&count).Error
+
+	return count, err
+}
+
+// GetAttendanceReport counts, for everyone who was in the cool during the meetings held within [from, until) by now,
+// the meetings they were a member for and the ones they were present or permitted at.
+func (cmr *coolMeetingRepository) GetAttendanceReport(ctx context.Context, coolId int, from time.Time, until time.Time, now time.Time) (output []models.GetCoolAttendanceDBOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cmr.db.Raw(queryGetCoolAttendanceReport, coolId, from, until, now).Scan(&output).Error
+
+	return output, err
+}
@@ -0,0 +1,22 @@
+package pgsql
+
+var (
+	queryGetCoolMembersByCoolId = `
+	SELECT
+		cm.id AS id,
+		cm.cool_id AS cool_id,
+		cm.community_id AS community_id,
+		u.name AS name,
+		u.phone_number AS phone_number,
+		u.email AS email,
+		cm.role AS role,
+		cm.joined_at AS joined_at,
+		cm.left_at AS left_at
+	FROM
+		cool_members cm
+	JOIN users u ON u.community_id = cm.community_id AND u.deleted_at IS NULL
+	WHERE
+		cm.cool_id = ? AND cm.left_at IS NULL
+	ORDER BY
+		CASE cm.role WHEN 'leader' THEN 0 WHEN 'core' THEN 1 ELSE 2 END, u.name ASC`
+)
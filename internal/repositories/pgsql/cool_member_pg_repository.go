@@ -0,0 +1,69 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CoolMemberRepository interface {
+	Create(ctx context.Context, member *models.CoolMember) (err error)
+	GetCurrentByCommunityId(ctx context.Context, communityId string) (member *models.CoolMember, err error)
+	GetCurrentByCoolId(ctx context.Context, coolId int) (output []models.CoolMemberDBOutput, err error)
+	Leave(ctx context.Context, id int, leftAt time.Time) (err error)
+}
+
+type coolMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewCoolMemberRepository(db *gorm.DB) CoolMemberRepository {
+	return &coolMemberRepository{db: db}
+}
+
+func (cmr *coolMemberRepository) Create(ctx context.Context, member *models.CoolMember) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Create(member).Error
+}
+
+// GetCurrentByCommunityId returns the open membership of the user, or nil when they are not in a cool.
+func (cmr *coolMemberRepository) GetCurrentByCommunityId(ctx context.Context, communityId string) (member *models.CoolMember, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	var members []models.CoolMember
+	err = cmr.db.Where("community_id = ? AND left_at IS NULL", communityId).Limit(1).Find(&members).Error
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+
+	return &members[0], nil
+}
+
+func (cmr *coolMemberRepository) GetCurrentByCoolId(ctx context.Context, coolId int) (output []models.CoolMemberDBOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	var members []models.CoolMemberDBOutput
+	err = cmr.db.Raw(queryGetCoolMembersByCoolId, coolId).Scan(&members).Error
+
+	return members, err
+}
+
+func (cmr *coolMemberRepository) Leave(ctx context.Context, id int, leftAt time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cmr.db.Model(&models.CoolMember{}).Where("id = ? AND left_at IS NULL", id).Updates(map[string]interface{}{
+		"left_at":    leftAt,
+		"updated_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,40 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CoolNewJoinerNoteRepository interface {
+	Create(ctx context.Context, note *models.CoolNewJoinerNote) (err error)
+	GetManyByNewJoinerId(ctx context.Context, newJoinerId int) (output []models.CoolNewJoinerNoteDBOutput, err error)
+}
+
+type coolNewJoinerNoteRepository struct {
+	db *gorm.DB
+}
+
+func NewCoolNewJoinerNoteRepository(db *gorm.DB) CoolNewJoinerNoteRepository {
+	return &coolNewJoinerNoteRepository{db: db}
+}
+
+func (cnjnr *coolNewJoinerNoteRepository) Create(ctx context.Context, note *models.CoolNewJoinerNote) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cnjnr.db.Create(note).Error
+}
+
+// GetManyByNewJoinerId lists the notes on the new joiner, newest first.
+func (cnjnr *coolNewJoinerNoteRepository) GetManyByNewJoinerId(ctx context.Context, newJoinerId int) (output []models.CoolNewJoinerNoteDBOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cnjnr.db.Raw(queryGetCoolNewJoinerNotes, newJoinerId).Scan(&output).Error
+
+	return output, err
+}
@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"fmt"
+	"github.com/lib/pq"
 	"go-community/internal/models"
 	"go-community/internal/pkg/cursor"
 	"strings"
@@ -22,11 +23,21 @@ var (
 		cnj.location AS location,
 		cnj.updated_by AS updated_by,
 		cnj.status AS status,
+		cnj.cool_id AS cool_id,
+		c.name AS cool_name,
+		cnj.follow_up_community_id AS follow_up_community_id,
+		fu.name AS follow_up_name,
+		cnj.assigned_at AS assigned_at,
+		cnj.followed_up_at AS followed_up_at,
+		cnj.community_id AS community_id,
+		cnj.converted_at AS converted_at,
 		cnj.created_at AS created_at,
 		cnj.updated_at AS updated_at,
 		cnj.deleted_at AS deleted_at
 	FROM
 		cool_new_joiners cnj
+	LEFT JOIN cools c ON c.id = cnj.cool_id
+	LEFT JOIN users fu ON fu.community_id = cnj.follow_up_community_id
 	WHERE
 		1=1`
 
@@ -34,6 +45,34 @@ var (
 		SELECT COUNT(*)
 		FROM cool_new_joiners cnj
 	`
+
+	queryCountOpenCoolNewJoinerByFollowUp = `
+	SELECT
+		follow_up_community_id,
+		COUNT(*) AS total
+	FROM
+		cool_new_joiners
+	WHERE
+		deleted_at IS NULL AND converted_at IS NULL AND status IN ('pending', 'followed') AND follow_up_community_id = ANY(?)
+	GROUP BY
+		follow_up_community_id`
+
+	queryGetCoolNewJoinerNotes = `
+	SELECT
+		n.id AS id,
+		n.new_joiner_id AS new_joiner_id,
+		n.note AS note,
+		n.status AS status,
+		n.created_by AS created_by,
+		COALESCE(u.name, '') AS created_by_name,
+		n.created_at AS created_at
+	FROM
+		cool_new_joiner_notes n
+	LEFT JOIN users u ON u.community_id = n.created_by
+	WHERE
+		n.new_joiner_id = ?
+	ORDER BY
+		n.created_at DESC, n.id DESC`
 )
 
 func BuildCountGetAllCoolNewJoiner(param models.GetAllCoolNewJoinerCursorParam) (string, []interface{}, error) {
@@ -56,6 +95,10 @@ func BuildCountGetAllCoolNewJoiner(param models.GetAllCoolNewJoinerCursorParam)
 		queryBuilder.WriteString(" AND cnj.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(cnj.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.MaritalStatus != "" {
 		queryBuilder.WriteString(" AND cnj.marital_status = ?")
 		args = append(args, param.MaritalStatus)
@@ -76,6 +119,18 @@ func BuildCountGetAllCoolNewJoiner(param models.GetAllCoolNewJoinerCursorParam)
 		queryBuilder.WriteString(" AND cnj.location = ?")
 		args = append(args, param.Location)
 	}
+	if param.CoolId != 0 {
+		queryBuilder.WriteString(" AND cnj.cool_id = ?")
+		args = append(args, param.CoolId)
+	}
+	if param.FollowUpCommunityId != "" {
+		queryBuilder.WriteString(" AND cnj.follow_up_community_id = ?")
+		args = append(args, param.FollowUpCommunityId)
+	}
+	if param.OverdueBefore != nil {
+		queryBuilder.WriteString(" AND cnj.status = 'pending' AND cnj.created_at < ?")
+		args = append(args, *param.OverdueBefore)
+	}
 
 	return queryBuilder.String(), args, nil
 }
@@ -99,6 +154,10 @@ func BuildQueryGetAllCoolNewJoiner(param models.GetAllCoolNewJoinerCursorParam)
 		queryBuilder.WriteString(" AND cnj.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(cnj.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.MaritalStatus != "" {
 		queryBuilder.WriteString(" AND cnj.marital_status = ?")
 		args = append(args, param.MaritalStatus)
@@ -119,6 +178,18 @@ func BuildQueryGetAllCoolNewJoiner(param models.GetAllCoolNewJoinerCursorParam)
 		queryBuilder.WriteString(" AND cnj.location = ?")
 		args = append(args, param.Location)
 	}
+	if param.CoolId != 0 {
+		queryBuilder.WriteString(" AND cnj.cool_id = ?")
+		args = append(args, param.CoolId)
+	}
+	if param.FollowUpCommunityId != "" {
+		queryBuilder.WriteString(" AND cnj.follow_up_community_id = ?")
+		args = append(args, param.FollowUpCommunityId)
+	}
+	if param.OverdueBefore != nil {
+		queryBuilder.WriteString(" AND cnj.status = 'pending' AND cnj.created_at < ?")
+		args = append(args, *param.OverdueBefore)
+	}
 
 	isForward := param.Direction != "prev"
 	if param.Cursor != "" {
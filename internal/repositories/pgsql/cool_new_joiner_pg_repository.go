@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"go-community/internal/models"
 	"go-community/internal/pkg/cursor"
+	"time"
+
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -13,6 +16,12 @@ type CoolNewJoinerRepository interface {
 	GetAll(ctx context.Context, param models.GetAllCoolNewJoinerCursorParam) (output []models.GetCoolNewJoinerResponse, pagination *models.PaginationOutput, err error)
 	GetById(ctx context.Context, id int) (output *models.CoolNewJoiner, err error)
 	Update(ctx context.Context, question *models.CoolNewJoiner) (err error)
+	Assign(ctx context.Context, id int, coolId int, followUpCommunityId string, assignedAt time.Time) (err error)
+	Convert(ctx context.Context, id int, communityId string, status string, convertedAt time.Time) (err error)
+	CountOpenByFollowUp(ctx context.Context, communityIds []string) (output []models.CountCoolNewJoinerByFollowUpDBOutput, err error)
+	GetOverdue(ctx context.Context, before time.Time) (output []models.CoolNewJoiner, err error)
+	ClaimSlaReminder(ctx context.Context, id int, remindedAt time.Time) (rows int64, err error)
+	UpdateCoolIdByCoolId(ctx context.Context, coolId int, newCoolId int) (err error)
 }
 
 type coolNewJoinerRepository struct {
@@ -125,3 +134,78 @@ func (cnjr *coolNewJoinerRepository) Update(ctx context.Context, question *model
 
 	return cnjr.db.Model(&models.CoolNewJoiner{}).Where("id = ?", question.ID).Updates(question).Error
 }
+
+// Assign gives the new joiner to the cool and the follow up, who is reminded afresh once they are overdue.
+func (cnjr *coolNewJoinerRepository) Assign(ctx context.Context, id int, coolId int, followUpCommunityId string, assignedAt time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cnjr.db.Model(&models.CoolNewJoiner{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"cool_id":                coolId,
+		"follow_up_community_id": followUpCommunityId,
+		"assigned_at":            assignedAt,
+		"sla_reminded_at":        nil,
+		"updated_at":             time.Now(),
+	}).Error
+}
+
+func (cnjr *coolNewJoinerRepository) Convert(ctx context.Context, id int, communityId string, status string, convertedAt time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cnjr.db.Model(&models.CoolNewJoiner{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"community_id":   communityId,
+		"converted_at":   convertedAt,
+		"status":         status,
+		"followed_up_at": gorm.Expr("COALESCE(followed_up_at, ?)", convertedAt),
+		"updated_at":     time.Now(),
+	}).Error
+}
+
+func (cnjr *coolNewJoinerRepository) CountOpenByFollowUp(ctx context.Context, communityIds []string) (output []models.CountCoolNewJoinerByFollowUpDBOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cnjr.db.Raw(queryCountOpenCoolNewJoinerByFollowUp, pq.Array(communityIds)).Scan(&output).Error
+
+	return output, err
+}
+
+// GetOverdue lists the new joiners with a follow up that are still pending since before the time and were not reminded yet.
+func (cnjr *coolNewJoinerRepository) GetOverdue(ctx context.Context, before time.Time) (output []models.CoolNewJoiner, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = cnjr.db.Where("deleted_at IS NULL AND status = ? AND created_at < ? AND follow_up_community_id IS NOT NULL AND sla_reminded_at IS NULL", "pending", before).
+		Order("created_at ASC").Find(&output).Error
+
+	return output, err
+}
+
+// ClaimSlaReminder marks the new joiner as reminded unless someone else already did, in which case no row is affected.
+// Claiming in the transaction that sends the reminder keeps other instances of the app from sending it again.
+func (cnjr *coolNewJoinerRepository) ClaimSlaReminder(ctx context.Context, id int, remindedAt time.Time) (rows int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := cnjr.db.Model(&models.CoolNewJoiner{}).Where("id = ? AND sla_reminded_at IS NULL", id).Update("sla_reminded_at", remindedAt)
+
+	return result.RowsAffected, result.Error
+}
+
+// UpdateCoolIdByCoolId moves the new joiners of the cool that did not join yet over to the other cool.
+func (cnjr *coolNewJoinerRepository) UpdateCoolIdByCoolId(ctx context.Context, coolId int, newCoolId int) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return cnjr.db.Model(&models.CoolNewJoiner{}).Where("cool_id = ? AND converted_at IS NULL AND deleted_at IS NULL", coolId).Updates(map[string]interface{}{
+		"cool_id":    newCoolId,
+		"updated_at": time.Now(),
+	}).Error
+}
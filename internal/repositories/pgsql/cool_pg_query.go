@@ -1,7 +1,129 @@
 package pgsql
 
+import (
+	"fmt"
+	"go-community/internal/models"
+	"go-community/internal/pkg/cursor"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
 var (
 	queryCheckCoolById   = "SELECT EXISTS (SELECT 1 FROM cools WHERE id = ?)"
 	queryGetNameById     = "SELECT cools.id, cools.name FROM cools WHERE id = ?"
 	queryGetCoolsOptions = `SELECT id, name, campus_code, leader_community_ids, status FROM cools WHERE deleted_at IS NULL AND status = 'active'`
+
+	queryCountCurrentCoolMembers = `(SELECT COUNT(*) FROM cool_members cm WHERE cm.cool_id = c.id AND cm.left_at IS NULL)`
+
+	baseQuerySearchCool = `
+	SELECT
+		c.id AS id,
+		c.name AS name,
+		c.description AS description,
+		c.campus_code AS campus_code,
+		c.category AS category,
+		c.gender AS gender,
+		c.location_type AS location_type,
+		c.location_name AS location_name,
+		c.leader_community_ids AS leader_community_ids,
+		COALESCE(c.meeting_rule, '') AS meeting_rule,
+		c.meeting_start_at AS meeting_start_at,
+		c.meeting_duration_minutes AS meeting_duration_minutes,
+		c.capacity AS capacity,
+		` + queryCountCurrentCoolMembers + ` AS member_count
+	FROM
+		cools c
+	WHERE
+		c.deleted_at IS NULL AND c.status = 'active'`
+
+	queryCountSearchCool = `
+	SELECT COUNT(*)
+	FROM cools c
+	WHERE
+		c.deleted_at IS NULL AND c.status = 'active'`
 )
+
+func buildSearchCoolFilter(queryBuilder *strings.Builder, param models.SearchCoolCursorParam) []interface{} {
+	var args []interface{}
+
+	if param.Name != "" {
+		queryBuilder.WriteString(" AND c.name ILIKE ?")
+		args = append(args, "%"+param.Name+"%")
+	}
+	if param.CampusCode != "" {
+		queryBuilder.WriteString(" AND UPPER(c.campus_code) = UPPER(?)")
+		args = append(args, param.CampusCode)
+	}
+	if param.Category != "" {
+		queryBuilder.WriteString(" AND c.category = ?")
+		args = append(args, param.Category)
+	}
+	if param.Gender != "" {
+		// A cool for everyone takes either gender
+		queryBuilder.WriteString(" AND c.gender = ANY(?)")
+		args = append(args, pq.Array([]string{param.Gender, "all"}))
+	}
+	if param.MeetingByDay != "" {
+		// The days of a rule with BYDAY, every day of a daily one, or else the day its first meeting started
+		queryBuilder.WriteString(` AND COALESCE(c.meeting_rule, '') <> '' AND (c.meeting_rule ~* ? OR (c.meeting_rule !~* 'BYDAY=' AND (c.meeting_rule ~* 'FREQ=DAILY' OR EXTRACT(ISODOW FROM c.meeting_start_at AT TIME ZONE 'Asia/Jakarta') = ?)))`)
+		args = append(args, "BYDAY=[^;]*"+param.MeetingByDay, param.MeetingIsoDay)
+	}
+	if param.LocationType != "" {
+		queryBuilder.WriteString(" AND c.location_type = ?")
+		args = append(args, param.LocationType)
+	}
+	if param.Area != "" {
+		queryBuilder.WriteString(" AND c.location_name ILIKE ?")
+		args = append(args, "%"+param.Area+"%")
+	}
+	if param.IsOpen {
+		queryBuilder.WriteString(" AND (c.capacity IS NULL OR " + queryCountCurrentCoolMembers + " < c.capacity)")
+	}
+
+	return args
+}
+
+func BuildCountSearchCool(param models.SearchCoolCursorParam) (string, []interface{}, error) {
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString(queryCountSearchCool)
+	args := buildSearchCoolFilter(&queryBuilder, param)
+
+	return queryBuilder.String(), args, nil
+}
+
+func BuildQuerySearchCool(param models.SearchCoolCursorParam) (string, []interface{}, error) {
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString(baseQuerySearchCool)
+	args := buildSearchCoolFilter(&queryBuilder, param)
+
+	isForward := param.Direction != "prev"
+	if param.Cursor != "" {
+		var coolCursor models.SearchCoolCursor
+		if _, err := cursor.DecryptCursorToStruct(param.Cursor, &coolCursor); err != nil {
+			return "", nil, err
+		}
+
+		operator := ">"
+		if !isForward {
+			operator = "<"
+		}
+
+		queryBuilder.WriteString(fmt.Sprintf(" AND (c.name, c.id) %s (?, ?)", operator))
+		args = append(args, coolCursor.Name, coolCursor.ID)
+	}
+
+	// Going back walks down from the cursor, the records are reversed again afterwards
+	if isForward {
+		queryBuilder.WriteString(" ORDER BY c.name ASC, c.id ASC")
+	} else {
+		queryBuilder.WriteString(" ORDER BY c.name DESC, c.id DESC")
+	}
+
+	queryBuilder.WriteString(" LIMIT ?")
+	args = append(args, param.Limit+1)
+
+	return queryBuilder.String(), args, nil
+}
@@ -2,8 +2,12 @@ package pgsql
 
 import (
 	"context"
+	"fmt"
+	"github.com/lib/pq"
 	"go-community/internal/models"
+	"go-community/internal/pkg/cursor"
 	"gorm.io/gorm"
+	"time"
 )
 
 type CoolRepository interface {
@@ -12,6 +16,13 @@ type CoolRepository interface {
 	GetNameById(ctx context.Context, id int) (cool models.Cool, err error)
 	Create(ctx context.Context, cool *models.Cool) (err error)
 	GetAllOptions(ctx context.Context) (cool []models.GetAllCoolOptionsDBOutput, err error)
+	UpdateTeamsById(ctx context.Context, id int, leaderCommunityIds []string, coreCommunityIds []string) (err error)
+	GetAllWithMeetingRule(ctx context.Context, status string) (cools []models.Cool, err error)
+	GetAllByCampusCode(ctx context.Context, campusCode string, status string) (cools []models.Cool, err error)
+	Update(ctx context.Context, cool *models.Cool) (err error)
+	UpdateFacilitatorsById(ctx context.Context, id int, facilitatorCommunityIds []string) (err error)
+	UpdateStatusById(ctx context.Context, id int, status string) (err error)
+	Search(ctx context.Context, param models.SearchCoolCursorParam) (output []models.SearchCoolDBOutput, pagination *models.PaginationOutput, err error)
 }
 
 type coolRepository struct {
@@ -76,3 +87,141 @@ func (clr *coolRepository) GetAllOptions(ctx context.Context) (cool []models.Get
 
 	return cl, err
 }
+
+func (clr *coolRepository) UpdateTeamsById(ctx context.Context, id int, leaderCommunityIds []string, coreCommunityIds []string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	cool := models.Cool{}
+	return clr.db.Model(cool).Where("id = ?", id).Updates(map[string]interface{}{
+		"leader_community_ids": pq.Array(leaderCommunityIds),
+		"core_community_ids":   pq.Array(coreCommunityIds),
+		"updated_at":           time.Now(),
+	}).Error
+}
+
+func (clr *coolRepository) GetAllWithMeetingRule(ctx context.Context, status string) (cools []models.Cool, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = clr.db.Where("deleted_at IS NULL AND status = ? AND meeting_rule <> '' AND meeting_start_at IS NOT NULL", status).Find(&cools).Error
+
+	return cools, err
+}
+
+func (clr *coolRepository) GetAllByCampusCode(ctx context.Context, campusCode string, status string) (cools []models.Cool, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = clr.db.Where("deleted_at IS NULL AND UPPER(campus_code) = UPPER(?) AND status = ?", campusCode, status).Order("id ASC").Find(&cools).Error
+
+	return cools, err
+}
+
+// Update writes the details of the cool, leaving its teams and status as they are.
+func (clr *coolRepository) Update(ctx context.Context, cool *models.Cool) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return clr.db.Model(&models.Cool{}).Where("id = ?", cool.ID).
+		Select("name", "description", "category", "gender", "recurrence", "location_type", "location_name", "meeting_rule", "meeting_start_at", "meeting_duration_minutes", "capacity", "updated_at").
+		Updates(cool).Error
+}
+
+func (clr *coolRepository) UpdateFacilitatorsById(ctx context.Context, id int, facilitatorCommunityIds []string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return clr.db.Model(&models.Cool{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"facilitator_community_ids": pq.Array(facilitatorCommunityIds),
+		"updated_at":                time.Now(),
+	}).Error
+}
+
+func (clr *coolRepository) UpdateStatusById(ctx context.Context, id int, status string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return clr.db.Model(&models.Cool{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// Search lists the active cools by name for the ones looking for a group to join.
+func (clr *coolRepository) Search(ctx context.Context, param models.SearchCoolCursorParam) (output []models.SearchCoolDBOutput, pagination *models.PaginationOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	if param.Limit <= 0 {
+		param.Limit = 10
+	}
+
+	queryList, paramList, err := BuildQuerySearchCool(param)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build list query: %w", err)
+	}
+
+	var records []models.SearchCoolDBOutput
+	if err := clr.db.Raw(queryList, paramList...).Scan(&records).Error; err != nil {
+		return nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+
+	queryCount, paramCount, err := BuildCountSearchCool(param)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int
+	if err := clr.db.Raw(queryCount, paramCount...).Scan(&total).Error; err != nil {
+		return nil, nil, fmt.Errorf("count query execution failed: %w", err)
+	}
+
+	var next, prev string
+	if len(records) > 0 {
+		hasMore := len(records) > param.Limit
+		isForward := param.Direction != "prev" && param.Cursor != ""
+		isBackward := param.Direction == "prev" && param.Cursor != ""
+
+		if hasMore {
+			records = records[:param.Limit]
+		}
+
+		if isBackward {
+			for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+				records[i], records[j] = records[j], records[i]
+			}
+		}
+
+		if isBackward || hasMore {
+			lastRecord := records[len(records)-1]
+			next = cursor.EncryptCursorFromStruct(models.SearchCoolCursor{
+				ID:   lastRecord.ID,
+				Name: lastRecord.Name,
+			})
+		}
+
+		if isForward || (hasMore && isBackward) {
+			firstRecord := records[0]
+			prev = cursor.EncryptCursorFromStruct(models.SearchCoolCursor{
+				ID:   firstRecord.ID,
+				Name: firstRecord.Name,
+			})
+		}
+	}
+
+	pagination = &models.PaginationOutput{
+		Next:  next,
+		Prev:  prev,
+		Total: total,
+	}
+
+	return records, pagination, nil
+}
@@ -0,0 +1,9 @@
+package pgsql
+
+var (
+	queryGetAnswersByInstanceCode = `SELECT ea.id, ea.question_id, ea.registration_id, ea.value, ea."values", ea.created_at, ea.updated_at, ea.deleted_at
+		FROM event_answers ea
+		INNER JOIN event_registration_records er ON er.id = ea.registration_id
+		WHERE er.instance_code = ?
+		  AND ea.deleted_at IS NULL`
+)
@@ -0,0 +1,41 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"gorm.io/gorm"
+)
+
+type EventAnswerRepository interface {
+	BulkCreate(ctx context.Context, answers *[]models.EventAnswer) (err error)
+	GetManyByInstanceCode(ctx context.Context, instanceCode string) (answers []models.EventAnswer, err error)
+}
+
+type eventAnswerRepository struct {
+	db *gorm.DB
+}
+
+func NewEventAnswerRepository(db *gorm.DB) EventAnswerRepository {
+	return &eventAnswerRepository{db: db}
+}
+
+func (ear *eventAnswerRepository) BulkCreate(ctx context.Context, answers *[]models.EventAnswer) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return ear.db.Create(&answers).Error
+}
+
+func (ear *eventAnswerRepository) GetManyByInstanceCode(ctx context.Context, instanceCode string) (answers []models.EventAnswer, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = ear.db.Raw(queryGetAnswersByInstanceCode, instanceCode).Scan(&answers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
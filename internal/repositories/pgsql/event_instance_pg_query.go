@@ -4,6 +4,7 @@ var (
 	queryCountEventInstanceByCode   = `SELECT COUNT(*) FROM event_instances WHERE event_code = ?`
 	queryCheckEventInstanceByCode   = "SELECT EXISTS (SELECT 1 FROM event_instances WHERE code = ?)"
 	queryMultipleCheckEventInstance = "SELECT COUNT(*) FROM event_instances WHERE code = ANY(?)"
+	queryLockEventInstanceByCode    = "SELECT 1 FROM event_instances WHERE code = ? FOR UPDATE"
 
 	queryGetSessionsByEventCode = `
 		SELECT 
@@ -21,6 +22,7 @@ var (
 			ei.max_per_transaction AS instance_max_per_transaction, 
 			ei.is_one_per_account AS instance_is_one_per_account, 
 			ei.is_one_per_ticket AS instance_is_one_per_ticket, 
+			ei.is_waitlist_enabled AS instance_is_waitlist_enabled, 
 			ei.register_flow AS instance_register_flow, 
 			ei.check_type as instance_check_type, 
 			ei.total_seats AS instance_total_seats, 
@@ -38,7 +40,7 @@ var (
 			ei.status = ? AND 
 			ei.deleted_at IS NULL 
 		GROUP BY 
-			ei.code, ei.title, ei.description, ei.instance_start_at, ei.instance_end_at, ei.register_start_at, ei.register_end_at, ei.location_type, ei.location_name, ei.max_per_transaction, ei.is_one_per_account, ei.is_one_per_ticket, ei.register_flow, ei.check_type, ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.status, e.allowed_for, ei.allow_verify_at, ei.disallow_verify_at
+			ei.code, ei.title, ei.description, ei.instance_start_at, ei.instance_end_at, ei.register_start_at, ei.register_end_at, ei.location_type, ei.location_name, ei.max_per_transaction, ei.is_one_per_account, ei.is_one_per_ticket, ei.is_waitlist_enabled, ei.register_flow, ei.check_type, ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.status, e.allowed_for, ei.allow_verify_at, ei.disallow_verify_at
 `
 
 	queryGetSessionByCode = `
@@ -58,6 +60,7 @@ var (
 			ei.max_per_transaction AS instance_max_per_transaction, 
 			ei.is_one_per_account AS instance_is_one_per_account, 
 			ei.is_one_per_ticket AS instance_is_one_per_ticket, 
+			ei.is_waitlist_enabled AS instance_is_waitlist_enabled, 
 			ei.register_flow AS instance_register_flow, 
 			ei.check_type AS instance_check_type, 
 			ei.total_seats AS instance_total_seats, 
@@ -75,20 +78,23 @@ var (
 			ei.status = ? AND 
 			ei.deleted_at IS NULL 
 		GROUP BY 
-			ei.code, ei.event_code, ei.title, ei.description, ei.instance_start_at, ei.instance_end_at, ei.register_start_at, ei.register_end_at, ei.location_type, ei.location_name, ei.max_per_transaction, ei.is_one_per_account, ei.is_one_per_ticket, ei.register_flow, ei.check_type, ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.status, ei.allow_verify_at, ei.disallow_verify_at
+			ei.code, ei.event_code, ei.title, ei.description, ei.instance_start_at, ei.instance_end_at, ei.register_start_at, ei.register_end_at, ei.location_type, ei.location_name, ei.max_per_transaction, ei.is_one_per_account, ei.is_one_per_ticket, ei.is_waitlist_enabled, ei.register_flow, ei.check_type, ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.status, ei.allow_verify_at, ei.disallow_verify_at
 `
 	queryGetSeatsByInstanceCode = `SELECT ei.total_seats as total_seats,
 		   ei.booked_seats as booked_seats,
 		   ei.scanned_seats as scanned_seats,
+		   ei.checked_out_seats as checked_out_seats,
+		   ei.check_type as check_type,
 		   ei.title as event_instance_title,
 		   e.title as event_title,
 		   ei.allow_verify_at AS instance_allow_verify_at,
 		   ei.disallow_verify_at AS instance_disallow_verify_at,
+		   ei.is_waitlist_enabled AS is_waitlist_enabled,
 		   coalesce(sum(ei.total_seats - ei.booked_seats), 0) as total_remaining_seats
 	from event_instances ei
 		left join events e on ei.event_code = e.code
 	where ei.code = ?
-	group by ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.title, e.title, ei.allow_verify_at, ei.disallow_verify_at`
+	group by ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.checked_out_seats, ei.check_type, ei.title, e.title, ei.allow_verify_at, ei.disallow_verify_at, ei.is_waitlist_enabled`
 
 	queryGetInstanceSummary = `SELECT 
 			ei.code AS instance_code, 
@@ -98,16 +104,45 @@ var (
 			ei.total_seats AS instance_total_seats, 
 			ei.booked_seats AS instance_booked_seats, 
 			ei.scanned_seats AS instance_scanned_seats,
+			ei.checked_out_seats AS instance_checked_out_seats,
 			ei.max_per_transaction AS instance_max_per_transaction,
 			ei.status AS instance_status, 
-			COALESCE(SUM(ei.total_seats - ei.booked_seats), 0) AS total_remaining_seats
+			COALESCE(SUM(ei.total_seats - ei.booked_seats), 0) AS total_remaining_seats,
+			COALESCE(d.average_attended_minutes, 0) AS average_attended_minutes,
+			COALESCE(d.min_attended_minutes, 0) AS min_attended_minutes,
+			COALESCE(d.max_attended_minutes, 0) AS max_attended_minutes,
+			COALESCE(d.full_session_count, 0) AS full_session_count
 		FROM 
 			event_instances ei
 				LEFT JOIN events e 
 				ON ei.event_code = e.code
+				LEFT JOIN (
+					-- Only check-ins followed by a check-out have a measurable attended duration
+					SELECT 
+						er.instance_code,
+						AVG(EXTRACT(EPOCH FROM (er.checked_out_at - er.verified_at)) / 60) AS average_attended_minutes,
+						MIN(EXTRACT(EPOCH FROM (er.checked_out_at - er.verified_at)) / 60) AS min_attended_minutes,
+						MAX(EXTRACT(EPOCH FROM (er.checked_out_at - er.verified_at)) / 60) AS max_attended_minutes,
+						COUNT(CASE WHEN er.checked_out_at >= i.instance_end_at THEN 1 END) AS full_session_count
+					FROM 
+						event_registration_records er
+							INNER JOIN event_instances i
+							ON er.instance_code = i.code
+					WHERE 
+						i.event_code = ? AND
+						i.check_type = 'both' AND
+						er.status = 'checked-out' AND
+						er.verified_at IS NOT NULL AND
+						er.checked_out_at IS NOT NULL AND
+						er.deleted_at IS NULL
+					GROUP BY 
+						er.instance_code
+				) d 
+				ON d.instance_code = ei.code
 		WHERE 
 			ei.event_code = ? AND
 			ei.deleted_at IS NULL 
 		GROUP BY 
-			ei.code, ei.title, ei.register_flow, ei.check_type, ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.status, ei.max_per_transaction`
+			ei.code, ei.title, ei.register_flow, ei.check_type, ei.total_seats, ei.booked_seats, ei.scanned_seats, ei.checked_out_seats, ei.status, ei.max_per_transaction,
+			d.average_attended_minutes, d.min_attended_minutes, d.max_attended_minutes, d.full_session_count`
 )
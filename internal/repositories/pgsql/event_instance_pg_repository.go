@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/lib/pq"
 	"go-community/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,6 +13,7 @@ type EventInstanceRepository interface {
 	Create(ctx context.Context, event *models.EventInstance) (err error)
 	BulkCreate(ctx context.Context, events *[]models.EventInstance) (err error)
 	GetByCode(ctx context.Context, code string) (campus models.EventInstance, err error)
+	LockByCode(ctx context.Context, code string) (err error)
 	GetAll(ctx context.Context) (campus []models.EventInstance, err error)
 	CountByCode(ctx context.Context, code string) (count int64, err error)
 	GetManyByEventCode(ctx context.Context, eventCode string, status string) (outputs *[]models.GetInstanceByEventCodeDBOutput, err error)
@@ -20,9 +22,14 @@ type EventInstanceRepository interface {
 	UpdateBookedSeatsByCode(ctx context.Context, code string, event *models.GetSeatsAndNamesByInstanceCodeDBOutput) (err error)
 	UpdateScannedSeatsByCode(ctx context.Context, code string, event *models.GetSeatsAndNamesByInstanceCodeDBOutput) (err error)
 	UpdateSeatsByCode(ctx context.Context, code string, event *models.GetSeatsAndNamesByInstanceCodeDBOutput) (err error)
+	UpdateCheckedOutSeatsByCode(ctx context.Context, code string, event *models.GetSeatsAndNamesByInstanceCodeDBOutput) (err error)
 	GetSummary(ctx context.Context, eventCode string) (output []models.GetInstanceSummaryDBOutput, err error)
 	CheckByCode(ctx context.Context, code string) (dataExist bool, err error)
 	CheckMultiple(ctx context.Context, codes []string) (count int64, err error)
+	GetRecurrenceTemplate(ctx context.Context, eventCode string) (instance models.EventInstance, err error)
+	GetManyRecurringByEventCode(ctx context.Context, eventCode string, from time.Time) (instances []models.EventInstance, err error)
+	GetAllByEventCodeAndStatus(ctx context.Context, eventCode string, status string) (instances []models.EventInstance, err error)
+	Update(ctx context.Context, instance *models.EventInstance) (err error)
 }
 
 type eventInstanceRepository struct {
@@ -65,6 +72,16 @@ func (eir *eventInstanceRepository) GetByCode(ctx context.Context, code string)
 	return ei, err
 }
 
+// LockByCode locks the instance until the transaction ends, so its seats can be read and written back without
+// another transaction changing them in between.
+func (eir *eventInstanceRepository) LockByCode(ctx context.Context, code string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return eir.db.Exec(queryLockEventInstanceByCode, code).Error
+}
+
 func (eir *eventInstanceRepository) GetAll(ctx context.Context) (campus []models.EventInstance, err error) {
 	defer func() {
 		LogRepository(ctx, err)
@@ -155,12 +172,23 @@ func (eir *eventInstanceRepository) UpdateSeatsByCode(ctx context.Context, code
 	}).Error
 }
 
+func (eir *eventInstanceRepository) UpdateCheckedOutSeatsByCode(ctx context.Context, code string, event *models.GetSeatsAndNamesByInstanceCodeDBOutput) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return eir.db.Model(&models.EventInstance{}).Where("code = ?", code).Updates(map[string]interface{}{
+		"scanned_seats":     event.ScannedSeats,
+		"checked_out_seats": event.CheckedOutSeats,
+	}).Error
+}
+
 func (eir *eventInstanceRepository) GetSummary(ctx context.Context, eventCode string) (output []models.GetInstanceSummaryDBOutput, err error) {
 	defer func() {
 		LogRepository(ctx, err)
 	}()
 
-	err = eir.db.Raw(queryGetInstanceSummary, eventCode).Scan(&output).Error
+	err = eir.db.Raw(queryGetInstanceSummary, eventCode, eventCode).Scan(&output).Error
 	if err != nil {
 		return nil, err
 	}
@@ -193,3 +221,43 @@ func (eir *eventInstanceRepository) CheckMultiple(ctx context.Context, codes []s
 
 	return count, nil
 }
+
+func (eir *eventInstanceRepository) GetRecurrenceTemplate(ctx context.Context, eventCode string) (instance models.EventInstance, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	// The first occurrence is the template, even after it has been deleted
+	err = eir.db.Where("event_code = ? AND recurrence_at IS NOT NULL", eventCode).Order("recurrence_at ASC").Limit(1).Find(&instance).Error
+
+	return instance, err
+}
+
+func (eir *eventInstanceRepository) GetManyRecurringByEventCode(ctx context.Context, eventCode string, from time.Time) (instances []models.EventInstance, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = eir.db.Where("event_code = ? AND recurrence_at >= ?", eventCode, from).Order("recurrence_at ASC").Find(&instances).Error
+
+	return instances, err
+}
+
+func (eir *eventInstanceRepository) GetAllByEventCodeAndStatus(ctx context.Context, eventCode string, status string) (instances []models.EventInstance, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = eir.db.Where("event_code = ? AND status = ? AND deleted_at IS NULL", eventCode, status).Order("instance_start_at ASC").Find(&instances).Error
+
+	return instances, err
+}
+
+func (eir *eventInstanceRepository) Update(ctx context.Context, instance *models.EventInstance) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	// Seat counters are only changed by their own updates, so registrations made meanwhile are not overwritten
+	return eir.db.Omit("booked_seats", "scanned_seats", "checked_out_seats").Save(instance).Error
+}
@@ -175,6 +175,8 @@ var (
 		ei.location_type AS instance_location_type,
 		ei.location_name AS instance_location_name,
 		ei.status AS instance_status,
+		ei.allow_verify_at AS instance_allow_verify_at,
+		ei.disallow_verify_at AS instance_disallow_verify_at,
 		rr.id AS registration_record_id,
 		rr.name AS registration_record_name,
 		coalesce(rr.identifier, '') AS registration_record_identifier,
@@ -204,6 +206,7 @@ var (
 			COALESCE(e.allowed_campuses, ARRAY[]::TEXT[]) AS event_allowed_campuses, -- Default to empty array
 			COALESCE(SUM(ei.booked_seats), 0) AS total_booked_seats,
 			COALESCE(SUM(ei.scanned_seats), 0) AS total_scanned_seats,
+			COALESCE(SUM(ei.checked_out_seats), 0) AS total_checked_out_seats,
 			e.status AS event_status
 		FROM
 			events e
@@ -18,6 +18,7 @@ type EventRepository interface {
 	GetTitles(ctx context.Context) (output []models.GetEventTitlesDBOutput, err error)
 	GetSummary(ctx context.Context, code string) (output *models.GetEventSummaryDBOutput, err error)
 	Update(ctx context.Context, event *models.Event) (err error)
+	GetAllRecurring(ctx context.Context, status string) (events []models.Event, err error)
 }
 
 type eventRepository struct {
@@ -148,3 +149,13 @@ func (er *eventRepository) Update(ctx context.Context, event *models.Event) (err
 
 	return er.db.Save(&event).Error
 }
+
+func (er *eventRepository) GetAllRecurring(ctx context.Context, status string) (events []models.Event, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = er.db.Where("is_recurring = ? AND status = ? AND deleted_at IS NULL", true, status).Find(&events).Error
+
+	return events, err
+}
@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"context"
+	"go-community/internal/common"
 	"go-community/internal/models"
 	"gorm.io/gorm"
 )
@@ -9,6 +10,11 @@ import (
 type EventQuestionRepository interface {
 	Create(ctx context.Context, question *models.EventQuestion) (err error)
 	BulkCreate(ctx context.Context, questions *[]models.EventQuestion) (err error)
+	GetManyByEventCodeAndInstanceCode(ctx context.Context, eventCode string, instanceCode string, status string) (questions []models.EventQuestion, err error)
+	GetManyWithDeletedByEventCodeAndInstanceCode(ctx context.Context, eventCode string, instanceCode string) (questions []models.EventQuestion, err error)
+	GetById(ctx context.Context, id string) (question models.EventQuestion, err error)
+	Update(ctx context.Context, question *models.EventQuestion) (err error)
+	Delete(ctx context.Context, id string) (err error)
 }
 
 type eventQuestionRepository struct {
@@ -34,3 +40,69 @@ func (eqr *eventQuestionRepository) BulkCreate(ctx context.Context, questions *[
 
 	return eqr.db.Create(&questions).Error
 }
+
+// GetManyByEventCodeAndInstanceCode lists the questions in display order. An empty instance code lists the questions
+// of every instance and an empty status lists every status.
+func (eqr *eventQuestionRepository) GetManyByEventCodeAndInstanceCode(ctx context.Context, eventCode string, instanceCode string, status string) (questions []models.EventQuestion, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	query := eqr.byEventCodeAndInstanceCode(eventCode, instanceCode).Where("deleted_at IS NULL")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err = query.Order("display_order ASC NULLS LAST, created_at ASC").Find(&questions).Error
+
+	return questions, err
+}
+
+// GetManyWithDeletedByEventCodeAndInstanceCode lists every question in display order, deleted ones included, since
+// the answers given to them are kept.
+func (eqr *eventQuestionRepository) GetManyWithDeletedByEventCodeAndInstanceCode(ctx context.Context, eventCode string, instanceCode string) (questions []models.EventQuestion, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = eqr.byEventCodeAndInstanceCode(eventCode, instanceCode).Order("display_order ASC NULLS LAST, created_at ASC").Find(&questions).Error
+
+	return questions, err
+}
+
+func (eqr *eventQuestionRepository) byEventCodeAndInstanceCode(eventCode string, instanceCode string) *gorm.DB {
+	query := eqr.db.Where("event_code = ?", eventCode)
+	if instanceCode != "" {
+		// A question without instance codes is asked for every instance of the event
+		query = query.Where("(COALESCE(cardinality(instance_code), 0) = 0 OR ? = ANY(instance_code))", instanceCode)
+	}
+
+	return query
+}
+
+func (eqr *eventQuestionRepository) GetById(ctx context.Context, id string) (question models.EventQuestion, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = eqr.db.Where("id = ? AND deleted_at IS NULL", id).Find(&question).Error
+
+	return question, err
+}
+
+func (eqr *eventQuestionRepository) Update(ctx context.Context, question *models.EventQuestion) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return eqr.db.Save(question).Error
+}
+
+func (eqr *eventQuestionRepository) Delete(ctx context.Context, id string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	// Soft deleted, since the answers already given still refer to the question
+	return eqr.db.Model(&models.EventQuestion{}).Where("id = ?", id).Update("deleted_at", common.Now()).Error
+}
@@ -0,0 +1,55 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventRecurrenceExceptionRepository interface {
+	Upsert(ctx context.Context, exception *models.EventRecurrenceException) (err error)
+	GetManyByEventCode(ctx context.Context, eventCode string) (output []models.EventRecurrenceException, err error)
+	DeleteByOccurrence(ctx context.Context, eventCode string, occurrenceAt time.Time) (rowsAffected int64, err error)
+}
+
+type eventRecurrenceExceptionRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRecurrenceExceptionRepository(db *gorm.DB) EventRecurrenceExceptionRepository {
+	return &eventRecurrenceExceptionRepository{db: db}
+}
+
+func (errr *eventRecurrenceExceptionRepository) Upsert(ctx context.Context, exception *models.EventRecurrenceException) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return errr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_code"}, {Name: "occurrence_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_skipped", "title", "location_name", "instance_start_at", "instance_end_at", "total_seats", "updated_at"}),
+	}).Create(&exception).Error
+}
+
+func (errr *eventRecurrenceExceptionRepository) GetManyByEventCode(ctx context.Context, eventCode string) (output []models.EventRecurrenceException, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = errr.db.Where("event_code = ?", eventCode).Order("occurrence_at ASC").Find(&output).Error
+
+	return output, err
+}
+
+func (errr *eventRecurrenceExceptionRepository) DeleteByOccurrence(ctx context.Context, eventCode string, occurrenceAt time.Time) (rowsAffected int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := errr.db.Where("event_code = ? AND occurrence_at = ?", eventCode, occurrenceAt).Delete(&models.EventRecurrenceException{})
+
+	return result.RowsAffected, result.Error
+}
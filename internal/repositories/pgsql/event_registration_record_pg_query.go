@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"fmt"
+	"github.com/lib/pq"
 	"go-community/internal/models"
 	"go-community/internal/pkg/cursor"
 	"strconv"
@@ -23,10 +24,10 @@ var (
 			er.community_id,
 			er.event_code,
 			e.title,
-			COUNT(CASE WHEN er.status = 'success' THEN 1 END) AS success_count,
+			COUNT(CASE WHEN er.status IN ('success', 'checked-out') THEN 1 END) AS success_count,
 			COUNT(CASE WHEN er.status = 'permit' AND (er.reason IS NOT NULL AND er.reason != '') THEN 1 END) AS permit_with_reason_count,
 			COUNT(CASE WHEN er.status = 'permit' AND (er.reason IS NULL OR er.reason = '') THEN 1 END) AS permit_without_reason_count,
-			COUNT(CASE WHEN er.status NOT IN ('success', 'permit') THEN 1 END) AS other_status_count,
+			COUNT(CASE WHEN er.status NOT IN ('success', 'checked-out', 'permit') THEN 1 END) AS other_status_count,
 			COUNT(*) AS total_instances
 		FROM 
 			event_registration_records AS er
@@ -205,6 +206,10 @@ func BuildCountGetRegisteredQuery(param models.GetAllRegisteredCursorParam) (str
 		queryBuilder.WriteString(" AND u.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(u.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.DepartmentCode != "" {
 		queryBuilder.WriteString(" AND u.department = ?")
 		args = append(args, param.DepartmentCode)
@@ -242,6 +247,10 @@ func BuildGetRegisteredQuery(param models.GetAllRegisteredCursorParam) (string,
 		queryBuilder.WriteString(" AND u.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(u.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.DepartmentCode != "" {
 		queryBuilder.WriteString(" AND u.department = ?")
 		args = append(args, param.DepartmentCode)
@@ -317,6 +326,10 @@ func BuildDownloadGetRegisteredQuery(param models.GetDownloadAllRegisteredParam)
 		queryBuilder.WriteString(" AND u.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(u.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.DepartmentCode != "" {
 		queryBuilder.WriteString(" AND u.department = ?")
 		args = append(args, param.DepartmentCode)
@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"go-community/internal/models"
 	"go-community/internal/pkg/cursor"
 	"gorm.io/gorm"
@@ -22,6 +23,9 @@ type EventRegistrationRecordRepository interface {
 	CheckByCommunityId(ctx context.Context, communityId string) (isExist bool, err error)
 	CheckByCommunityIdAndInstanceCode(ctx context.Context, communityId string, instanceCode string) (isExist bool, err error)
 	Update(ctx context.Context, eventRegistrationRecord models.EventRegistrationRecord) (err error)
+	GetManyByInstanceCodeAndStatus(ctx context.Context, instanceCode string, status string) (eventRegistrationRecords []models.EventRegistrationRecord, err error)
+	UpdateStatusByIds(ctx context.Context, ids []uuid.UUID, status string) (err error)
+	UpdateStatusAndReasonByIds(ctx context.Context, ids []uuid.UUID, status string, reason string, updatedBy string) (err error)
 	GetEventAttendance(ctx context.Context, communityId, startDate string, endDate string) (output []models.GetEventAttendanceDBOutput, err error)
 	GetAllWithCursor(ctx context.Context, param models.GetAllRegisteredCursorParam) (output []models.GetAllRegisteredRecordDBOutput, prev string, next string, total int, err error)
 	Download(ctx context.Context, param models.GetDownloadAllRegisteredParam) (output []models.GetDownloadAllRegisteredDBOutput, err error)
@@ -202,6 +206,37 @@ func (errr *eventRegistrationRecordRepository) Update(ctx context.Context, event
 	return errr.db.Save(&eventRegistrationRecord).Error
 }
 
+func (errr *eventRegistrationRecordRepository) GetManyByInstanceCodeAndStatus(ctx context.Context, instanceCode string, status string) (eventRegistrationRecords []models.EventRegistrationRecord, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	var e []models.EventRegistrationRecord
+	err = errr.db.Where("instance_code = ? AND status = ? AND deleted_at IS NULL", instanceCode, status).Order("created_at ASC, registered_at ASC").Find(&e).Error
+
+	return e, err
+}
+
+func (errr *eventRegistrationRecordRepository) UpdateStatusByIds(ctx context.Context, ids []uuid.UUID, status string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return errr.db.Model(&models.EventRegistrationRecord{}).Where("id IN ?", ids).Update("status", status).Error
+}
+
+func (errr *eventRegistrationRecordRepository) UpdateStatusAndReasonByIds(ctx context.Context, ids []uuid.UUID, status string, reason string, updatedBy string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return errr.db.Model(&models.EventRegistrationRecord{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":     status,
+		"reason":     reason,
+		"updated_by": updatedBy,
+	}).Error
+}
+
 func (errr *eventRegistrationRecordRepository) GetEventAttendance(ctx context.Context, communityId, startDate string, endDate string) (output []models.GetEventAttendanceDBOutput, err error) {
 	defer func() {
 		LogRepository(ctx, err)
@@ -0,0 +1,18 @@
+package pgsql
+
+var (
+	// A key that expired, or whose request never finished within its lock, is taken over as if it was new
+	queryAcquireIdempotencyKey = `INSERT INTO idempotency_keys (request_id, caller, route, fingerprint, status, locked_until, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (request_id, caller, route) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			status = EXCLUDED.status,
+			response_code = 0,
+			response_body = NULL,
+			content_type = '',
+			locked_until = EXCLUDED.locked_until,
+			expires_at = EXCLUDED.expires_at,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at
+		WHERE idempotency_keys.expires_at < ? OR (idempotency_keys.status = ? AND idempotency_keys.locked_until < ?)`
+)
@@ -0,0 +1,86 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyKeyRepository interface {
+	Acquire(ctx context.Context, key *models.IdempotencyKey, now time.Time) (acquired bool, err error)
+	Get(ctx context.Context, requestId string, caller string, route string) (key *models.IdempotencyKey, err error)
+	Complete(ctx context.Context, key *models.IdempotencyKey, now time.Time) (err error)
+	Delete(ctx context.Context, requestId string, caller string, route string) (err error)
+	DeleteExpired(ctx context.Context, now time.Time) (err error)
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Acquire stores the key as processing, unless another request holds it already.
+func (ikr *idempotencyKeyRepository) Acquire(ctx context.Context, key *models.IdempotencyKey, now time.Time) (acquired bool, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	processing := models.MapIdempotencyStatus[models.IDEMPOTENCY_STATUS_PROCESSING]
+	result := ikr.db.Exec(queryAcquireIdempotencyKey,
+		key.RequestId, key.Caller, key.Route, key.Fingerprint, processing, key.LockedUntil, key.ExpiresAt, now, now,
+		now, processing, now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (ikr *idempotencyKeyRepository) Get(ctx context.Context, requestId string, caller string, route string) (key *models.IdempotencyKey, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	var keys []models.IdempotencyKey
+	err = ikr.db.Where("request_id = ? AND caller = ? AND route = ?", requestId, caller, route).Limit(1).Find(&keys).Error
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+
+	return &keys[0], nil
+}
+
+func (ikr *idempotencyKeyRepository) Complete(ctx context.Context, key *models.IdempotencyKey, now time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return ikr.db.Model(&models.IdempotencyKey{}).Where("request_id = ? AND caller = ? AND route = ?", key.RequestId, key.Caller, key.Route).Updates(map[string]interface{}{
+		"status":        models.MapIdempotencyStatus[models.IDEMPOTENCY_STATUS_COMPLETED],
+		"response_code": key.ResponseCode,
+		"response_body": key.ResponseBody,
+		"content_type":  key.ContentType,
+		"updated_at":    now,
+	}).Error
+}
+
+func (ikr *idempotencyKeyRepository) Delete(ctx context.Context, requestId string, caller string, route string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return ikr.db.Where("request_id = ? AND caller = ? AND route = ?", requestId, caller, route).Delete(&models.IdempotencyKey{}).Error
+}
+
+func (ikr *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return ikr.db.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{}).Error
+}
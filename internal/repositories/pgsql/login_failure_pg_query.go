@@ -0,0 +1,12 @@
+package pgsql
+
+var (
+	// Failures older than the window no longer count, so the counter starts over
+	queryIncrementLoginFailure = `INSERT INTO login_failures (scope, value, failures, last_failed_at, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, 1, ?, ?, ?, ?)
+		ON CONFLICT (scope, value) DO UPDATE SET
+			failures = CASE WHEN login_failures.last_failed_at < ? THEN 1 ELSE login_failures.failures + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *`
+)
@@ -0,0 +1,67 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginFailureRepository interface {
+	GetByIdentifierAndIp(ctx context.Context, identifier string, ipAddress string) (failures []models.LoginFailure, err error)
+	Increment(ctx context.Context, scope string, value string, now time.Time, windowStart time.Time) (failure models.LoginFailure, err error)
+	UpdateDelay(ctx context.Context, scope string, value string, nextAttemptAt time.Time, lockedUntil sql.NullTime) (err error)
+	Delete(ctx context.Context, scope string, value string) (err error)
+}
+
+type loginFailureRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginFailureRepository(db *gorm.DB) LoginFailureRepository {
+	return &loginFailureRepository{db: db}
+}
+
+func (lfr *loginFailureRepository) GetByIdentifierAndIp(ctx context.Context, identifier string, ipAddress string) (failures []models.LoginFailure, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = lfr.db.Where("(scope = ? AND value = ?) OR (scope = ? AND value = ?)",
+		models.MapLoginScope[models.LOGIN_SCOPE_IDENTIFIER], identifier,
+		models.MapLoginScope[models.LOGIN_SCOPE_IP], ipAddress).Find(&failures).Error
+
+	return failures, err
+}
+
+// Increment counts a failed login and returns the counter. The next attempt is allowed right away until UpdateDelay says otherwise.
+func (lfr *loginFailureRepository) Increment(ctx context.Context, scope string, value string, now time.Time, windowStart time.Time) (failure models.LoginFailure, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = lfr.db.Raw(queryIncrementLoginFailure, scope, value, now, now, now, now, windowStart).Scan(&failure).Error
+
+	return failure, err
+}
+
+func (lfr *loginFailureRepository) UpdateDelay(ctx context.Context, scope string, value string, nextAttemptAt time.Time, lockedUntil sql.NullTime) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return lfr.db.Model(&models.LoginFailure{}).Where("scope = ? AND value = ?", scope, value).Updates(map[string]interface{}{
+		"next_attempt_at": nextAttemptAt,
+		"locked_until":    lockedUntil,
+	}).Error
+}
+
+func (lfr *loginFailureRepository) Delete(ctx context.Context, scope string, value string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return lfr.db.Where("scope = ? AND value = ?", scope, value).Delete(&models.LoginFailure{}).Error
+}
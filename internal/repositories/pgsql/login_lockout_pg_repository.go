@@ -0,0 +1,45 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginLockoutRepository interface {
+	Create(ctx context.Context, lockout *models.LoginLockout) (err error)
+	Unlock(ctx context.Context, scope string, value string, unlockedBy string, now time.Time) (rowsAffected int64, err error)
+}
+
+type loginLockoutRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginLockoutRepository(db *gorm.DB) LoginLockoutRepository {
+	return &loginLockoutRepository{db: db}
+}
+
+func (llr *loginLockoutRepository) Create(ctx context.Context, lockout *models.LoginLockout) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return llr.db.Create(lockout).Error
+}
+
+// Unlock ends the lockouts of the scope and value that are still running.
+func (llr *loginLockoutRepository) Unlock(ctx context.Context, scope string, value string, unlockedBy string, now time.Time) (rowsAffected int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := llr.db.Model(&models.LoginLockout{}).Where("scope = ? AND value = ? AND unlocked_at IS NULL AND locked_until > ?", scope, value, now).Updates(map[string]interface{}{
+		"unlocked_at": now,
+		"unlocked_by": unlockedBy,
+		"updated_at":  now,
+	})
+
+	return result.RowsAffected, result.Error
+}
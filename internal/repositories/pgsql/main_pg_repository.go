@@ -16,34 +16,64 @@ type PostgreRepositories struct {
 	FeatureFlag FeatureFlagRepository
 	Config      ConfigRepository
 
-	Role                    RoleRepository
-	UserType                UserTypeRepository
-	Event                   EventRepository
-	EventInstance           EventInstanceRepository
-	EventRegistrationRecord EventRegistrationRecordRepository
-	EventQuestion           EventQuestionRepository
-	CoolNewJoiner           CoolNewJoinerRepository
+	Role                     RoleRepository
+	UserType                 UserTypeRepository
+	Event                    EventRepository
+	EventInstance            EventInstanceRepository
+	EventRegistrationRecord  EventRegistrationRecordRepository
+	EventQuestion            EventQuestionRepository
+	EventAnswer              EventAnswerRepository
+	EventRecurrenceException EventRecurrenceExceptionRepository
+	CoolNewJoiner            CoolNewJoinerRepository
+	CoolNewJoinerNote        CoolNewJoinerNoteRepository
+	CoolMember               CoolMemberRepository
+	CoolMeeting              CoolMeetingRepository
+	CoolMeetingAttendance    CoolMeetingAttendanceRepository
+	NotificationOutbox       NotificationOutboxRepository
+	PasswordReset            PasswordResetRepository
+	UserSession              UserSessionRepository
+	LoginFailure             LoginFailureRepository
+	LoginLockout             LoginLockoutRepository
+	RateLimit                RateLimitRepository
+	IdempotencyKey           IdempotencyKeyRepository
+	Permission               PermissionRepository
+	AuditEvent               AuditEventRepository
 }
 
 func New(db *gorm.DB) *PostgreRepositories {
 	return &PostgreRepositories{
-		Transaction:             NewTransactionRepository(db),
-		Health:                  NewHealthRepository(db),
-		Campus:                  NewCampusRepository(db, NewTransactionRepository(db)),
-		CoolCategory:            NewCoolCategoryRepository(db, NewTransactionRepository(db)),
-		Cool:                    NewCoolRepository(db, NewTransactionRepository(db)),
-		Location:                NewLocationRepository(db, NewTransactionRepository(db)),
-		User:                    NewUserRepository(db, NewTransactionRepository(db)),
-		UserRelation:            NewUserRelationRepository(db, NewTransactionRepository(db)),
-		EventCommunityRequest:   NewEventCommunityRequestRepository(db, NewTransactionRepository(db)),
-		Role:                    NewRoleRepository(db, NewTransactionRepository(db)),
-		UserType:                NewUserTypeRepository(db, NewTransactionRepository(db)),
-		Event:                   NewEventRepository(db, NewTransactionRepository(db)),
-		EventInstance:           NewEventInstanceRepository(db, NewTransactionRepository(db)),
-		EventRegistrationRecord: NewEventRegistrationRecordRepository(db, NewTransactionRepository(db)),
-		EventQuestion:           NewEventQuestionRepository(db),
-		FeatureFlag:             NewFeatureFlagRepository(db),
-		CoolNewJoiner:           NewCoolNewJoinerRepository(db),
-		Config:                  NewConfigRepository(db),
+		Transaction:              NewTransactionRepository(db),
+		Health:                   NewHealthRepository(db),
+		Campus:                   NewCampusRepository(db, NewTransactionRepository(db)),
+		CoolCategory:             NewCoolCategoryRepository(db, NewTransactionRepository(db)),
+		Cool:                     NewCoolRepository(db, NewTransactionRepository(db)),
+		Location:                 NewLocationRepository(db, NewTransactionRepository(db)),
+		User:                     NewUserRepository(db, NewTransactionRepository(db)),
+		UserRelation:             NewUserRelationRepository(db, NewTransactionRepository(db)),
+		EventCommunityRequest:    NewEventCommunityRequestRepository(db, NewTransactionRepository(db)),
+		Role:                     NewRoleRepository(db, NewTransactionRepository(db)),
+		UserType:                 NewUserTypeRepository(db, NewTransactionRepository(db)),
+		Event:                    NewEventRepository(db, NewTransactionRepository(db)),
+		EventInstance:            NewEventInstanceRepository(db, NewTransactionRepository(db)),
+		EventRegistrationRecord:  NewEventRegistrationRecordRepository(db, NewTransactionRepository(db)),
+		EventQuestion:            NewEventQuestionRepository(db),
+		EventAnswer:              NewEventAnswerRepository(db),
+		EventRecurrenceException: NewEventRecurrenceExceptionRepository(db),
+		FeatureFlag:              NewFeatureFlagRepository(db),
+		CoolNewJoiner:            NewCoolNewJoinerRepository(db),
+		CoolNewJoinerNote:        NewCoolNewJoinerNoteRepository(db),
+		CoolMember:               NewCoolMemberRepository(db),
+		CoolMeeting:              NewCoolMeetingRepository(db),
+		CoolMeetingAttendance:    NewCoolMeetingAttendanceRepository(db),
+		NotificationOutbox:       NewNotificationOutboxRepository(db),
+		PasswordReset:            NewPasswordResetRepository(db),
+		UserSession:              NewUserSessionRepository(db),
+		LoginFailure:             NewLoginFailureRepository(db),
+		LoginLockout:             NewLoginLockoutRepository(db),
+		RateLimit:                NewRateLimitRepository(db),
+		IdempotencyKey:           NewIdempotencyKeyRepository(db),
+		Permission:               NewPermissionRepository(db),
+		AuditEvent:               NewAuditEventRepository(db),
+		Config:                   NewConfigRepository(db),
 	}
 }
@@ -0,0 +1,15 @@
+package pgsql
+
+var (
+	// Rows locked by another worker are skipped, so concurrent workers never claim the same message
+	queryClaimDueNotifications = `UPDATE notification_outboxes
+		SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM notification_outboxes
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+)
@@ -0,0 +1,54 @@
+package pgsql
+
+import (
+	"context"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationOutboxRepository interface {
+	Create(ctx context.Context, notification *models.NotificationOutbox) (err error)
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) (notifications []models.NotificationOutbox, err error)
+	Update(ctx context.Context, notification *models.NotificationOutbox) (err error)
+}
+
+type notificationOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationOutboxRepository(db *gorm.DB) NotificationOutboxRepository {
+	return &notificationOutboxRepository{db: db}
+}
+
+func (nor *notificationOutboxRepository) Create(ctx context.Context, notification *models.NotificationOutbox) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return nor.db.Create(notification).Error
+}
+
+// ClaimDue takes up to limit pending notifications due at now and counts the attempt. They are not due again until
+// leaseUntil, so a worker that stops halfway leaves them to be retried instead of stuck.
+func (nor *notificationOutboxRepository) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) (notifications []models.NotificationOutbox, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = nor.db.Raw(queryClaimDueNotifications, leaseUntil, now, models.MapNotificationStatus[models.NOTIFICATION_STATUS_PENDING], now, limit).Scan(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (nor *notificationOutboxRepository) Update(ctx context.Context, notification *models.NotificationOutbox) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return nor.db.Save(notification).Error
+}
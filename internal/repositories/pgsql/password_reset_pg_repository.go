@@ -0,0 +1,62 @@
+package pgsql
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) (err error)
+	GetActiveByTokenHash(ctx context.Context, tokenHash string, now time.Time) (token models.PasswordResetToken, err error)
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (rowsAffected int64, err error)
+	RevokeByCommunityId(ctx context.Context, communityId string, usedAt time.Time) (err error)
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (prr *passwordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return prr.db.Create(token).Error
+}
+
+func (prr *passwordResetRepository) GetActiveByTokenHash(ctx context.Context, tokenHash string, now time.Time) (token models.PasswordResetToken, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = prr.db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).Find(&token).Error
+
+	return token, err
+}
+
+// MarkUsed only marks a token that has not been used yet, so of two requests racing with the same token only one gets a row.
+func (prr *passwordResetRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (rowsAffected int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := prr.db.Model(&models.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", id).Updates(map[string]interface{}{"used_at": usedAt, "updated_at": usedAt})
+
+	return result.RowsAffected, result.Error
+}
+
+func (prr *passwordResetRepository) RevokeByCommunityId(ctx context.Context, communityId string, usedAt time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return prr.db.Model(&models.PasswordResetToken{}).Where("community_id = ? AND used_at IS NULL", communityId).Updates(map[string]interface{}{"used_at": usedAt, "updated_at": usedAt}).Error
+}
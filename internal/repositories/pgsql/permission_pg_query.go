@@ -0,0 +1,9 @@
+package pgsql
+
+var (
+	queryGetGrantedPermissions = `SELECT DISTINCT permission FROM (
+			SELECT UNNEST(permissions) AS permission FROM roles WHERE role = ANY(?) AND deleted_at IS NULL
+			UNION
+			SELECT UNNEST(permissions) AS permission FROM user_types WHERE type = ANY(?) AND deleted_at IS NULL
+		) AS granted`
+)
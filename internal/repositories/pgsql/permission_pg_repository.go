@@ -0,0 +1,72 @@
+package pgsql
+
+import (
+	"context"
+	"github.com/lib/pq"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PermissionRepository interface {
+	GetAll(ctx context.Context) (permissions []models.Permission, err error)
+	GetGranted(ctx context.Context, roles []string, userTypes []string) (permissions []string, err error)
+	UpdateRolePermissions(ctx context.Context, role string, permissions []string) (rows int64, err error)
+	UpdateUserTypePermissions(ctx context.Context, userType string, permissions []string) (rows int64, err error)
+}
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (pr *permissionRepository) GetAll(ctx context.Context) (permissions []models.Permission, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = pr.db.Where("deleted_at IS NULL").Order("permission ASC").Find(&permissions).Error
+
+	return permissions, err
+}
+
+// GetGranted lists what the roles and the user types are granted together, wildcards included as they are.
+func (pr *permissionRepository) GetGranted(ctx context.Context, roles []string, userTypes []string) (permissions []string, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = pr.db.Raw(queryGetGrantedPermissions, pq.Array(roles), pq.Array(userTypes)).Scan(&permissions).Error
+
+	return permissions, err
+}
+
+func (pr *permissionRepository) UpdateRolePermissions(ctx context.Context, role string, permissions []string) (rows int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := pr.db.Model(&models.Role{}).Where("role = ? AND deleted_at IS NULL", role).Updates(map[string]interface{}{
+		"permissions": pq.StringArray(permissions),
+		"updated_at":  time.Now(),
+	})
+
+	return result.RowsAffected, result.Error
+}
+
+func (pr *permissionRepository) UpdateUserTypePermissions(ctx context.Context, userType string, permissions []string) (rows int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := pr.db.Model(&models.UserType{}).Where("type = ? AND deleted_at IS NULL", userType).Updates(map[string]interface{}{
+		"permissions": pq.StringArray(permissions),
+		"updated_at":  time.Now(),
+	})
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,8 @@
+package pgsql
+
+var (
+	queryHitRateLimitCounter = `INSERT INTO rate_limit_counters (key, window_start, count, expires_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (key, window_start) DO UPDATE SET count = rate_limit_counters.count + 1
+		RETURNING count`
+)
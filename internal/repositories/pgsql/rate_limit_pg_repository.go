@@ -0,0 +1,40 @@
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RateLimitRepository interface {
+	Hit(ctx context.Context, key string, windowStart time.Time, expiresAt time.Time) (count int, err error)
+	DeleteExpired(ctx context.Context, now time.Time) (err error)
+}
+
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+func NewRateLimitRepository(db *gorm.DB) RateLimitRepository {
+	return &rateLimitRepository{db: db}
+}
+
+// Hit counts a request of the key in the window and returns how many there have been in it so far.
+func (rlr *rateLimitRepository) Hit(ctx context.Context, key string, windowStart time.Time, expiresAt time.Time) (count int, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = rlr.db.Raw(queryHitRateLimitCounter, key, windowStart, expiresAt).Scan(&count).Error
+
+	return count, err
+}
+
+func (rlr *rateLimitRepository) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return rlr.db.Exec("DELETE FROM rate_limit_counters WHERE expires_at < ?", now).Error
+}
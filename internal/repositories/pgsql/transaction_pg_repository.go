@@ -12,6 +12,7 @@ import (
 type TransactionRepository interface {
 	Transaction(fc func(dtx *gorm.DB) error) error
 	Atomic(ctx context.Context, fc func(ctx context.Context, r *PostgreRepositories) error) error
+	TryLock(ctx context.Context, key string) (locked bool, err error)
 }
 
 type transactionRepository struct {
@@ -83,3 +84,14 @@ func (tr *transactionRepository) Atomic(ctx context.Context, fc func(ctx context
 
 	return err
 }
+
+// TryLock takes the advisory lock of key until the transaction ends, so it only works on the repositories of Atomic.
+// It does not wait, locked is false when another transaction holds the lock.
+func (tr *transactionRepository) TryLock(ctx context.Context, key string) (locked bool, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = tr.db.WithContext(ctx).Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", key).Scan(&locked).Error
+	return locked, err
+}
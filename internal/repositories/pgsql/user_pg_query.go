@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"fmt"
+	"github.com/lib/pq"
 	"go-community/internal/models"
 	"go-community/internal/pkg/cursor"
 	"strings"
@@ -35,6 +36,7 @@ var (
         u.community_id,
         u.roles,
         u.user_types,
+        u.campus_scopes,
         -- 3. Aggregate the individual roles into one clean array
         ARRAY_AGG(role) FILTER (WHERE role IS NOT NULL) AS combined_roles
 		FROM
@@ -48,7 +50,7 @@ var (
 		WHERE
 			u.community_id = ?
 		GROUP BY
-			u.community_id, u.roles, u.user_types;`
+			u.community_id, u.roles, u.user_types, u.campus_scopes;`
 
 	queryGetUserNameByCommunityId = `SELECT name, community_id
 	FROM users WHERE community_id = ? LIMIT 1`
@@ -213,6 +215,10 @@ func BuildCountGetAllUser(param models.GetAllUserCursorParam) (string, []interfa
 		queryBuilder.WriteString(" AND u.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(u.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.CoolId != 0 {
 		queryBuilder.WriteString(" AND u.cool_id = ?")
 		args = append(args, param.CoolId)
@@ -256,6 +262,10 @@ func BuildQueryGetAllUser(param models.GetAllUserCursorParam) (string, []interfa
 		queryBuilder.WriteString(" AND u.campus_code = ?")
 		args = append(args, param.CampusCode)
 	}
+	if len(param.CampusScope) > 0 {
+		queryBuilder.WriteString(" AND UPPER(u.campus_code) = ANY(?)")
+		args = append(args, pq.Array(param.CampusScope))
+	}
 	if param.CoolId != 0 {
 		queryBuilder.WriteString(" AND u.cool_id = ?")
 		args = append(args, param.CoolId)
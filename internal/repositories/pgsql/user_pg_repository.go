@@ -28,6 +28,7 @@ type UserRepository interface {
 	BulkUpdateRolesByCommunityIds(ctx context.Context, communityIds []string, roles []string) (err error)
 	BulkUpdateUserTypesByCommunityIds(ctx context.Context, communityIds []string, userTypes []string) (err error)
 	UpdateCoolTeamsByCommunityId(ctx context.Context, communityId string, coolId int, userTypes []string) (err error)
+	ClearCoolByCommunityId(ctx context.Context, communityId string, userTypes []string) (err error)
 	CheckMultiple(ctx context.Context, communityIds []string) (count int64, err error)
 	GetDetailByCommunityId(ctx context.Context, communityId string) (output []models.GetUserProfileDBOutput, err error)
 	GetCommunityIdByParams(ctx context.Context, param models.GetCommunityIdsByParameter) (output []models.GetCommunityIdsByParamsDBOutput, err error)
@@ -300,6 +301,18 @@ func (ur *userRepository) UpdateCoolTeamsByCommunityId(ctx context.Context, comm
 	}).Error
 }
 
+func (ur *userRepository) ClearCoolByCommunityId(ctx context.Context, communityId string, userTypes []string) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	user := models.User{}
+	return ur.db.Model(user).Where("community_id = ?", communityId).Updates(map[string]interface{}{
+		"user_types": pq.Array(userTypes),
+		"cool_id":    gorm.Expr("NULL"),
+	}).Error
+}
+
 func (ur *userRepository) CheckMultiple(ctx context.Context, communityIds []string) (count int64, err error) {
 	defer func() {
 		LogRepository(ctx, err)
@@ -0,0 +1,10 @@
+package pgsql
+
+var (
+	// A session is listed by the active row of its family, the first row of the family being the login
+	queryGetActiveSessionsByCommunityId = `SELECT s.family_id, s.user_agent, s.ip_address, s.expires_at, s.created_at AS last_used_at, f.created_at
+		FROM user_sessions s
+		JOIN (SELECT family_id, MIN(created_at) AS created_at FROM user_sessions WHERE community_id = ? GROUP BY family_id) f ON f.family_id = s.family_id
+		WHERE s.community_id = ? AND s.revoked_at IS NULL AND s.expires_at > ?
+		ORDER BY s.created_at DESC`
+)
@@ -0,0 +1,114 @@
+package pgsql
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"go-community/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserSessionRepository interface {
+	Create(ctx context.Context, session *models.UserSession) (err error)
+	GetById(ctx context.Context, id uuid.UUID) (session models.UserSession, err error)
+	GetActiveByCommunityId(ctx context.Context, communityId string, now time.Time) (output []models.GetUserSessionDBOutput, err error)
+	Rotate(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID, now time.Time) (rowsAffected int64, err error)
+	RevokeByFamilyId(ctx context.Context, familyId uuid.UUID, reason string, now time.Time) (rowsAffected int64, err error)
+	RevokeByCommunityIdAndFamilyId(ctx context.Context, communityId string, familyId uuid.UUID, reason string, now time.Time) (rowsAffected int64, err error)
+	RevokeByCommunityId(ctx context.Context, communityId string, exceptFamilyId uuid.UUID, reason string, now time.Time) (err error)
+}
+
+type userSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) UserSessionRepository {
+	return &userSessionRepository{db: db}
+}
+
+func (usr *userSessionRepository) Create(ctx context.Context, session *models.UserSession) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return usr.db.Create(session).Error
+}
+
+func (usr *userSessionRepository) GetById(ctx context.Context, id uuid.UUID) (session models.UserSession, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = usr.db.Where("id = ?", id).Find(&session).Error
+
+	return session, err
+}
+
+func (usr *userSessionRepository) GetActiveByCommunityId(ctx context.Context, communityId string, now time.Time) (output []models.GetUserSessionDBOutput, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	err = usr.db.Raw(queryGetActiveSessionsByCommunityId, communityId, communityId, now).Scan(&output).Error
+
+	return output, err
+}
+
+// Rotate revokes the session in favour of replacedBy. It only takes a session that is still active, so when the same
+// refresh token is used twice at once only one of the requests gets a row.
+func (usr *userSessionRepository) Rotate(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID, now time.Time) (rowsAffected int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := usr.db.Model(&models.UserSession{}).Where("id = ? AND revoked_at IS NULL", id).Updates(map[string]interface{}{
+		"replaced_by":    replacedBy,
+		"revoked_at":     now,
+		"revoked_reason": models.MapSessionRevokedReason[models.SESSION_REVOKED_ROTATED],
+		"updated_at":     now,
+	})
+
+	return result.RowsAffected, result.Error
+}
+
+func (usr *userSessionRepository) RevokeByFamilyId(ctx context.Context, familyId uuid.UUID, reason string, now time.Time) (rowsAffected int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := usr.db.Model(&models.UserSession{}).Where("family_id = ? AND revoked_at IS NULL", familyId).Updates(map[string]interface{}{
+		"revoked_at":     now,
+		"revoked_reason": reason,
+		"updated_at":     now,
+	})
+
+	return result.RowsAffected, result.Error
+}
+
+func (usr *userSessionRepository) RevokeByCommunityIdAndFamilyId(ctx context.Context, communityId string, familyId uuid.UUID, reason string, now time.Time) (rowsAffected int64, err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	result := usr.db.Model(&models.UserSession{}).Where("community_id = ? AND family_id = ? AND revoked_at IS NULL", communityId, familyId).Updates(map[string]interface{}{
+		"revoked_at":     now,
+		"revoked_reason": reason,
+		"updated_at":     now,
+	})
+
+	return result.RowsAffected, result.Error
+}
+
+// RevokeByCommunityId revokes every session of the user except the one of exceptFamilyId, which can be uuid.Nil.
+func (usr *userSessionRepository) RevokeByCommunityId(ctx context.Context, communityId string, exceptFamilyId uuid.UUID, reason string, now time.Time) (err error) {
+	defer func() {
+		LogRepository(ctx, err)
+	}()
+
+	return usr.db.Model(&models.UserSession{}).Where("community_id = ? AND family_id <> ? AND revoked_at IS NULL", communityId, exceptFamilyId).Updates(map[string]interface{}{
+		"revoked_at":     now,
+		"revoked_reason": reason,
+		"updated_at":     now,
+	}).Error
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"go-community/internal/common"
+	"go-community/internal/models"
+	"go-community/internal/repositories/pgsql"
+	"reflect"
+)
+
+// auditIgnoredFields are never written to the audit log, either because they are secret or because they change on every write
+var auditIgnoredFields = []string{"Password", "CreatedAt", "UpdatedAt", "DeletedAt", "Campus", "CoolCategory"}
+
+type auditUsecase struct {
+	r pgsql.PostgreRepositories
+}
+
+func NewAuditUsecase(r pgsql.PostgreRepositories) *auditUsecase {
+	return &auditUsecase{
+		r: r,
+	}
+}
+
+func (au *auditUsecase) GetAll(ctx context.Context, param models.GetAllAuditEventCursorParam) (res []models.AuditEventResponse, info *models.CursorInfo, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if param.From != "" {
+		from, err := common.ParseStringToDatetime("2006-01-02", param.From, common.GetLocation())
+		if err != nil {
+			return nil, nil, models.ErrorInvalidInput
+		}
+		param.CreatedFrom = &from
+	}
+
+	if param.To != "" {
+		to, err := common.ParseStringToDatetime("2006-01-02", param.To, common.GetLocation())
+		if err != nil {
+			return nil, nil, models.ErrorInvalidInput
+		}
+
+		// To is inclusive, so the range ends at the start of the day after
+		until := to.AddDate(0, 0, 1)
+		param.CreatedUntil = &until
+	}
+
+	if param.CreatedFrom != nil && param.CreatedUntil != nil && !param.CreatedFrom.Before(*param.CreatedUntil) {
+		return nil, nil, models.ErrorStartDateLater
+	}
+
+	output, pagination, err := au.r.AuditEvent.GetAll(ctx, param)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res = make([]models.AuditEventResponse, len(output))
+	for i, event := range output {
+		res[i] = event.ToResponse()
+	}
+
+	info = &models.CursorInfo{
+		PreviousCursor: pagination.Prev,
+		NextCursor:     pagination.Next,
+		TotalData:      pagination.Total,
+	}
+
+	return res, info, nil
+}
+
+// recordAudit writes the change made by the caller with the repositories of the transaction of the change, so the event
+// only exists if the change does. Updates that changed nothing are not recorded, and changes without a caller are
+// refused, as no one could be held to them.
+func recordAudit(ctx context.Context, r *pgsql.PostgreRepositories, action string, entityType string, entityId string, before map[string]interface{}, after map[string]interface{}) error {
+	value, _ := models.TokenValuesFromContext(ctx)
+	return writeAudit(ctx, r, value.Id, action, entityType, entityId, before, after)
+}
+
+// recordSystemAudit is recordAudit for changes the app makes on its own rather than on behalf of a caller.
+func recordSystemAudit(ctx context.Context, r *pgsql.PostgreRepositories, action string, entityType string, entityId string, before map[string]interface{}, after map[string]interface{}) error {
+	return writeAudit(ctx, r, models.AUDIT_ACTOR_SYSTEM, action, entityType, entityId, before, after)
+}
+
+func writeAudit(ctx context.Context, r *pgsql.PostgreRepositories, actor string, action string, entityType string, entityId string, before map[string]interface{}, after map[string]interface{}) error {
+	if actor == "" {
+		return models.ErrorUnauthorized
+	}
+
+	if before != nil && after != nil {
+		before, after = auditDiff(before, after)
+		if len(before) == 0 && len(after) == 0 {
+			return nil
+		}
+	}
+
+	return r.AuditEvent.Create(ctx, &models.AuditEvent{
+		ActorCommunityId: actor,
+		Action:           action,
+		EntityType:       entityType,
+		EntityId:         entityId,
+		Before:           before,
+		After:            after,
+		RequestId:        models.RequestIdFromContext(ctx),
+	})
+}
+
+// auditSnapshot copies the fields of the entity as they are now, so later changes to it do not change the snapshot.
+func auditSnapshot(entity interface{}) map[string]interface{} {
+	bytes, err := json.Marshal(entity)
+	if err != nil {
+		return nil
+	}
+
+	snapshot := map[string]interface{}{}
+	if err := json.Unmarshal(bytes, &snapshot); err != nil {
+		return nil
+	}
+
+	for _, field := range auditIgnoredFields {
+		delete(snapshot, field)
+	}
+
+	return snapshot
+}
+
+// auditDiff keeps only the fields whose value differs between the snapshots.
+func auditDiff(before map[string]interface{}, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+
+	for field, value := range before {
+		if afterValue, ok := after[field]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changedBefore[field] = value
+		}
+	}
+
+	for field, value := range after {
+		if beforeValue, ok := before[field]; !ok || !reflect.DeepEqual(value, beforeValue) {
+			changedAfter[field] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"go-community/internal/models"
+	"slices"
+	"strings"
+)
+
+// campusScope is the campuses the user of the access token in ctx administers, or nil when they administer every campus.
+func campusScope(ctx context.Context) []string {
+	value, ok := models.TokenValuesFromContext(ctx)
+	if !ok || len(value.Campuses) == 0 {
+		return nil
+	}
+
+	scope := make([]string, len(value.Campuses))
+	for i, campus := range value.Campuses {
+		scope[i] = strings.ToUpper(campus)
+	}
+
+	return scope
+}
+
+// inCampusScope reports whether the user of the access token in ctx administers the campus.
+func inCampusScope(ctx context.Context, campusCode string) bool {
+	scope := campusScope(ctx)
+	return scope == nil || slices.Contains(scope, strings.ToUpper(campusCode))
+}
+
+// scopeCampusFilter narrows a list filtered by campusCode to the campuses the user administers: a campus outside of
+// them is rejected, and without a campus the list is filtered by all of them.
+func scopeCampusFilter(ctx context.Context, campusCode string) ([]string, error) {
+	if campusCode != "" {
+		if !inCampusScope(ctx, campusCode) {
+			return nil, models.ErrorOutOfCampusScope
+		}
+
+		return nil, nil
+	}
+
+	return campusScope(ctx), nil
+}
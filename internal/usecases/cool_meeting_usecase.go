@@ -0,0 +1,535 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-community/internal/common"
+	"go-community/internal/config"
+	"go-community/internal/constants"
+	"go-community/internal/models"
+	"go-community/internal/pkg/logger"
+	"go-community/internal/pkg/recurrence"
+	"go-community/internal/repositories/pgsql"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type CoolMeetingUsecase interface {
+	GetAll(ctx context.Context, param models.GetAllCoolMeetingParameter) (response []models.CoolMeetingResponse, err error)
+	Create(ctx context.Context, param models.CoolMemberParameter, request models.CreateCoolMeetingRequest) (response *models.CoolMeetingResponse, err error)
+	Generate(ctx context.Context, param models.CoolMemberParameter) (response []models.CoolMeetingResponse, err error)
+	GenerateAll(ctx context.Context) (err error)
+	Cancel(ctx context.Context, param models.CoolMeetingParameter) (err error)
+	GetAttendance(ctx context.Context, param models.CoolMeetingParameter) (response []models.CoolMeetingAttendanceResponse, err error)
+	MarkAttendance(ctx context.Context, param models.CoolMeetingParameter, request models.MarkCoolMeetingAttendanceRequest) (response []models.CoolMeetingAttendanceResponse, err error)
+	GetReport(ctx context.Context, param models.GetCoolAttendanceParameter) (detail *models.GetCoolAttendanceDetailResponse, response []models.GetCoolAttendanceListResponse, err error)
+}
+
+type coolMeetingUsecase struct {
+	cfg *config.Configuration
+	r   pgsql.PostgreRepositories
+	p   *permissionUsecase
+}
+
+func NewCoolMeetingUsecase(cfg config.Configuration, r pgsql.PostgreRepositories, p *permissionUsecase) *coolMeetingUsecase {
+	return &coolMeetingUsecase{
+		cfg: &cfg,
+		r:   r,
+		p:   p,
+	}
+}
+
+func (cmu *coolMeetingUsecase) GetAll(ctx context.Context, param models.GetAllCoolMeetingParameter) (response []models.CoolMeetingResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if _, err = cmu.authorizeCool(ctx, param.CoolId, true); err != nil {
+		return nil, err
+	}
+
+	now := common.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if param.From != "" {
+		from, err = common.ParseStringToDatetime("2006-01-02", param.From, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+	}
+
+	until := from.Add(recurrenceHorizon(cmu.cfg))
+	if param.To != "" {
+		to, err := common.ParseStringToDatetime("2006-01-02", param.To, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+		until = to.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(until) {
+		return nil, models.ErrorStartDateLater
+	}
+
+	// A list covers at most a year, the same as a recurrence preview
+	if until.Sub(from) > 366*24*time.Hour {
+		return nil, models.ErrorInvalidInput
+	}
+
+	meetings, err := cmu.r.CoolMeeting.GetManyByCoolId(ctx, param.CoolId, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CoolMeetingResponse, len(meetings))
+	for i, meeting := range meetings {
+		response[i] = meeting.ToResponse()
+	}
+
+	return response, nil
+}
+
+func (cmu *coolMeetingUsecase) Create(ctx context.Context, param models.CoolMemberParameter, request models.CreateCoolMeetingRequest) (response *models.CoolMeetingResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cool, err := cmu.authorizeCool(ctx, param.CoolId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt, err := common.ParseStringToDatetime(time.RFC3339, request.StartAt, common.GetLocation())
+	if err != nil {
+		return nil, models.ErrorInvalidInput
+	}
+
+	endAt, err := common.ParseStringToDatetime(time.RFC3339, request.EndAt, common.GetLocation())
+	if err != nil {
+		return nil, models.ErrorInvalidInput
+	}
+
+	if !startAt.Before(endAt) {
+		return nil, models.ErrorStartDateLater
+	}
+
+	meeting := models.CoolMeeting{
+		CoolId:       cool.ID,
+		Title:        request.Title,
+		StartAt:      startAt,
+		EndAt:        endAt,
+		LocationType: request.LocationType,
+		LocationName: request.LocationName,
+		Status:       models.MapCoolMeetingStatus[models.COOL_MEETING_STATUS_ACTIVE],
+		CreatedBy:    callerCommunityId(ctx),
+	}
+
+	// An ad hoc meeting takes the place of the cool when it is not told otherwise
+	if meeting.LocationType == "" {
+		meeting.LocationType = cool.LocationType
+		meeting.LocationName = cool.LocationName
+	}
+
+	if err = cmu.r.CoolMeeting.Create(ctx, &meeting); err != nil {
+		return nil, err
+	}
+
+	res := meeting.ToResponse()
+	return &res, nil
+}
+
+func (cmu *coolMeetingUsecase) Generate(ctx context.Context, param models.CoolMemberParameter) (response []models.CoolMeetingResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cool, err := cmu.authorizeCool(ctx, param.CoolId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if cool.MeetingSchedule() == nil {
+		return nil, models.ErrorCoolNotRecurring
+	}
+
+	var meetings []models.CoolMeeting
+	err = cmu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		now := common.Now()
+		meetings, err = generateCoolMeetings(ctx, r, cool, now, now.Add(recurrenceHorizon(cmu.cfg)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CoolMeetingResponse, len(meetings))
+	for i, meeting := range meetings {
+		response[i] = meeting.ToResponse()
+	}
+
+	return response, nil
+}
+
+// GenerateAll tops up the meetings of every active cool with a meeting rule until the configured horizon.
+// It is run by the scheduler, so a failing cool is logged and skipped instead of stopping the others.
+func (cmu *coolMeetingUsecase) GenerateAll(ctx context.Context) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cools, err := cmu.r.Cool.GetAllWithMeetingRule(ctx, constants.MapStatus[constants.STATUS_ACTIVE])
+	if err != nil {
+		return err
+	}
+
+	now := common.Now()
+	until := now.Add(recurrenceHorizon(cmu.cfg))
+	var errs []error
+	for _, cool := range cools {
+		err := cmu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+			meetings, err := generateCoolMeetings(ctx, r, cool, now, until)
+			if err != nil {
+				return err
+			}
+
+			if len(meetings) > 0 {
+				logger.Logger.Info("[COOL_MEETING] generated meetings", zap.Int("coolId", cool.ID), zap.Int("total", len(meetings)))
+			}
+
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cool %d: %w", cool.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Cancel keeps the meeting, so a cancelled occurrence of the rule is not generated again, but leaves it out of the reports.
+func (cmu *coolMeetingUsecase) Cancel(ctx context.Context, param models.CoolMeetingParameter) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	meeting, err := cmu.getMeeting(ctx, param)
+	if err != nil {
+		return err
+	}
+
+	if meeting.Status == models.MapCoolMeetingStatus[models.COOL_MEETING_STATUS_CANCELLED] {
+		return nil
+	}
+
+	return cmu.r.CoolMeeting.UpdateStatusById(ctx, meeting.ID, models.MapCoolMeetingStatus[models.COOL_MEETING_STATUS_CANCELLED])
+}
+
+func (cmu *coolMeetingUsecase) GetAttendance(ctx context.Context, param models.CoolMeetingParameter) (response []models.CoolMeetingAttendanceResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	meeting, err := cmu.getMeeting(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmu.attendanceResponse(ctx, &cmu.r, meeting.ID)
+}
+
+// MarkAttendance records the attendance of the members in the request and leaves the others as they are, so a meeting
+// can be marked in several goes. Only those who were in the cool when the meeting started can be marked.
+func (cmu *coolMeetingUsecase) MarkAttendance(ctx context.Context, param models.CoolMeetingParameter, request models.MarkCoolMeetingAttendanceRequest) (response []models.CoolMeetingAttendanceResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	meeting, err := cmu.getMeeting(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.Status == models.MapCoolMeetingStatus[models.COOL_MEETING_STATUS_CANCELLED] {
+		return nil, models.ErrorCoolMeetingCancelled
+	}
+
+	now := common.Now()
+	if meeting.StartAt.After(now) {
+		return nil, models.ErrorCoolMeetingNotStarted
+	}
+
+	err = cmu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		members, err := r.CoolMeetingAttendance.GetManyByMeetingId(ctx, meeting.ID)
+		if err != nil {
+			return err
+		}
+
+		markedBy := callerCommunityId(ctx)
+		attendances := make([]models.CoolMeetingAttendance, len(request.Attendances))
+		for i, attendance := range request.Attendances {
+			isMember := slices.ContainsFunc(members, func(member models.CoolMeetingAttendanceDBOutput) bool {
+				return member.CommunityId == attendance.CommunityId
+			})
+			if !isMember {
+				return models.ErrorNotCoolMemberAtMeeting
+			}
+
+			attendances[i] = models.CoolMeetingAttendance{
+				MeetingId:   meeting.ID,
+				CommunityId: attendance.CommunityId,
+				Status:      attendance.Status,
+				Reason:      attendance.Reason,
+				MarkedBy:    markedBy,
+				CreatedAt:   &now,
+				UpdatedAt:   &now,
+			}
+		}
+
+		if err := r.CoolMeetingAttendance.Upsert(ctx, &attendances); err != nil {
+			return err
+		}
+
+		response, err = cmu.attendanceResponse(ctx, r, meeting.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetReport is the attendance rate of the cool and of everyone who was in it over a date range, the current year by
+// default. A member is only counted for the meetings held while they were in the cool, and absence is every one of those
+// they were not marked present or permitted at.
+func (cmu *coolMeetingUsecase) GetReport(ctx context.Context, param models.GetCoolAttendanceParameter) (detail *models.GetCoolAttendanceDetailResponse, response []models.GetCoolAttendanceListResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if _, err = cmu.authorizeCool(ctx, param.CoolId, false); err != nil {
+		return nil, nil, err
+	}
+
+	now := common.Now()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	if param.From != "" {
+		from, err = common.ParseStringToDatetime("2006-01-02", param.From, common.GetLocation())
+		if err != nil {
+			return nil, nil, models.ErrorInvalidInput
+		}
+	}
+
+	to := time.Date(now.Year(), time.December, 31, 0, 0, 0, 0, now.Location())
+	if param.To != "" {
+		to, err = common.ParseStringToDatetime("2006-01-02", param.To, common.GetLocation())
+		if err != nil {
+			return nil, nil, models.ErrorInvalidInput
+		}
+	}
+
+	if from.After(to) {
+		return nil, nil, models.ErrorStartDateLater
+	}
+
+	until := to.AddDate(0, 0, 1)
+	totalMeetings, err := cmu.r.CoolMeeting.CountHeldByCoolId(ctx, param.CoolId, from, until, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := cmu.r.CoolMeeting.GetAttendanceReport(ctx, param.CoolId, from, until, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var totalPresent, totalExpected int
+	response = make([]models.GetCoolAttendanceListResponse, len(report))
+	for i, r := range report {
+		totalPresent += r.PresentCount
+		totalExpected += r.TotalMeetings
+
+		response[i] = models.GetCoolAttendanceListResponse{
+			Type:                 models.TYPE_COOL_MEMBER,
+			CommunityId:          r.CommunityId,
+			Name:                 r.Name,
+			Role:                 r.Role,
+			IsMember:             r.IsMember,
+			AttendanceCount:      r.PresentCount,
+			PermitCount:          r.PermitCount,
+			AbsenceCount:         r.TotalMeetings - r.PresentCount - r.PermitCount,
+			TotalMeetings:        r.TotalMeetings,
+			AttendancePercentage: attendancePercentage(r.PresentCount, r.TotalMeetings),
+		}
+	}
+
+	detail = &models.GetCoolAttendanceDetailResponse{
+		Type:                 models.TYPE_COOL,
+		CoolId:               param.CoolId,
+		From:                 from.Format("2006-01-02"),
+		To:                   to.Format("2006-01-02"),
+		TotalMeetings:        int(totalMeetings),
+		AttendancePercentage: attendancePercentage(totalPresent, totalExpected),
+	}
+
+	return detail, response, nil
+}
+
+// authorizeCool loads the cool and checks the caller runs it: its leaders, core team and facilitators, or an internal
+// user of its campus. With allowMembers its current members are let in as well.
+func (cmu *coolMeetingUsecase) authorizeCool(ctx context.Context, coolId int, allowMembers bool) (cool models.Cool, err error) {
+	cool, err = cmu.r.Cool.GetOneById(ctx, coolId)
+	if err != nil {
+		return cool, err
+	}
+
+	if cool.ID == 0 || cool.DeletedAt.Valid {
+		return cool, models.ErrorDataNotFound
+	}
+
+	communityId := callerCommunityId(ctx)
+	if communityId != "" && (slices.Contains(cool.LeaderCommunityIds, communityId) || slices.Contains(cool.CoreCommunityIds, communityId) || slices.Contains(cool.FacilitatorCommunityIds, communityId)) {
+		return cool, nil
+	}
+
+	if allowMembers && communityId != "" {
+		member, err := cmu.r.CoolMember.GetCurrentByCommunityId(ctx, communityId)
+		if err != nil {
+			return cool, err
+		}
+
+		if member != nil && member.CoolId == cool.ID {
+			return cool, nil
+		}
+	}
+
+	if err = cmu.p.Authorize(ctx, models.PERMISSION_INTERNAL_ACCESS, ""); err != nil {
+		return cool, err
+	}
+
+	if !inCampusScope(ctx, cool.CampusCode) {
+		return cool, models.ErrorOutOfCampusScope
+	}
+
+	return cool, nil
+}
+
+// getMeeting authorizes the caller on the cool and loads the meeting, which has to belong to it.
+func (cmu *coolMeetingUsecase) getMeeting(ctx context.Context, param models.CoolMeetingParameter) (meeting models.CoolMeeting, err error) {
+	if _, err = cmu.authorizeCool(ctx, param.CoolId, false); err != nil {
+		return meeting, err
+	}
+
+	meeting, err = cmu.r.CoolMeeting.GetOneById(ctx, param.MeetingId)
+	if err != nil {
+		return meeting, err
+	}
+
+	if meeting.ID == 0 || meeting.CoolId != param.CoolId {
+		return meeting, models.ErrorDataNotFound
+	}
+
+	return meeting, nil
+}
+
+func (cmu *coolMeetingUsecase) attendanceResponse(ctx context.Context, r *pgsql.PostgreRepositories, meetingId int) ([]models.CoolMeetingAttendanceResponse, error) {
+	attendances, err := r.CoolMeetingAttendance.GetManyByMeetingId(ctx, meetingId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]models.CoolMeetingAttendanceResponse, len(attendances))
+	for i, attendance := range attendances {
+		response[i] = attendance.ToResponse()
+	}
+
+	return response, nil
+}
+
+// validateMeetingRule checks the meeting rule of a cool and parses the time its first meeting starts at.
+func validateMeetingRule(rule string, startAt string) (*time.Time, error) {
+	if rule == "" {
+		return nil, nil
+	}
+
+	if _, err := recurrence.Parse(rule); err != nil {
+		return nil, models.ErrorInvalidRecurrence
+	}
+
+	start, err := common.ParseStringToDatetime(time.RFC3339, startAt, common.GetLocation())
+	if err != nil {
+		return nil, models.ErrorInvalidInput
+	}
+
+	return &start, nil
+}
+
+// generateCoolMeetings creates the meetings of the occurrences of the meeting rule of the cool within [from, until]
+// that were not generated before. A cancelled occurrence still has its meeting, so it stays cancelled.
+func generateCoolMeetings(ctx context.Context, r *pgsql.PostgreRepositories, cool models.Cool, from time.Time, until time.Time) ([]models.CoolMeeting, error) {
+	schedule := cool.MeetingSchedule()
+	if schedule == nil {
+		return nil, nil
+	}
+
+	rule, err := recurrence.Parse(schedule.Rule)
+	if err != nil {
+		return nil, models.ErrorInvalidRecurrence
+	}
+
+	existing, err := r.CoolMeeting.GetRecurrenceAtsByCoolId(ctx, cool.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := make(map[int64]bool, len(existing))
+	for _, at := range existing {
+		generated[at.Unix()] = true
+	}
+
+	duration := time.Duration(schedule.DurationMinutes) * time.Minute
+	start := schedule.StartAt.In(common.GetLocation())
+	var meetings []models.CoolMeeting
+	for _, at := range rule.Between(start, from.In(common.GetLocation()), until.In(common.GetLocation())) {
+		if generated[at.Unix()] {
+			continue
+		}
+
+		meetings = append(meetings, models.CoolMeeting{
+			CoolId:       cool.ID,
+			Title:        cool.Name,
+			StartAt:      at,
+			EndAt:        at.Add(duration),
+			LocationType: cool.LocationType,
+			LocationName: cool.LocationName,
+			RecurrenceAt: sql.NullTime{Time: at, Valid: true},
+			Status:       models.MapCoolMeetingStatus[models.COOL_MEETING_STATUS_ACTIVE],
+		})
+	}
+
+	if len(meetings) == 0 {
+		return nil, nil
+	}
+
+	if err = r.CoolMeeting.BulkCreate(ctx, &meetings); err != nil {
+		return nil, err
+	}
+
+	return meetings, nil
+}
+
+func callerCommunityId(ctx context.Context) string {
+	value, _ := models.TokenValuesFromContext(ctx)
+	return value.Id
+}
+
+func attendancePercentage(present int, total int) float64 {
+	if total == 0 {
+		return 0.00
+	}
+
+	return float64(present) / float64(total) * 100
+}
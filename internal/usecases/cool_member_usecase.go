@@ -0,0 +1,406 @@
+package usecases
+
+import (
+	"context"
+	"go-community/internal/common"
+	"go-community/internal/models"
+	"go-community/internal/repositories/pgsql"
+	"slices"
+	"time"
+)
+
+type coolMemberUsecase struct {
+	r pgsql.PostgreRepositories
+}
+
+func NewCoolMemberUsecase(r pgsql.PostgreRepositories) *coolMemberUsecase {
+	return &coolMemberUsecase{
+		r: r,
+	}
+}
+
+func (cmu *coolMemberUsecase) GetAll(ctx context.Context, param models.CoolMemberParameter) (response []models.CoolMemberResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = cmu.checkCool(ctx, param.CoolId); err != nil {
+		return nil, err
+	}
+
+	members, err := cmu.r.CoolMember.GetCurrentByCoolId(ctx, param.CoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CoolMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = member.ToResponse()
+	}
+
+	return response, nil
+}
+
+func (cmu *coolMemberUsecase) Add(ctx context.Context, param models.CoolMemberParameter, request models.AddCoolMemberRequest) (response *models.CoolMemberResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = cmu.checkCool(ctx, param.CoolId); err != nil {
+		return nil, err
+	}
+
+	joinedAt := common.Now()
+	if request.JoinedAt != "" {
+		joinedAt, err = common.ParseStringToDatetime("2006-01-02", request.JoinedAt, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+	}
+
+	role := request.Role
+	if role == "" {
+		role = models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_MEMBER]
+	}
+
+	err = cmu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		user, err := r.User.GetOneByCommunityId(ctx, request.CommunityId)
+		if err != nil {
+			return err
+		}
+
+		if user.ID == 0 {
+			return models.ErrorDataNotFound
+		}
+
+		current, err := r.CoolMember.GetCurrentByCommunityId(ctx, request.CommunityId)
+		if err != nil {
+			return err
+		}
+
+		if current != nil {
+			return models.ErrorAlreadyCoolMember
+		}
+
+		_, member, err := joinCool(ctx, r, request.CommunityId, param.CoolId, role, joinedAt)
+		if err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_COOL_MEMBER_ADD, models.TYPE_COOL_MEMBER, request.CommunityId, nil, auditSnapshot(member)); err != nil {
+			return err
+		}
+
+		response = coolMemberResponse(user, member)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (cmu *coolMemberUsecase) Transfer(ctx context.Context, param models.CoolMemberCommunityIdParameter, request models.TransferCoolMemberRequest) (response *models.CoolMemberResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = cmu.checkCool(ctx, param.CoolId); err != nil {
+		return nil, err
+	}
+
+	if err = cmu.checkCool(ctx, request.CoolId); err != nil {
+		return nil, err
+	}
+
+	role := request.Role
+	if role == "" {
+		role = models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_MEMBER]
+	}
+
+	err = cmu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		current, err := r.CoolMember.GetCurrentByCommunityId(ctx, param.CommunityId)
+		if err != nil {
+			return err
+		}
+
+		if current == nil || current.CoolId != param.CoolId {
+			return models.ErrorDataNotFound
+		}
+
+		user, err := r.User.GetOneByCommunityId(ctx, param.CommunityId)
+		if err != nil {
+			return err
+		}
+
+		previous, member, err := joinCool(ctx, r, param.CommunityId, request.CoolId, role, common.Now())
+		if err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_COOL_MEMBER_TRANSFER, models.TYPE_COOL_MEMBER, param.CommunityId, auditSnapshot(previous), auditSnapshot(member)); err != nil {
+			return err
+		}
+
+		response = coolMemberResponse(user, member)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (cmu *coolMemberUsecase) Remove(ctx context.Context, param models.CoolMemberCommunityIdParameter) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = cmu.checkCool(ctx, param.CoolId); err != nil {
+		return err
+	}
+
+	return cmu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		current, err := r.CoolMember.GetCurrentByCommunityId(ctx, param.CommunityId)
+		if err != nil {
+			return err
+		}
+
+		if current == nil || current.CoolId != param.CoolId {
+			return models.ErrorDataNotFound
+		}
+
+		previous, err := leaveCool(ctx, r, param.CommunityId, common.Now())
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_MEMBER_REMOVE, models.TYPE_COOL_MEMBER, param.CommunityId, auditSnapshot(previous), nil)
+	})
+}
+
+// checkCool makes sure the cool exists and is on a campus the caller administers.
+func (cmu *coolMemberUsecase) checkCool(ctx context.Context, coolId int) error {
+	cool, err := cmu.r.Cool.GetOneById(ctx, coolId)
+	if err != nil {
+		return err
+	}
+
+	if cool.ID == 0 || cool.DeletedAt.Valid {
+		return models.ErrorDataNotFound
+	}
+
+	if !inCampusScope(ctx, cool.CampusCode) {
+		return models.ErrorOutOfCampusScope
+	}
+
+	return nil
+}
+
+func coolMemberResponse(user models.User, member *models.CoolMember) *models.CoolMemberResponse {
+	return &models.CoolMemberResponse{
+		Type:        models.TYPE_COOL_MEMBER,
+		CoolId:      member.CoolId,
+		CommunityId: member.CommunityId,
+		Name:        user.Name,
+		PhoneNumber: user.PhoneNumber,
+		Email:       user.Email,
+		Role:        member.Role,
+		JoinedAt:    member.JoinedAt,
+	}
+}
+
+// syncCoolMember moves the user into the cool as a member when users.cool_id was changed outside of the member
+// endpoints, so their membership history follows it.
+func syncCoolMember(ctx context.Context, r *pgsql.PostgreRepositories, communityId string, coolId int) error {
+	if coolId == 0 {
+		return nil
+	}
+
+	current, err := r.CoolMember.GetCurrentByCommunityId(ctx, communityId)
+	if err != nil {
+		return err
+	}
+
+	if current != nil && current.CoolId == coolId {
+		return nil
+	}
+
+	_, _, err = joinCool(ctx, r, communityId, coolId, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_MEMBER], common.Now())
+	return err
+}
+
+// joinCool ends the current membership of the user, if any, and starts one in the cool with the role. The team of
+// both cools, the user types that come with the roles and users.cool_id are kept in step.
+func joinCool(ctx context.Context, r *pgsql.PostgreRepositories, communityId string, coolId int, role string, at time.Time) (previous *models.CoolMember, member *models.CoolMember, err error) {
+	user, err := r.User.GetOneByCommunityId(ctx, communityId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.ID == 0 {
+		return nil, nil, models.ErrorDataNotFound
+	}
+
+	previous, err = r.CoolMember.GetCurrentByCommunityId(ctx, communityId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userTypes := []string(user.UserTypes)
+	if previous != nil {
+		if err := r.CoolMember.Leave(ctx, previous.ID, at); err != nil {
+			return nil, nil, err
+		}
+
+		if err := updateCoolTeam(ctx, r, previous.CoolId, communityId, ""); err != nil {
+			return nil, nil, err
+		}
+		userTypes = withoutCoolRoleUserTypes(userTypes)
+	}
+
+	member = &models.CoolMember{
+		CoolId:      coolId,
+		CommunityId: communityId,
+		Role:        role,
+		JoinedAt:    at,
+	}
+
+	if err := r.CoolMember.Create(ctx, member); err != nil {
+		return nil, nil, err
+	}
+
+	if err := updateCoolTeam(ctx, r, coolId, communityId, role); err != nil {
+		return nil, nil, err
+	}
+
+	if userType, ok := models.MapCoolMemberRoleUserType[role]; ok && !slices.Contains(userTypes, userType) {
+		userTypes = append(userTypes, userType)
+	}
+
+	if err := r.User.UpdateCoolTeamsByCommunityId(ctx, communityId, coolId, userTypes); err != nil {
+		return nil, nil, err
+	}
+
+	return previous, member, nil
+}
+
+// leaveCool ends the current membership of the user and takes them out of the team of the cool.
+func leaveCool(ctx context.Context, r *pgsql.PostgreRepositories, communityId string, at time.Time) (previous *models.CoolMember, err error) {
+	user, err := r.User.GetOneByCommunityId(ctx, communityId)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err = r.CoolMember.GetCurrentByCommunityId(ctx, communityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == nil {
+		return nil, models.ErrorDataNotFound
+	}
+
+	if err := r.CoolMember.Leave(ctx, previous.ID, at); err != nil {
+		return nil, err
+	}
+
+	if err := updateCoolTeam(ctx, r, previous.CoolId, communityId, ""); err != nil {
+		return nil, err
+	}
+
+	if err := r.User.ClearCoolByCommunityId(ctx, communityId, withoutCoolRoleUserTypes(user.UserTypes)); err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// setCoolRole makes the user a current member of the cool with the role, moving them in from where they are if needed.
+func setCoolRole(ctx context.Context, r *pgsql.PostgreRepositories, coolId int, communityId string, role string, at time.Time) error {
+	current, err := r.CoolMember.GetCurrentByCommunityId(ctx, communityId)
+	if err != nil {
+		return err
+	}
+
+	if current != nil && current.CoolId == coolId && current.Role == role {
+		return updateCoolTeam(ctx, r, coolId, communityId, role)
+	}
+
+	_, _, err = joinCool(ctx, r, communityId, coolId, role, at)
+	return err
+}
+
+// leaveCoolTeam takes the user out of the leaders and core team of the cool. A current member of the cool stays on as a
+// member and loses the user type of their role, which they keep when they are in a team of another cool.
+func leaveCoolTeam(ctx context.Context, r *pgsql.PostgreRepositories, coolId int, communityId string, at time.Time) error {
+	current, err := r.CoolMember.GetCurrentByCommunityId(ctx, communityId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current != nil && current.CoolId == coolId:
+		_, _, err = joinCool(ctx, r, communityId, coolId, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_MEMBER], at)
+		return err
+	case current != nil:
+		return updateCoolTeam(ctx, r, coolId, communityId, "")
+	}
+
+	if err := updateCoolTeam(ctx, r, coolId, communityId, ""); err != nil {
+		return err
+	}
+
+	user, err := r.User.GetOneByCommunityId(ctx, communityId)
+	if err != nil {
+		return err
+	}
+
+	if user.CoolID == 0 {
+		return r.User.ClearCoolByCommunityId(ctx, communityId, withoutCoolRoleUserTypes(user.UserTypes))
+	}
+
+	return r.User.UpdateCoolTeamsByCommunityId(ctx, communityId, user.CoolID, withoutCoolRoleUserTypes(user.UserTypes))
+}
+
+// updateCoolTeam puts the user in the leaders or the core team of the cool for their role, and out of the other one.
+func updateCoolTeam(ctx context.Context, r *pgsql.PostgreRepositories, coolId int, communityId string, role string) error {
+	cool, err := r.Cool.GetOneById(ctx, coolId)
+	if err != nil {
+		return err
+	}
+
+	isUser := func(id string) bool { return id == communityId }
+	leaders := slices.DeleteFunc(slices.Clone([]string(cool.LeaderCommunityIds)), isUser)
+	core := slices.DeleteFunc(slices.Clone([]string(cool.CoreCommunityIds)), isUser)
+	if leaders == nil {
+		// leader_community_ids cannot be NULL
+		leaders = []string{}
+	}
+
+	switch role {
+	case models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_LEADER]:
+		leaders = append(leaders, communityId)
+	case models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_CORE]:
+		core = append(core, communityId)
+	}
+
+	if slices.Equal(leaders, cool.LeaderCommunityIds) && slices.Equal(core, cool.CoreCommunityIds) {
+		return nil
+	}
+
+	return r.Cool.UpdateTeamsById(ctx, coolId, leaders, core)
+}
+
+func withoutCoolRoleUserTypes(userTypes []string) []string {
+	return slices.DeleteFunc(slices.Clone(userTypes), func(userType string) bool {
+		for _, roleUserType := range models.MapCoolMemberRoleUserType {
+			if userType == roleUserType {
+				return true
+			}
+		}
+		return false
+	})
+}
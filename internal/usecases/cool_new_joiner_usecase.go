@@ -2,36 +2,63 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-community/internal/common"
 	"go-community/internal/config"
 	"go-community/internal/constants"
 	"go-community/internal/models"
+	"go-community/internal/pkg/generator"
+	"go-community/internal/pkg/hash"
+	"go-community/internal/pkg/logger"
+	"go-community/internal/pkg/notification"
 	"go-community/internal/pkg/validator"
 	"go-community/internal/repositories/pgsql"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type CoolNewJoinerUsecase interface {
 	Create(ctx context.Context, request *models.CreateCoolNewJoinerRequest) (response *models.CreateCoolNewJoinerResponse, err error)
 	GetAll(ctx context.Context) (response []models.GetCoolNewJoinerResponse, info *models.CursorInfo, err error)
 	UpdateStatus(ctx context.Context, request *models.UpdateCoolNewJoinerRequest) (response *models.UpdateCoolNewJoinerResponse, err error)
+	Suggest(ctx context.Context, param models.CoolNewJoinerParameter) (response []models.CoolNewJoinerSuggestionResponse, err error)
+	Assign(ctx context.Context, param models.CoolNewJoinerParameter, request models.AssignCoolNewJoinerRequest) (response *models.AssignCoolNewJoinerResponse, err error)
+	GetNotes(ctx context.Context, param models.CoolNewJoinerParameter) (response []models.CoolNewJoinerNoteResponse, err error)
+	CreateNote(ctx context.Context, param models.CoolNewJoinerParameter, request models.CreateCoolNewJoinerNoteRequest) (response *models.CoolNewJoinerNoteResponse, err error)
+	Convert(ctx context.Context, param models.CoolNewJoinerParameter, request models.ConvertCoolNewJoinerRequest) (response *models.ConvertCoolNewJoinerResponse, err error)
+	RemindOverdue(ctx context.Context) (err error)
 }
 
 type coolNewJoinerUsecase struct {
 	r     pgsql.PostgreRepositories
 	cfg   *config.Configuration
 	cfgDb configDBUsecase
+	n     *notification.Notifier
+	s     []byte
+	p     *permissionUsecase
 }
 
-func NewCoolNewJoinerUsecase(r pgsql.PostgreRepositories, cfg *config.Configuration, cfgDb configDBUsecase) *coolNewJoinerUsecase {
+func NewCoolNewJoinerUsecase(r pgsql.PostgreRepositories, cfg *config.Configuration, cfgDb configDBUsecase, n *notification.Notifier, s []byte, p *permissionUsecase) *coolNewJoinerUsecase {
 	return &coolNewJoinerUsecase{
 		r:     r,
 		cfg:   cfg,
 		cfgDb: cfgDb,
+		n:     n,
+		s:     s,
+		p:     p,
 	}
 }
 
+// maxCoolNewJoinerSuggestions is how many cools are suggested for a new joiner at most
+const maxCoolNewJoinerSuggestions = 5
+
 func (cnju *coolNewJoinerUsecase) Create(ctx context.Context, request *models.CreateCoolNewJoinerRequest) (response *models.CreateCoolNewJoinerResponse, err error) {
 	defer func() {
 		LogService(ctx, err)
@@ -104,6 +131,11 @@ func (cnju *coolNewJoinerUsecase) GetAll(ctx context.Context, param models.GetAl
 		LogService(ctx, err)
 	}()
 
+	param.CampusScope, err = scopeCampusFilter(ctx, param.CampusCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if param.MaritalStatus != "" {
 		maritalStatus, found := constants.MaritalStatus.LookupValue(common.StringTrimSpaceAndLower(param.MaritalStatus))
 		if !found {
@@ -146,6 +178,11 @@ func (cnju *coolNewJoinerUsecase) GetAll(ctx context.Context, param models.GetAl
 		param.PhoneNumber = *phoneNumber
 	}
 
+	overdueBefore := common.Now().Add(-pendingSLA(cnju.cfg))
+	if param.IsOverdue {
+		param.OverdueBefore = &overdueBefore
+	}
+
 	output, pagination, err := cnju.r.CoolNewJoiner.GetAll(ctx, param)
 	if err != nil {
 		return nil, nil, err
@@ -182,6 +219,15 @@ func (cnju *coolNewJoinerUsecase) GetAll(ctx context.Context, param models.GetAl
 			Location:            item.Location,
 			UpdatedBy:           item.UpdatedBy,
 			Status:              item.Status,
+			CoolId:              item.CoolId,
+			CoolName:            item.CoolName,
+			FollowUpCommunityId: item.FollowUpCommunityId,
+			FollowUpName:        item.FollowUpName,
+			AssignedAt:          item.AssignedAt,
+			FollowedUpAt:        item.FollowedUpAt,
+			CommunityId:         item.CommunityId,
+			ConvertedAt:         item.ConvertedAt,
+			IsOverdue:           item.Status == constants.CoolJoinerStatusPending && item.CreatedAt.Before(overdueBefore),
 			CreatedAt:           item.CreatedAt,
 			UpdatedAt:           item.UpdatedAt,
 			DeletedAtString:     deletedAt,
@@ -202,15 +248,11 @@ func (cnju *coolNewJoinerUsecase) UpdateStatus(ctx context.Context, request *mod
 		LogService(ctx, err)
 	}()
 
-	newJoiner, err := cnju.r.CoolNewJoiner.GetById(ctx, request.Id)
+	newJoiner, err := cnju.getNewJoiner(ctx, request.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	if newJoiner.ID == 0 {
-		return nil, models.ErrorDataNotFound
-	}
-
 	if newJoiner.Status == request.Status {
 		return nil, models.ErrorInvalidInput
 	}
@@ -220,20 +262,50 @@ func (cnju *coolNewJoinerUsecase) UpdateStatus(ctx context.Context, request *mod
 		return nil, err
 	}
 
-	if err := cnju.r.CoolNewJoiner.Update(ctx, &models.CoolNewJoiner{
-		ID:                  newJoiner.ID,
-		Name:                newJoiner.Name,
-		MaritalStatus:       newJoiner.MaritalStatus,
-		Gender:              newJoiner.Gender,
-		YearOfBirth:         newJoiner.YearOfBirth,
-		PhoneNumber:         newJoiner.PhoneNumber,
-		Address:             newJoiner.Address,
-		CommunityOfInterest: newJoiner.CommunityOfInterest,
-		CampusCode:          newJoiner.CampusCode,
-		Location:            newJoiner.Location,
-		UpdatedBy:           &updater.Name,
-		Status:              request.Status,
-	}); err != nil {
+	// The first time the new joiner moves on from pending is when they were followed up
+	followedUpAt := newJoiner.FollowedUpAt
+	if followedUpAt == nil && request.Status != constants.CoolJoinerStatusPending {
+		now := common.Now()
+		followedUpAt = &now
+	}
+
+	err = cnju.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.CoolNewJoiner.Update(ctx, &models.CoolNewJoiner{
+			ID:                  newJoiner.ID,
+			Name:                newJoiner.Name,
+			MaritalStatus:       newJoiner.MaritalStatus,
+			Gender:              newJoiner.Gender,
+			YearOfBirth:         newJoiner.YearOfBirth,
+			PhoneNumber:         newJoiner.PhoneNumber,
+			Address:             newJoiner.Address,
+			CommunityOfInterest: newJoiner.CommunityOfInterest,
+			CampusCode:          newJoiner.CampusCode,
+			Location:            newJoiner.Location,
+			UpdatedBy:           &updater.Name,
+			Status:              request.Status,
+			FollowedUpAt:        followedUpAt,
+		}); err != nil {
+			return err
+		}
+
+		if request.Note != "" {
+			if err := r.CoolNewJoinerNote.Create(ctx, &models.CoolNewJoinerNote{
+				NewJoinerId: newJoiner.ID,
+				Note:        strings.TrimSpace(request.Note),
+				Status:      request.Status,
+				CreatedBy:   request.UpdatedBy,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return enqueueNotification(ctx, r, cnju.n, notification.EventCoolJoinerStatusChanged, newJoiner.PhoneNumber, notification.CoolJoinerData{
+			Name:     newJoiner.Name,
+			Status:   request.Status,
+			Location: newJoiner.Location,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -269,3 +341,550 @@ func (cnju *coolNewJoinerUsecase) UpdateStatus(ctx context.Context, request *mod
 		DeletedAt:           deletedAt,
 	}, nil
 }
+
+// Suggest ranks the active cools of the campus of the new joiner by how well they fit them, and suggests the least
+// busy leader of each to follow them up. Cools for the other gender are left out.
+func (cnju *coolNewJoinerUsecase) Suggest(ctx context.Context, param models.CoolNewJoinerParameter) (response []models.CoolNewJoinerSuggestionResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	newJoiner, err := cnju.getNewJoiner(ctx, param.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	cools, err := cnju.r.Cool.GetAllByCampusCode(ctx, newJoiner.CampusCode, constants.MapStatus[constants.STATUS_ACTIVE])
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := cnju.r.CoolCategory.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The categories a new joiner of their age belongs to, by community of interest
+	age := common.Now().Year() - newJoiner.YearOfBirth
+	ageCategories := make(map[string]bool)
+	for _, category := range categories {
+		if category.Status != constants.MapStatus[constants.STATUS_ACTIVE] || age < category.AgeStart || age > category.AgeEnd {
+			continue
+		}
+
+		if communityOfInterest, found := constants.CommunityOfInterest.LookupValue(category.Name); found {
+			ageCategories[*communityOfInterest] = true
+		}
+		ageCategories[strings.ToUpper(category.Name)] = true
+	}
+
+	gender := strings.ToLower(newJoiner.Gender)
+	location := strings.ToLower(strings.TrimSpace(newJoiner.Location))
+	var leaderIds []string
+	coolLeaders := make(map[int][]string, len(cools))
+	for _, cool := range cools {
+		coolGender := strings.ToLower(cool.Gender)
+		if (coolGender == "male" || coolGender == "female") && coolGender != gender {
+			continue
+		}
+
+		var score int
+		var reasons []string
+		if strings.EqualFold(cool.Category, newJoiner.CommunityOfInterest) {
+			score += 3
+			reasons = append(reasons, "communityOfInterest")
+		}
+
+		if ageCategories[strings.ToUpper(cool.Category)] {
+			score += 2
+			reasons = append(reasons, "age")
+		}
+
+		coolLocation := strings.ToLower(strings.TrimSpace(cool.LocationName))
+		if location != "" && coolLocation != "" && (strings.Contains(coolLocation, location) || strings.Contains(location, coolLocation)) {
+			score += 2
+			reasons = append(reasons, "location")
+		}
+
+		if coolGender == gender {
+			score += 1
+			reasons = append(reasons, "gender")
+		}
+
+		leaderIds = append(leaderIds, cool.LeaderCommunityIds...)
+		coolLeaders[cool.ID] = cool.LeaderCommunityIds
+		response = append(response, models.CoolNewJoinerSuggestionResponse{
+			Type:         models.TYPE_COOL_NEW_JOINER_SUGGESTION,
+			CoolId:       cool.ID,
+			CoolName:     cool.Name,
+			CampusCode:   cool.CampusCode,
+			Category:     cool.Category,
+			Gender:       cool.Gender,
+			LocationName: cool.LocationName,
+			Score:        score,
+			Reasons:      reasons,
+		})
+	}
+
+	loads, err := cnju.followUpLoads(ctx, leaderIds)
+	if err != nil {
+		return nil, err
+	}
+
+	leaders, err := cnju.r.User.GetUserNamesByMultipleCommunityId(ctx, leaderIds)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(leaders))
+	for _, leader := range leaders {
+		names[leader.CommunityId] = leader.Name
+	}
+
+	coolLoads := make(map[int]int, len(response))
+	for i, suggestion := range response {
+		followUp, ok := leastBusyFollowUp(coolLeaders[suggestion.CoolId], loads)
+		if !ok {
+			continue
+		}
+
+		coolLoads[suggestion.CoolId] = loads[followUp]
+		response[i].FollowUp = &models.CoolLeaderAndCoreResponse{
+			Type:        models.TYPE_USER,
+			CommunityId: followUp,
+			Name:        names[followUp],
+		}
+	}
+
+	// Best fit first, then the cool whose follow up has the fewest new joiners on hand
+	sort.SliceStable(response, func(i, j int) bool {
+		if response[i].Score != response[j].Score {
+			return response[i].Score > response[j].Score
+		}
+		if coolLoads[response[i].CoolId] != coolLoads[response[j].CoolId] {
+			return coolLoads[response[i].CoolId] < coolLoads[response[j].CoolId]
+		}
+		return response[i].CoolId < response[j].CoolId
+	})
+
+	if len(response) > maxCoolNewJoinerSuggestions {
+		response = response[:maxCoolNewJoinerSuggestions]
+	}
+
+	return response, nil
+}
+
+// Assign gives the new joiner to a cool of their campus and to someone of its team to follow them up, who is notified.
+func (cnju *coolNewJoinerUsecase) Assign(ctx context.Context, param models.CoolNewJoinerParameter, request models.AssignCoolNewJoinerRequest) (response *models.AssignCoolNewJoinerResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	newJoiner, err := cnju.getNewJoiner(ctx, param.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if newJoiner.ConvertedAt != nil {
+		return nil, models.ErrorNewJoinerConverted
+	}
+
+	cool, err := cnju.r.Cool.GetOneById(ctx, request.CoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cool.ID == 0 || cool.DeletedAt.Valid || cool.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+		return nil, models.ErrorDataNotFound
+	}
+
+	if !inCampusScope(ctx, cool.CampusCode) {
+		return nil, models.ErrorOutOfCampusScope
+	}
+
+	if !strings.EqualFold(cool.CampusCode, newJoiner.CampusCode) {
+		return nil, models.ErrorCoolOutsideCampus
+	}
+
+	followUpId := request.FollowUpCommunityId
+	if followUpId == "" {
+		loads, err := cnju.followUpLoads(ctx, cool.LeaderCommunityIds)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if followUpId, ok = leastBusyFollowUp(cool.LeaderCommunityIds, loads); !ok {
+			return nil, models.ErrorNotCoolFollowUp
+		}
+	} else if !slices.Contains(cool.LeaderCommunityIds, followUpId) && !slices.Contains(cool.CoreCommunityIds, followUpId) && !slices.Contains(cool.FacilitatorCommunityIds, followUpId) {
+		return nil, models.ErrorNotCoolFollowUp
+	}
+
+	followUp, err := cnju.r.User.GetOneByCommunityId(ctx, followUpId)
+	if err != nil {
+		return nil, err
+	}
+
+	if followUp.ID == 0 {
+		return nil, models.ErrorDataNotFound
+	}
+
+	assignedAt := common.Now()
+	err = cnju.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.CoolNewJoiner.Assign(ctx, newJoiner.ID, cool.ID, followUpId, assignedAt); err != nil {
+			return err
+		}
+
+		before := map[string]interface{}{"coolId": newJoiner.CoolId, "followUpCommunityId": newJoiner.FollowUpCommunityId}
+		after := map[string]interface{}{"coolId": cool.ID, "followUpCommunityId": followUpId}
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_COOL_NEW_JOINER_ASSIGN, models.TYPE_COOL_NEW_JOINER, strconv.Itoa(newJoiner.ID), auditSnapshot(before), auditSnapshot(after)); err != nil {
+			return err
+		}
+
+		return enqueueNotification(ctx, r, cnju.n, notification.EventCoolJoinerAssigned, followUpRecipient(followUp), notification.CoolJoinerFollowUpData{
+			Name:              followUp.Name,
+			JoinerName:        newJoiner.Name,
+			JoinerPhoneNumber: newJoiner.PhoneNumber,
+			Location:          newJoiner.Location,
+			CoolName:          cool.Name,
+			Since:             *newJoiner.CreatedAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AssignCoolNewJoinerResponse{
+		Type:                models.TYPE_COOL_NEW_JOINER,
+		ID:                  newJoiner.ID,
+		Name:                newJoiner.Name,
+		CoolId:              cool.ID,
+		CoolName:            cool.Name,
+		FollowUpCommunityId: followUpId,
+		FollowUpName:        followUp.Name,
+		AssignedAt:          assignedAt,
+		Status:              newJoiner.Status,
+	}, nil
+}
+
+func (cnju *coolNewJoinerUsecase) GetNotes(ctx context.Context, param models.CoolNewJoinerParameter) (response []models.CoolNewJoinerNoteResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if _, err = cnju.getNewJoiner(ctx, param.ID); err != nil {
+		return nil, err
+	}
+
+	notes, err := cnju.r.CoolNewJoinerNote.GetManyByNewJoinerId(ctx, param.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CoolNewJoinerNoteResponse, len(notes))
+	for i, note := range notes {
+		response[i] = note.ToResponse()
+	}
+
+	return response, nil
+}
+
+// CreateNote adds to the follow up history of the new joiner without changing their status.
+func (cnju *coolNewJoinerUsecase) CreateNote(ctx context.Context, param models.CoolNewJoinerParameter, request models.CreateCoolNewJoinerNoteRequest) (response *models.CoolNewJoinerNoteResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	newJoiner, err := cnju.getNewJoiner(ctx, param.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := models.CoolNewJoinerNote{
+		NewJoinerId: newJoiner.ID,
+		Note:        strings.TrimSpace(request.Note),
+		Status:      newJoiner.Status,
+		CreatedBy:   callerCommunityId(ctx),
+	}
+
+	if err = cnju.r.CoolNewJoinerNote.Create(ctx, &note); err != nil {
+		return nil, err
+	}
+
+	var createdByName string
+	creator, err := cnju.r.User.GetUserNameByCommunityId(ctx, note.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if creator != nil {
+		createdByName = creator.Name
+	}
+
+	output := models.CoolNewJoinerNoteDBOutput{
+		ID:            note.ID,
+		NewJoinerId:   note.NewJoinerId,
+		Note:          note.Note,
+		Status:        note.Status,
+		CreatedBy:     note.CreatedBy,
+		CreatedByName: createdByName,
+		CreatedAt:     *note.CreatedAt,
+	}
+	res := output.ToResponse()
+
+	return &res, nil
+}
+
+// Convert makes a user of the new joiner, or takes the user with their phone number if there is one, and puts them in
+// the cool they were assigned to. A new user sets their password through forgot password. They can only be given user
+// types whose permissions the caller holds, and an email of someone else is refused.
+func (cnju *coolNewJoinerUsecase) Convert(ctx context.Context, param models.CoolNewJoinerParameter, request models.ConvertCoolNewJoinerRequest) (response *models.ConvertCoolNewJoinerResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	newJoiner, err := cnju.getNewJoiner(ctx, param.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if newJoiner.ConvertedAt != nil {
+		return nil, models.ErrorNewJoinerConverted
+	}
+
+	if newJoiner.CoolId == nil {
+		return nil, models.ErrorNewJoinerNotAssigned
+	}
+
+	userTypes, err := cnju.r.UserType.GetByArray(ctx, request.UserTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userTypes) != len(request.UserTypes) {
+		return nil, models.ErrorDataNotFound
+	}
+
+	if err := cnju.p.AuthorizeGrants(ctx, nil, request.UserTypes); err != nil {
+		return nil, err
+	}
+
+	email := common.StringTrimSpaceAndLower(request.Email)
+	now := common.Now()
+	response = &models.ConvertCoolNewJoinerResponse{
+		Type:        models.TYPE_USER,
+		NewJoinerId: newJoiner.ID,
+		CoolId:      *newJoiner.CoolId,
+	}
+
+	err = cnju.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		user, err := r.User.GetOneByEmailPhoneNumber(ctx, email, newJoiner.PhoneNumber)
+		if err != nil {
+			return err
+		}
+
+		if user.ID != 0 && user.PhoneNumber != newJoiner.PhoneNumber {
+			return models.ErrorAlreadyExist
+		}
+
+		if user.ID == 0 {
+			token, err := generator.SecretToken()
+			if err != nil {
+				return err
+			}
+
+			password, err := hash.Generate(append([]byte(token), cnju.s...))
+			if err != nil {
+				return err
+			}
+
+			user = models.User{
+				CommunityID:   generator.LuhnAccountNumber(),
+				Name:          newJoiner.Name,
+				PhoneNumber:   newJoiner.PhoneNumber,
+				Email:         email,
+				Password:      password,
+				UserTypes:     request.UserTypes,
+				Status:        models.UserStatusActive,
+				Gender:        strings.ToLower(newJoiner.Gender),
+				Address:       newJoiner.Address,
+				CampusCode:    newJoiner.CampusCode,
+				MaritalStatus: newJoiner.MaritalStatus,
+			}
+
+			if err := r.User.Create(ctx, &user); err != nil {
+				return err
+			}
+		} else {
+			response.IsExistingUser = true
+		}
+
+		current, err := r.CoolMember.GetCurrentByCommunityId(ctx, user.CommunityID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case current == nil:
+			if _, _, err := joinCool(ctx, r, user.CommunityID, *newJoiner.CoolId, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_MEMBER], now); err != nil {
+				return err
+			}
+		case current.CoolId != *newJoiner.CoolId:
+			return models.ErrorAlreadyCoolMember
+		}
+
+		if err := r.CoolNewJoiner.Convert(ctx, newJoiner.ID, user.CommunityID, constants.CoolJoinerStatusCompleted, now); err != nil {
+			return err
+		}
+
+		response.CommunityId = user.CommunityID
+		response.Name = user.Name
+		response.PhoneNumber = user.PhoneNumber
+		response.Email = user.Email
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_NEW_JOINER_CONVERT, models.TYPE_COOL_NEW_JOINER, strconv.Itoa(newJoiner.ID), nil, map[string]interface{}{
+			"communityId":    user.CommunityID,
+			"coolId":         *newJoiner.CoolId,
+			"isExistingUser": response.IsExistingUser,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RemindOverdue reminds the follow up of every new joiner that is still pending past the SLA, once per assignment.
+func (cnju *coolNewJoinerUsecase) RemindOverdue(ctx context.Context) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	now := common.Now()
+	newJoiners, err := cnju.r.CoolNewJoiner.GetOverdue(ctx, now.Add(-pendingSLA(cnju.cfg)))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	reminded := 0
+	for _, newJoiner := range newJoiners {
+		var claimed int64
+		err := cnju.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) (err error) {
+			// Every instance of the app runs the job, the one that claims the new joiner first is the one reminding
+			claimed, err = r.CoolNewJoiner.ClaimSlaReminder(ctx, newJoiner.ID, now)
+			if err != nil || claimed == 0 {
+				return err
+			}
+
+			followUp, err := r.User.GetOneByCommunityId(ctx, *newJoiner.FollowUpCommunityId)
+			if err != nil {
+				return err
+			}
+
+			var coolName string
+			if newJoiner.CoolId != nil {
+				cool, err := r.Cool.GetOneById(ctx, *newJoiner.CoolId)
+				if err != nil {
+					return err
+				}
+				coolName = cool.Name
+			}
+
+			return enqueueNotification(ctx, r, cnju.n, notification.EventCoolJoinerOverdue, followUpRecipient(followUp), notification.CoolJoinerFollowUpData{
+				Name:              followUp.Name,
+				JoinerName:        newJoiner.Name,
+				JoinerPhoneNumber: newJoiner.PhoneNumber,
+				Location:          newJoiner.Location,
+				CoolName:          coolName,
+				Since:             *newJoiner.CreatedAt,
+			})
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("new joiner %d: %w", newJoiner.ID, err))
+		} else if claimed > 0 {
+			reminded++
+		}
+	}
+
+	if reminded > 0 {
+		logger.Logger.Info("[COOL_NEW_JOINER] reminded overdue follow ups", zap.Int("total", reminded))
+	}
+
+	return errors.Join(errs...)
+}
+
+// getNewJoiner loads the new joiner, who has to be on a campus the caller administers.
+func (cnju *coolNewJoinerUsecase) getNewJoiner(ctx context.Context, id int) (*models.CoolNewJoiner, error) {
+	newJoiner, err := cnju.r.CoolNewJoiner.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrorDataNotFound
+		}
+		return nil, err
+	}
+
+	if newJoiner.ID == 0 || newJoiner.DeletedAt.Valid {
+		return nil, models.ErrorDataNotFound
+	}
+
+	if !inCampusScope(ctx, newJoiner.CampusCode) {
+		return nil, models.ErrorOutOfCampusScope
+	}
+
+	return newJoiner, nil
+}
+
+// followUpLoads counts the new joiners each of the users follows up that did not join yet.
+func (cnju *coolNewJoinerUsecase) followUpLoads(ctx context.Context, communityIds []string) (map[string]int, error) {
+	loads := make(map[string]int, len(communityIds))
+	if len(communityIds) == 0 {
+		return loads, nil
+	}
+
+	counts, err := cnju.r.CoolNewJoiner.CountOpenByFollowUp(ctx, communityIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		loads[count.FollowUpCommunityId] = count.Total
+	}
+
+	return loads, nil
+}
+
+// leastBusyFollowUp picks the candidate with the fewest new joiners on hand, the first one listed on a tie.
+func leastBusyFollowUp(candidates []string, loads map[string]int) (string, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	followUp := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if loads[candidate] < loads[followUp] {
+			followUp = candidate
+		}
+	}
+
+	return followUp, true
+}
+
+// followUpRecipient reaches the follow up on their phone, or by email when they have no phone number.
+func followUpRecipient(user models.User) string {
+	if user.PhoneNumber != "" {
+		return user.PhoneNumber
+	}
+
+	return user.Email
+}
+
+func pendingSLA(cfg *config.Configuration) time.Duration {
+	if cfg.NewJoiner.PendingSLA <= 0 {
+		return 48 * time.Hour
+	}
+
+	return cfg.NewJoiner.PendingSLA
+}
@@ -6,27 +6,42 @@ import (
 	"go-community/internal/config"
 	"go-community/internal/constants"
 	"go-community/internal/models"
+	"go-community/internal/pkg/recurrence"
 	"go-community/internal/repositories/pgsql"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type CoolUsecase interface {
 	Create(ctx context.Context, request models.CreateCoolRequest) (response *models.CreateCoolResponse, err error)
 	GetAll(ctx context.Context) (response []models.GetAllCoolOptionsResponse, err error)
 	GetByCommunityId(ctx context.Context, communityId string) (response *models.GetCoolDetailResponse, err error)
+	Update(ctx context.Context, param models.CoolMemberParameter, request models.UpdateCoolRequest) (response *models.GetCoolDetailResponse, err error)
+	UpdateLeadership(ctx context.Context, param models.CoolMemberParameter, request models.UpdateCoolLeadershipRequest) (response *models.GetCoolDetailResponse, err error)
+	Deactivate(ctx context.Context, param models.CoolMemberParameter) (err error)
+	Merge(ctx context.Context, param models.CoolMemberParameter, request models.MergeCoolRequest) (response *models.GetCoolDetailResponse, err error)
+	Split(ctx context.Context, param models.CoolMemberParameter, request models.SplitCoolRequest) (response *models.GetCoolDetailResponse, err error)
+	Search(ctx context.Context, param models.SearchCoolCursorParam) (response []models.SearchCoolResponse, info *models.CursorInfo, err error)
 }
 
+// defaultCoolMeetingDurationMinutes is how long a meeting of a cool lasts when its rule does not say
+const defaultCoolMeetingDurationMinutes = 120
+
 type coolUsecase struct {
-	r    pgsql.PostgreRepositories
-	cfg  config.Configuration
-	flag FeatureFlagUsecase
+	r     pgsql.PostgreRepositories
+	cfg   config.Configuration
+	flag  FeatureFlagUsecase
+	cfgDb configDBUsecase
 }
 
-func NewCoolUsecase(r pgsql.PostgreRepositories, cfg config.Configuration, flag FeatureFlagUsecase) *coolUsecase {
+func NewCoolUsecase(r pgsql.PostgreRepositories, cfg config.Configuration, flag FeatureFlagUsecase, cfgDb configDBUsecase) *coolUsecase {
 	return &coolUsecase{
-		r:    r,
-		cfg:  cfg,
-		flag: flag,
+		r:     r,
+		cfg:   cfg,
+		flag:  flag,
+		cfgDb: cfgDb,
 	}
 }
 
@@ -74,6 +89,16 @@ func (clu *coolUsecase) Create(ctx context.Context, request models.CreateCoolReq
 		return nil, models.ErrorDataNotFound
 	}
 
+	meetingStartAt, err := validateMeetingRule(request.MeetingRule, request.MeetingStartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	meetingDuration := request.MeetingDurationMinutes
+	if meetingDuration == 0 {
+		meetingDuration = defaultCoolMeetingDurationMinutes
+	}
+
 	cool := models.Cool{
 		Name:                    strings.TrimSpace(request.Name),
 		Description:             *request.Description,
@@ -87,42 +112,34 @@ func (clu *coolUsecase) Create(ctx context.Context, request models.CreateCoolReq
 		LocationType:            request.LocationType,
 		LocationName:            *request.LocationName,
 		Status:                  constants.MapStatus[constants.STATUS_ACTIVE],
-	}
-
-	if err := clu.r.Cool.Create(ctx, &cool); err != nil {
-		return nil, err
+		MeetingRule:             request.MeetingRule,
+		MeetingStartAt:          meetingStartAt,
+		MeetingDurationMinutes:  meetingDuration,
+		Capacity:                request.Capacity,
 	}
 
 	// TODO: need to add case for facilitator to be set into cool of facilitators
-	for _, leaderCommunityId := range request.LeaderCommunityIds {
-		// Get User Type here
-		userRbacs, err := clu.r.User.GetRBAC(ctx, leaderCommunityId)
-		if err != nil {
-			return nil, err
+	err = clu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.Cool.Create(ctx, &cool); err != nil {
+			return err
 		}
 
-		existingUserTypes := []string(userRbacs.UserTypes) // convert pq.StringArray to []string
-		userTypes := append(existingUserTypes, "cool-leader")
-
-		if err := clu.r.User.UpdateCoolTeamsByCommunityId(ctx, leaderCommunityId, cool.ID, userTypes); err != nil {
-			return nil, err
+		for _, leaderCommunityId := range request.LeaderCommunityIds {
+			if _, _, err := joinCool(ctx, r, leaderCommunityId, cool.ID, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_LEADER], common.Now()); err != nil {
+				return err
+			}
 		}
-	}
 
-	for _, coreCommunityId := range request.CoreCommunityIds {
-		// Get User Type here
-		userRbacs, err := clu.r.User.GetRBAC(ctx, coreCommunityId)
-		if err != nil {
-			return nil, err
+		for _, coreCommunityId := range request.CoreCommunityIds {
+			if _, _, err := joinCool(ctx, r, coreCommunityId, cool.ID, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_CORE], common.Now()); err != nil {
+				return err
+			}
 		}
 
-		existingUserTypes := []string(userRbacs.UserTypes)  // convert pq.StringArray to []string
-		userTypes := append(existingUserTypes, "cool-core") // add core team user type to the user
-
-		// update user's user types and cool id here
-		if err := clu.r.User.UpdateCoolTeamsByCommunityId(ctx, coreCommunityId, cool.ID, userTypes); err != nil {
-			return nil, err
-		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	facilitators, err := clu.r.User.GetUserNamesByMultipleCommunityId(ctx, request.FacilitatorCommunityIds)
@@ -182,6 +199,7 @@ func (clu *coolUsecase) Create(ctx context.Context, request models.CreateCoolReq
 		LocationType: request.LocationType,
 		LocationName: *request.LocationName,
 		Status:       constants.MapStatus[constants.STATUS_ACTIVE],
+		Meeting:      cool.MeetingSchedule(),
 	}
 
 	return &res, nil
@@ -310,8 +328,22 @@ func (clu *coolUsecase) GetByCommunityId(ctx context.Context, communityId string
 		})
 	}
 
+	var members []models.CoolMemberResponse
+	if slices.Contains(cool.LeaderCommunityIds, communityId) || slices.Contains(cool.FacilitatorCommunityIds, communityId) {
+		roster, err := clu.r.CoolMember.GetCurrentByCoolId(ctx, cool.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		members = make([]models.CoolMemberResponse, len(roster))
+		for i, member := range roster {
+			members[i] = member.ToResponse()
+		}
+	}
+
 	return &models.GetCoolDetailResponse{
 		Type:         models.TYPE_COOL,
+		ID:           cool.ID,
 		Name:         cool.Name,
 		Description:  cool.Description,
 		CampusCode:   cool.CampusCode,
@@ -325,5 +357,647 @@ func (clu *coolUsecase) GetByCommunityId(ctx context.Context, communityId string
 		LocationType: cool.LocationType,
 		LocationName: cool.LocationName,
 		Status:       cool.Status,
+		Meeting:      cool.MeetingSchedule(),
+		Capacity:     cool.Capacity,
+		Members:      members,
+	}, nil
+}
+
+// Update replaces the details of the cool. When the meeting rule changes, the upcoming meetings generated from the old
+// rule are replaced by the ones of the new rule.
+func (clu *coolUsecase) Update(ctx context.Context, param models.CoolMemberParameter, request models.UpdateCoolRequest) (response *models.GetCoolDetailResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cool, err := clu.getCool(ctx, param.CoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	category, found := constants.CommunityOfInterest.LookupValue(common.StringTrimSpaceAndLower(request.Category))
+	if !found {
+		return nil, models.ErrorDataNotFound
+	}
+
+	meetingStartAt, err := validateMeetingRule(request.MeetingRule, request.MeetingStartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	meetingDuration := request.MeetingDurationMinutes
+	if meetingDuration == 0 {
+		meetingDuration = defaultCoolMeetingDurationMinutes
+	}
+
+	updated := cool
+	updated.Name = strings.TrimSpace(request.Name)
+	updated.Category = *category
+	updated.LocationType = request.LocationType
+	updated.MeetingRule = request.MeetingRule
+	updated.MeetingStartAt = meetingStartAt
+	updated.MeetingDurationMinutes = meetingDuration
+	if request.Description != nil {
+		updated.Description = *request.Description
+	}
+	if request.Gender != nil {
+		updated.Gender = *request.Gender
+	}
+	if request.Recurrence != nil {
+		updated.Recurrence = *request.Recurrence
+	}
+	if request.LocationName != nil {
+		updated.LocationName = *request.LocationName
+	}
+	if request.Capacity != nil {
+		updated.Capacity = request.Capacity
+		if *request.Capacity == 0 {
+			updated.Capacity = nil
+		}
+	}
+
+	before, after := cool.MeetingSchedule(), updated.MeetingSchedule()
+	scheduleChanged := (before == nil) != (after == nil) ||
+		(before != nil && (before.Rule != after.Rule || !before.StartAt.Equal(after.StartAt) || before.DurationMinutes != after.DurationMinutes))
+
+	err = clu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.Cool.Update(ctx, &updated); err != nil {
+			return err
+		}
+
+		if scheduleChanged && cool.Status == constants.MapStatus[constants.STATUS_ACTIVE] {
+			now := common.Now()
+			if err := r.CoolMeeting.DeleteUpcomingRecurringByCoolId(ctx, cool.ID, now); err != nil {
+				return err
+			}
+
+			if _, err := generateCoolMeetings(ctx, r, updated, now, now.Add(recurrenceHorizon(&clu.cfg))); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_UPDATE, models.TYPE_COOL, strconv.Itoa(cool.ID), auditSnapshot(cool), auditSnapshot(updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clu.detailResponse(ctx, cool.ID)
+}
+
+// UpdateLeadership replaces the facilitators, leaders and core team of the cool. Leaders and core team members are
+// moved into the cool with their role, while the ones left out stay on as members and lose the user type of their role.
+func (clu *coolUsecase) UpdateLeadership(ctx context.Context, param models.CoolMemberParameter, request models.UpdateCoolLeadershipRequest) (response *models.GetCoolDetailResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cool, err := clu.getCool(ctx, param.CoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cool.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+		return nil, models.ErrorCoolInactive
+	}
+
+	facilitators := common.UniqueArray(request.FacilitatorCommunityIds)
+	if facilitators == nil {
+		// facilitator_community_ids cannot be NULL
+		facilitators = []string{}
+	}
+	leaders := common.UniqueArray(request.LeaderCommunityIds)
+	core := common.UniqueArray(request.CoreCommunityIds)
+	if common.CheckOneDataInList(leaders, core) {
+		return nil, models.ErrorInvalidInput
+	}
+
+	if err = clu.checkUsers(ctx, facilitators, leaders, core); err != nil {
+		return nil, err
+	}
+
+	err = clu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		now := common.Now()
+		for _, communityId := range append(slices.Clone([]string(cool.LeaderCommunityIds)), cool.CoreCommunityIds...) {
+			if slices.Contains(leaders, communityId) || slices.Contains(core, communityId) {
+				continue
+			}
+
+			if err := leaveCoolTeam(ctx, r, cool.ID, communityId, now); err != nil {
+				return err
+			}
+		}
+
+		for _, communityId := range leaders {
+			if err := setCoolRole(ctx, r, cool.ID, communityId, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_LEADER], now); err != nil {
+				return err
+			}
+		}
+
+		for _, communityId := range core {
+			if err := setCoolRole(ctx, r, cool.ID, communityId, models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_CORE], now); err != nil {
+				return err
+			}
+		}
+
+		if err := r.Cool.UpdateFacilitatorsById(ctx, cool.ID, facilitators); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_LEADERSHIP_UPDATE, models.TYPE_COOL, strconv.Itoa(cool.ID), map[string]interface{}{
+			"facilitatorCommunityIds": []string(cool.FacilitatorCommunityIds),
+			"leaderCommunityIds":      []string(cool.LeaderCommunityIds),
+			"coreCommunityIds":        []string(cool.CoreCommunityIds),
+		}, map[string]interface{}{
+			"facilitatorCommunityIds": facilitators,
+			"leaderCommunityIds":      leaders,
+			"coreCommunityIds":        core,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clu.detailResponse(ctx, cool.ID)
+}
+
+// Deactivate closes the cool: everyone in it leaves, with the leaders and core team losing the user type of their role,
+// and its upcoming meetings are cancelled.
+func (clu *coolUsecase) Deactivate(ctx context.Context, param models.CoolMemberParameter) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cool, err := clu.getCool(ctx, param.CoolId)
+	if err != nil {
+		return err
+	}
+
+	if cool.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+		return models.ErrorCoolInactive
+	}
+
+	return clu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		now := common.Now()
+		members, err := r.CoolMember.GetCurrentByCoolId(ctx, cool.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if _, err := leaveCool(ctx, r, member.CommunityId, now); err != nil {
+				return err
+			}
+		}
+
+		if err := closeCool(ctx, r, cool.ID, now); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_DEACTIVATE, models.TYPE_COOL, strconv.Itoa(cool.ID), map[string]interface{}{
+			"status": cool.Status,
+		}, map[string]interface{}{
+			"status":  constants.MapStatus[constants.STATUS_INACTIVE],
+			"members": len(members),
+		})
+	})
+}
+
+// Merge moves everyone in the cool into the target cool with the role they had, along with the new joiners assigned to
+// it, and closes the cool.
+func (clu *coolUsecase) Merge(ctx context.Context, param models.CoolMemberParameter, request models.MergeCoolRequest) (response *models.GetCoolDetailResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if param.CoolId == request.TargetCoolId {
+		return nil, models.ErrorInvalidInput
+	}
+
+	cool, err := clu.getCool(ctx, param.CoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := clu.getCool(ctx, request.TargetCoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cool.Status != constants.MapStatus[constants.STATUS_ACTIVE] || target.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+		return nil, models.ErrorCoolInactive
+	}
+
+	err = clu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		now := common.Now()
+		members, err := r.CoolMember.GetCurrentByCoolId(ctx, cool.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if _, _, err := joinCool(ctx, r, member.CommunityId, target.ID, member.Role, now); err != nil {
+				return err
+			}
+		}
+
+		if err := r.CoolNewJoiner.UpdateCoolIdByCoolId(ctx, cool.ID, target.ID); err != nil {
+			return err
+		}
+
+		if err := closeCool(ctx, r, cool.ID, now); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_MERGE, models.TYPE_COOL, strconv.Itoa(cool.ID), map[string]interface{}{
+			"status": cool.Status,
+		}, map[string]interface{}{
+			"status":       constants.MapStatus[constants.STATUS_INACTIVE],
+			"targetCoolId": target.ID,
+			"members":      len(members),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clu.detailResponse(ctx, target.ID)
+}
+
+// Split starts a new cool from the cool and moves the listed members into it, as its leaders, core team or members.
+// The cool has to keep at least one of its leaders.
+func (clu *coolUsecase) Split(ctx context.Context, param models.CoolMemberParameter, request models.SplitCoolRequest) (response *models.GetCoolDetailResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	cool, err := clu.getCool(ctx, param.CoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cool.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+		return nil, models.ErrorCoolInactive
+	}
+
+	leaders := common.UniqueArray(request.LeaderCommunityIds)
+	core := common.UniqueArray(request.CoreCommunityIds)
+	members := common.UniqueArray(request.MemberCommunityIds)
+	if common.CheckOneDataInList(leaders, core) || common.CheckOneDataInList(leaders, members) || common.CheckOneDataInList(core, members) {
+		return nil, models.ErrorInvalidInput
+	}
+
+	current, err := clu.r.CoolMember.GetCurrentByCoolId(ctx, cool.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	moving := slices.Concat(leaders, core, members)
+	for _, communityId := range moving {
+		if !slices.ContainsFunc(current, func(member models.CoolMemberDBOutput) bool { return member.CommunityId == communityId }) {
+			return nil, models.ErrorNotCoolMember
+		}
+	}
+
+	if !slices.ContainsFunc(cool.LeaderCommunityIds, func(communityId string) bool { return !slices.Contains(moving, communityId) }) {
+		return nil, models.ErrorCoolWithoutLeader
+	}
+
+	split := models.Cool{
+		Name:                    strings.TrimSpace(request.Name),
+		Description:             cool.Description,
+		CampusCode:              cool.CampusCode,
+		FacilitatorCommunityIds: cool.FacilitatorCommunityIds,
+		LeaderCommunityIds:      leaders,
+		CoreCommunityIds:        core,
+		Category:                cool.Category,
+		Gender:                  cool.Gender,
+		Recurrence:              cool.Recurrence,
+		LocationType:            cool.LocationType,
+		LocationName:            cool.LocationName,
+		Status:                  constants.MapStatus[constants.STATUS_ACTIVE],
+		MeetingRule:             cool.MeetingRule,
+		MeetingStartAt:          cool.MeetingStartAt,
+		MeetingDurationMinutes:  cool.MeetingDurationMinutes,
+	}
+	if request.Description != nil {
+		split.Description = *request.Description
+	}
+	if request.LocationType != "" {
+		split.LocationType = request.LocationType
+	}
+	if request.LocationName != nil {
+		split.LocationName = *request.LocationName
+	}
+
+	err = clu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.Cool.Create(ctx, &split); err != nil {
+			return err
+		}
+
+		now := common.Now()
+		roles := []struct {
+			role         string
+			communityIds []string
+		}{
+			{models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_LEADER], leaders},
+			{models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_CORE], core},
+			{models.MapCoolMemberRole[models.COOL_MEMBER_ROLE_MEMBER], members},
+		}
+		for _, role := range roles {
+			for _, communityId := range role.communityIds {
+				if _, _, err := joinCool(ctx, r, communityId, split.ID, role.role, now); err != nil {
+					return err
+				}
+			}
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_COOL_SPLIT, models.TYPE_COOL, strconv.Itoa(cool.ID), nil, map[string]interface{}{
+			"splitCoolId": split.ID,
+			"members":     len(moving),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clu.detailResponse(ctx, split.ID)
+}
+
+// Search finds the active cools matching the filters, telling which of them still take new joiners.
+func (clu *coolUsecase) Search(ctx context.Context, param models.SearchCoolCursorParam) (response []models.SearchCoolResponse, info *models.CursorInfo, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if param.CampusCode != "" {
+		if _, campusExist := clu.cfg.Campus[common.StringTrimSpaceAndLower(param.CampusCode)]; !campusExist {
+			return nil, nil, models.ErrorDataNotFound
+		}
+	}
+
+	if param.Category != "" {
+		category, found := constants.CommunityOfInterest.LookupValue(common.StringTrimSpaceAndLower(param.Category))
+		if !found {
+			return nil, nil, models.ErrorDataNotFound
+		}
+		param.Category = *category
+	}
+
+	if param.MeetingDay != "" {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), param.MeetingDay) {
+				param.MeetingByDay = strings.ToUpper(param.MeetingDay[:2])
+				param.MeetingIsoDay = isoWeekday(day)
+			}
+		}
+	}
+
+	if param.Area != "" {
+		areaExist, err := clu.isAreaExist(ctx, param.CampusCode, param.Area)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !areaExist {
+			return nil, nil, models.ErrorDataNotFound
+		}
+	}
+
+	output, pagination, err := clu.r.Cool.Search(ctx, param)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var leaderIds []string
+	for _, item := range output {
+		leaderIds = append(leaderIds, item.LeaderCommunityIds...)
+	}
+
+	leaders, err := clu.r.User.GetUserNamesByMultipleCommunityId(ctx, leaderIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[string]string, len(leaders))
+	for _, leader := range leaders {
+		names[leader.CommunityId] = leader.Name
+	}
+
+	response = make([]models.SearchCoolResponse, len(output))
+	for i, item := range output {
+		var leaderResponses []models.CoolLeaderAndCoreResponse
+		for _, communityId := range item.LeaderCommunityIds {
+			leaderResponses = append(leaderResponses, models.CoolLeaderAndCoreResponse{
+				Type:        models.TYPE_USER,
+				CommunityId: communityId,
+				Name:        names[communityId],
+			})
+		}
+
+		cool := models.Cool{
+			MeetingRule:            item.MeetingRule,
+			MeetingStartAt:         item.MeetingStartAt,
+			MeetingDurationMinutes: item.MeetingDurationMinutes,
+		}
+
+		response[i] = models.SearchCoolResponse{
+			Type:         models.TYPE_COOL,
+			ID:           item.ID,
+			Name:         item.Name,
+			Description:  item.Description,
+			CampusCode:   item.CampusCode,
+			CampusName:   clu.cfg.Campus[strings.ToLower(item.CampusCode)],
+			Category:     item.Category,
+			Gender:       item.Gender,
+			LocationType: item.LocationType,
+			LocationName: item.LocationName,
+			Leaders:      leaderResponses,
+			Meeting:      cool.MeetingSchedule(),
+			MeetingDays:  coolMeetingDays(cool),
+			MemberCount:  item.MemberCount,
+			Capacity:     item.Capacity,
+			IsOpen:       item.Capacity == nil || item.MemberCount < *item.Capacity,
+		}
+	}
+
+	info = &models.CursorInfo{
+		PreviousCursor: pagination.Prev,
+		NextCursor:     pagination.Next,
+		TotalData:      pagination.Total,
+	}
+
+	return response, info, nil
+}
+
+// isAreaExist checks the area is one of the locations of the campus, or of any campus when none is given, in the
+// campusLocationValue config.
+func (clu *coolUsecase) isAreaExist(ctx context.Context, campusCode string, area string) (bool, error) {
+	if campusCode == "" {
+		return clu.cfgDb.IsLocationExist(ctx, area)
+	}
+
+	locations, err := clu.cfgDb.GetLocationsByCampusCode(ctx, campusCode)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(locations, func(location models.GetLocationsByCampusCodeResponse) bool {
+		return strings.EqualFold(strings.TrimSpace(location.Name), strings.TrimSpace(area))
+	}), nil
+}
+
+// getCool loads the cool, which has to be on a campus the caller administers.
+func (clu *coolUsecase) getCool(ctx context.Context, id int) (cool models.Cool, err error) {
+	cool, err = clu.r.Cool.GetOneById(ctx, id)
+	if err != nil {
+		return cool, err
+	}
+
+	if cool.ID == 0 || cool.DeletedAt.Valid {
+		return cool, models.ErrorDataNotFound
+	}
+
+	if !inCampusScope(ctx, cool.CampusCode) {
+		return cool, models.ErrorOutOfCampusScope
+	}
+
+	return cool, nil
+}
+
+// checkUsers makes sure every one of the users exists.
+func (clu *coolUsecase) checkUsers(ctx context.Context, communityIds ...[]string) error {
+	for _, ids := range communityIds {
+		if len(ids) == 0 {
+			continue
+		}
+
+		count, err := clu.r.User.CheckMultiple(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		if int(count) != len(ids) {
+			return models.ErrorDataNotFound
+		}
+	}
+
+	return nil
+}
+
+// detailResponse shows the cool along with everyone in it.
+func (clu *coolUsecase) detailResponse(ctx context.Context, id int) (*models.GetCoolDetailResponse, error) {
+	cool, err := clu.r.Cool.GetOneById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	campusName := clu.cfg.Campus[strings.ToLower(cool.CampusCode)]
+
+	teams := make(map[string][]models.CoolLeaderAndCoreResponse, 3)
+	for team, communityIds := range map[string][]string{
+		"facilitators": cool.FacilitatorCommunityIds,
+		"leaders":      cool.LeaderCommunityIds,
+		"core":         cool.CoreCommunityIds,
+	} {
+		users, err := clu.r.User.GetUserNamesByMultipleCommunityId(ctx, communityIds)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range users {
+			teams[team] = append(teams[team], models.CoolLeaderAndCoreResponse{
+				Type:        models.TYPE_USER,
+				CommunityId: v.CommunityId,
+				Name:        v.Name,
+			})
+		}
+	}
+
+	roster, err := clu.r.CoolMember.GetCurrentByCoolId(ctx, cool.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]models.CoolMemberResponse, len(roster))
+	for i, member := range roster {
+		members[i] = member.ToResponse()
+	}
+
+	return &models.GetCoolDetailResponse{
+		Type:         models.TYPE_COOL,
+		ID:           cool.ID,
+		Name:         cool.Name,
+		Description:  cool.Description,
+		CampusCode:   cool.CampusCode,
+		CampusName:   campusName,
+		Facilitators: teams["facilitators"],
+		Leaders:      teams["leaders"],
+		CoreTeam:     teams["core"],
+		Category:     cool.Category,
+		Gender:       cool.Gender,
+		Recurrence:   cool.Recurrence,
+		LocationType: cool.LocationType,
+		LocationName: cool.LocationName,
+		Status:       cool.Status,
+		Meeting:      cool.MeetingSchedule(),
+		Capacity:     cool.Capacity,
+		Members:      members,
 	}, nil
 }
+
+// coolMeetingDays lists the days of the week the cool meets on: the days of its rule, or the day its first meeting was.
+func coolMeetingDays(cool models.Cool) []string {
+	schedule := cool.MeetingSchedule()
+	if schedule == nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(schedule.Rule)
+	if err != nil {
+		return nil
+	}
+
+	var days []time.Weekday
+	switch {
+	case len(rule.ByDay) > 0:
+		for _, byDay := range rule.ByDay {
+			if !slices.Contains(days, byDay.Day) {
+				days = append(days, byDay.Day)
+			}
+		}
+		slices.SortFunc(days, func(a, b time.Weekday) int { return isoWeekday(a) - isoWeekday(b) })
+	case rule.Frequency == recurrence.FrequencyDaily:
+		for day := time.Monday; day <= time.Saturday; day++ {
+			days = append(days, day)
+		}
+		days = append(days, time.Sunday)
+	default:
+		days = append(days, schedule.StartAt.In(common.GetLocation()).Weekday())
+	}
+
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = strings.ToLower(day.String())
+	}
+
+	return names
+}
+
+// isoWeekday numbers the days of the week from Monday as 1 to Sunday as 7.
+func isoWeekday(day time.Weekday) int {
+	if day == time.Sunday {
+		return 7
+	}
+
+	return int(day)
+}
+
+// closeCool sets the cool inactive and cancels its upcoming meetings.
+func closeCool(ctx context.Context, r *pgsql.PostgreRepositories, coolId int, at time.Time) error {
+	if err := r.CoolMeeting.CancelUpcomingByCoolId(ctx, coolId, at); err != nil {
+		return err
+	}
+
+	return r.Cool.UpdateStatusById(ctx, coolId, constants.MapStatus[constants.STATUS_INACTIVE])
+}
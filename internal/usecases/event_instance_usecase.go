@@ -11,10 +11,14 @@ import (
 	"go-community/internal/pkg/generator"
 	"go-community/internal/repositories/pgsql"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type EventInstanceUsecase interface {
 	Create(ctx context.Context, request models.CreateInstanceExistingEventRequest) (response *models.CreateInstanceResponse, err error)
+	Update(ctx context.Context, request models.UpdateInstanceRequest, value *models.TokenValues) (response *models.UpdateInstanceResponse, err error)
+	Cancel(ctx context.Context, param models.CancelInstanceParameter, value *models.TokenValues) (response *models.CancelInstanceResponse, err error)
 }
 
 type eventInstanceUsecase struct {
@@ -83,6 +87,7 @@ func (eiu *eventInstanceUsecase) Create(ctx context.Context, request models.Crea
 	} else {
 		request.IsOnePerAccount = false
 		request.IsOnePerTicket = false
+		request.IsWaitlistEnabled = false
 		request.RegisterFlow = models.MapRegisterFlow[models.REGISTER_FLOW_NONE]
 		request.MaxPerTransaction = 0
 		request.CheckType = "none"
@@ -105,6 +110,7 @@ func (eiu *eventInstanceUsecase) Create(ctx context.Context, request models.Crea
 		MaxPerTransaction: request.MaxPerTransaction,
 		IsOnePerAccount:   request.IsOnePerAccount,
 		IsOnePerTicket:    request.IsOnePerTicket,
+		IsWaitlistEnabled: request.IsWaitlistEnabled,
 		RegisterFlow:      request.RegisterFlow,
 		CheckType:         request.CheckType,
 		TotalSeats:        request.TotalSeats,
@@ -143,6 +149,7 @@ func (eiu *eventInstanceUsecase) Create(ctx context.Context, request models.Crea
 		MaxPerTransaction: request.MaxPerTransaction,
 		IsOnePerAccount:   request.IsOnePerAccount,
 		IsOnePerTicket:    request.IsOnePerTicket,
+		IsWaitlistEnabled: request.IsWaitlistEnabled,
 		RegisterFlow:      request.RegisterFlow,
 		CheckType:         request.CheckType,
 		TotalSeats:        request.TotalSeats,
@@ -151,3 +158,338 @@ func (eiu *eventInstanceUsecase) Create(ctx context.Context, request models.Crea
 
 	return &res, nil
 }
+
+func (eiu *eventInstanceUsecase) Update(ctx context.Context, request models.UpdateInstanceRequest, value *models.TokenValues) (response *models.UpdateInstanceResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	err = eiu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		// Seats are checked against and promoted into, so nobody can book them until the update is done
+		if err := r.EventInstance.LockByCode(ctx, request.Code); err != nil {
+			return err
+		}
+
+		instance, err := r.EventInstance.GetByCode(ctx, request.Code)
+		if err != nil {
+			return err
+		}
+
+		if instance.ID == 0 || instance.DeletedAt.Valid {
+			return models.ErrorDataNotFound
+		}
+
+		if instance.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+			return models.ErrorEventAlreadyCancelled
+		}
+
+		before := auditSnapshot(instance)
+		wasWaitlistEnabled := instance.IsWaitlistEnabled
+		previousRegisterFlow := instance.RegisterFlow
+		if err := mergeInstance(&instance, request); err != nil {
+			return err
+		}
+
+		if instance.InstanceStartAt.After(instance.InstanceEndAt) || instance.RegisterStartAt.After(instance.RegisterEndAt) || instance.AllowVerifyAt.After(instance.DisallowVerifyAt) {
+			return models.ErrorStartDateLater
+		}
+
+		if request.InstanceStartAt != nil || request.InstanceEndAt != nil {
+			event, err := r.Event.GetByCode(ctx, instance.EventCode)
+			if err != nil {
+				return err
+			}
+
+			if !isInstanceWithinEvent(event, instance) {
+				return models.ErrorInstanceOutsideEvent
+			}
+		}
+
+		if instance.RegisterFlow != models.MapRegisterFlow[models.REGISTER_FLOW_NONE] {
+			if instance.MaxPerTransaction == 0 {
+				return models.ErrorMaxPerTrxIsZero
+			}
+
+			if instance.CheckType == "" {
+				return models.ErrorAttendanceTypeWhenRequired
+			}
+		} else {
+			instance.IsOnePerAccount = false
+			instance.IsOnePerTicket = false
+			instance.IsWaitlistEnabled = false
+			instance.MaxPerTransaction = 0
+			instance.CheckType = "none"
+			instance.TotalSeats = 0
+		}
+
+		// Registrations already made follow the flow they were made with, e.g. personal QR ones are verified on the spot
+		if instance.RegisterFlow != previousRegisterFlow && instance.BookedSeats > 0 {
+			return models.ErrorRegisterFlowLocked
+		}
+
+		if instance.TotalSeats != 0 && instance.TotalSeats < instance.BookedSeats {
+			return models.ErrorSeatsBelowBooked
+		}
+
+		if err := r.EventInstance.Update(ctx, &instance); err != nil {
+			return err
+		}
+
+		res := models.UpdateInstanceResponse{
+			CreateInstanceResponse: instance.ToCreateResponse(),
+		}
+
+		// Without a waitlist nobody waiting would ever get a seat, so they are let go instead of left waiting
+		if wasWaitlistEnabled && !instance.IsWaitlistEnabled {
+			waitlisted, err := r.EventRegistrationRecord.GetManyByInstanceCodeAndStatus(ctx, instance.Code, models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED])
+			if err != nil {
+				return err
+			}
+
+			res.Cancelled, err = cancelRegistrations(ctx, r, waitlisted, "waitlist is closed", value.Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		seats := &models.GetSeatsAndNamesByInstanceCodeDBOutput{
+			TotalSeats:        instance.TotalSeats,
+			BookedSeats:       instance.BookedSeats,
+			IsWaitlistEnabled: instance.IsWaitlistEnabled,
+		}
+
+		promoted, err := promoteWaitlisted(ctx, r, instance.Code, seats)
+		if err != nil {
+			return err
+		}
+
+		instance.BookedSeats = seats.BookedSeats
+		after := auditSnapshot(instance)
+		if len(res.Cancelled) > 0 {
+			after["cancelledWaitlisted"] = len(res.Cancelled)
+		}
+
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_EVENT_INSTANCE_UPDATE, models.TYPE_EVENT_INSTANCE, instance.Code, before, after); err != nil {
+			return err
+		}
+
+		res.BookedSeats = seats.BookedSeats
+		res.Promoted = make([]models.CreateOtherEventRegistrationRecordResponse, len(promoted))
+		for i, p := range promoted {
+			res.Promoted[i] = models.CreateOtherEventRegistrationRecordResponse{
+				Type:   models.TYPE_EVENT_REGISTRATION_RECORD,
+				ID:     p.ID,
+				Name:   p.Name,
+				Status: models.MapRegisterStatus[models.REGISTER_STATUS_PENDING],
+			}
+		}
+
+		response = &res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (eiu *eventInstanceUsecase) Cancel(ctx context.Context, param models.CancelInstanceParameter, value *models.TokenValues) (response *models.CancelInstanceResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if param.Reason == "" {
+		param.Reason = "session is cancelled"
+	}
+
+	err = eiu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.EventInstance.LockByCode(ctx, param.Code); err != nil {
+			return err
+		}
+
+		instance, err := r.EventInstance.GetByCode(ctx, param.Code)
+		if err != nil {
+			return err
+		}
+
+		if instance.ID == 0 || instance.DeletedAt.Valid {
+			return models.ErrorDataNotFound
+		}
+
+		if instance.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+			return models.ErrorEventAlreadyCancelled
+		}
+
+		previousStatus := instance.Status
+		response, err = cancelInstance(ctx, r, &instance, param.Reason, value.Id)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_EVENT_INSTANCE_CANCEL, models.TYPE_EVENT_INSTANCE, instance.Code, map[string]interface{}{
+			"status": previousStatus,
+		}, map[string]interface{}{
+			"status":        instance.Status,
+			"reason":        param.Reason,
+			"totalAffected": response.TotalAffected,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// mergeInstance applies the fields sent in a partial update onto the instance.
+func mergeInstance(instance *models.EventInstance, request models.UpdateInstanceRequest) (err error) {
+	if request.Title != nil {
+		instance.Title = *request.Title
+	}
+
+	if request.Description != nil {
+		instance.Description = *request.Description
+	}
+
+	if instance.InstanceStartAt, err = parseOptionalTime(request.InstanceStartAt, instance.InstanceStartAt); err != nil {
+		return err
+	}
+
+	if instance.InstanceEndAt, err = parseOptionalTime(request.InstanceEndAt, instance.InstanceEndAt); err != nil {
+		return err
+	}
+
+	if instance.RegisterStartAt, err = parseOptionalTime(request.RegisterStartAt, instance.RegisterStartAt); err != nil {
+		return err
+	}
+
+	if instance.RegisterEndAt, err = parseOptionalTime(request.RegisterEndAt, instance.RegisterEndAt); err != nil {
+		return err
+	}
+
+	if instance.AllowVerifyAt, err = parseOptionalTime(request.AllowVerifyAt, instance.AllowVerifyAt); err != nil {
+		return err
+	}
+
+	if instance.DisallowVerifyAt, err = parseOptionalTime(request.DisallowVerifyAt, instance.DisallowVerifyAt); err != nil {
+		return err
+	}
+
+	if request.LocationType != nil {
+		instance.LocationType = *request.LocationType
+	}
+
+	if request.LocationName != nil {
+		instance.LocationName = *request.LocationName
+	}
+
+	if request.MaxPerTransaction != nil {
+		instance.MaxPerTransaction = *request.MaxPerTransaction
+	}
+
+	if request.IsOnePerAccount != nil {
+		instance.IsOnePerAccount = *request.IsOnePerAccount
+	}
+
+	if request.IsOnePerTicket != nil {
+		instance.IsOnePerTicket = *request.IsOnePerTicket
+	}
+
+	if request.IsWaitlistEnabled != nil {
+		instance.IsWaitlistEnabled = *request.IsWaitlistEnabled
+	}
+
+	if request.RegisterFlow != nil {
+		instance.RegisterFlow = *request.RegisterFlow
+	}
+
+	if request.CheckType != nil {
+		instance.CheckType = *request.CheckType
+	}
+
+	if request.TotalSeats != nil {
+		instance.TotalSeats = *request.TotalSeats
+	}
+
+	return nil
+}
+
+// cancelInstance deactivates an instance and cancels every registration of it, including the ones already verified.
+// Each of them is reported with the status it had, so the organizer knows who already came. The instance has to be
+// locked by the transaction of r and read after taking the lock, see EventInstanceRepository.LockByCode.
+func cancelInstance(ctx context.Context, r *pgsql.PostgreRepositories, instance *models.EventInstance, reason string, updatedBy string) (response *models.CancelInstanceResponse, err error) {
+	if reason == "" {
+		reason = "session is cancelled"
+	}
+
+	var records []models.EventRegistrationRecord
+	seats := 0
+	for _, status := range []string{
+		models.MapRegisterStatus[models.REGISTER_STATUS_PENDING],
+		models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS],
+		models.MapRegisterStatus[models.REGISTER_STATUS_CHECKED_OUT],
+		models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED],
+	} {
+		found, err := r.EventRegistrationRecord.GetManyByInstanceCodeAndStatus(ctx, instance.Code, status)
+		if err != nil {
+			return nil, err
+		}
+
+		// Every registration held a seat, except a waitlisted one
+		if status != models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED] {
+			seats += len(found)
+		}
+		records = append(records, found...)
+	}
+
+	affected, err := cancelRegistrations(ctx, r, records, reason, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	instance.Status = constants.MapStatus[constants.STATUS_INACTIVE]
+	if err := r.EventInstance.Update(ctx, instance); err != nil {
+		return nil, err
+	}
+
+	if seats > 0 {
+		instance.BookedSeats -= seats
+		if err := r.EventInstance.UpdateBookedSeatsByCode(ctx, instance.Code, &models.GetSeatsAndNamesByInstanceCodeDBOutput{BookedSeats: instance.BookedSeats}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.CancelInstanceResponse{
+		Type:          models.TYPE_EVENT_INSTANCE,
+		InstanceCode:  instance.Code,
+		EventCode:     instance.EventCode,
+		Title:         instance.Title,
+		Status:        instance.Status,
+		TotalAffected: len(affected),
+		Affected:      affected,
+	}, nil
+}
+
+// cancelRegistrations cancels the records in one update and reports each of them with the status it had before.
+func cancelRegistrations(ctx context.Context, r *pgsql.PostgreRepositories, records []models.EventRegistrationRecord, reason string, updatedBy string) (affected []models.AffectedRegistrationResponse, err error) {
+	if len(records) == 0 {
+		return []models.AffectedRegistrationResponse{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(records))
+	affected = make([]models.AffectedRegistrationResponse, len(records))
+	for i, record := range records {
+		previousStatus := record.Status
+		record.Status = models.MapRegisterStatus[models.REGISTER_STATUS_CANCELLED]
+
+		ids[i] = record.ID
+		affected[i] = record.ToAffectedResponse(previousStatus)
+	}
+
+	if err := r.EventRegistrationRecord.UpdateStatusAndReasonByIds(ctx, ids, models.MapRegisterStatus[models.REGISTER_STATUS_CANCELLED], reason, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return affected, nil
+}
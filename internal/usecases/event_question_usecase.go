@@ -2,14 +2,22 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
 	"go-community/internal/common"
+	"go-community/internal/constants"
 	"go-community/internal/models"
 	"go-community/internal/repositories/pgsql"
+	"slices"
 )
 
 type EventQuestionUsecase interface {
 	Create(ctx context.Context, request models.CreateQuestionRequest) (response []models.CreateQuestionResponse, err error)
+	GetAll(ctx context.Context, param models.GetQuestionsParameter) (response []models.CreateQuestionResponse, err error)
+	GetAllInternal(ctx context.Context, param models.GetQuestionsParameter) (response []models.CreateQuestionResponse, err error)
+	Update(ctx context.Context, request models.UpdateQuestionRequest) (response *models.CreateQuestionResponse, err error)
+	Delete(ctx context.Context, param models.DeleteQuestionParameter) (err error)
 }
 
 type eventQuestionUsecase struct {
@@ -37,26 +45,19 @@ func (equ *eventQuestionUsecase) Create(ctx context.Context, request models.Crea
 		return nil, models.ErrorDataNotFound
 	}
 
-	if request.InstanceCode != nil {
-		for _, instanceCode := range request.InstanceCode {
-			if request.EventCode != (instanceCode)[:7] {
-				return nil, models.ErrorMismatchFields
-			}
-		}
-
-		existInstance, err := equ.r.EventInstance.CheckMultiple(ctx, request.InstanceCode)
-		if err != nil {
-			return nil, err
-		}
+	if err = equ.checkInstanceCodes(ctx, request.EventCode, request.InstanceCode); err != nil {
+		return nil, err
+	}
 
-		if int(existInstance) != len(request.InstanceCode) {
-			return nil, models.ErrorDataNotFound
-		}
+	existing, err := equ.getQuestionMap(ctx, request.EventCode)
+	if err != nil {
+		return nil, err
 	}
 
+	var errs *multierror.Error
 	questionDetails := make([]models.EventQuestion, 0)
-	for _, questionDetail := range request.Questions {
-		questionType, description, err := models.CreateQuestionSetup(questionDetail.Type, questionDetail.Description, questionDetail.Options, *questionDetail.Rules)
+	for i, questionDetail := range request.Questions {
+		questionType, description, err := models.CreateQuestionSetup(questionDetail.Type, questionDetail.Description, questionDetail.Options, questionDetail.Rules)
 		if err != nil {
 			return nil, err
 		}
@@ -73,35 +74,267 @@ func (equ *eventQuestionUsecase) Create(ctx context.Context, request models.Crea
 			IsRegistrantRequired:  questionDetail.IsRegistrantRequired,
 			DisplayOrder:          questionDetail.DisplayOrder,
 			IsVisibleToRegistrant: questionDetail.IsVisibleToRegistrant,
+			Rules:                 questionDetail.Rules,
 			Status:                questionDetail.Status,
 		}
 
+		field := fmt.Sprintf("questions[%d].rules", i)
+		errs = multierror.Append(errs, question.Rules.Validate(question.Type, question.Options, field), checkCondition(question, existing, field))
+
 		questionDetails = append(questionDetails, question)
 	}
 
+	if err = errs.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
 	if err = equ.r.EventQuestion.BulkCreate(ctx, &questionDetails); err != nil {
 		return nil, err
 	}
 
 	questionResponse := make([]models.CreateQuestionResponse, len(questionDetails))
 	for i, p := range questionDetails {
-		questionResponse[i] = models.CreateQuestionResponse{
-			Type:                  models.TYPE_EVENT_QUESTION,
-			ID:                    p.ID,
-			EventCode:             p.EventCode,
-			InstanceCode:          p.InstanceCode,
-			Question:              p.Question,
-			Description:           p.Description,
-			QuestionType:          p.Type,
-			IsMainRequired:        p.IsMainRequired,
-			IsRegistrantRequired:  p.IsRegistrantRequired,
-			Options:               p.Options,
-			DisplayOrder:          p.DisplayOrder,
-			IsVisibleToRegistrant: p.IsVisibleToRegistrant,
-			Rules:                 p.Rules,
-			Status:                p.Status,
-		}
+		questionResponse[i] = p.ToResponse()
 	}
 
 	return questionResponse, nil
 }
+
+// GetAll lists the active questions of the registration form. With IsRegistrant only the questions asked to the people
+// registered alongside the main registrant are listed.
+func (equ *eventQuestionUsecase) GetAll(ctx context.Context, param models.GetQuestionsParameter) (response []models.CreateQuestionResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	questions, err := equ.getAll(ctx, param, constants.MapStatus[constants.STATUS_ACTIVE])
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CreateQuestionResponse, 0, len(questions))
+	for _, question := range questions {
+		if !question.IsAskedTo(!param.IsRegistrant) {
+			continue
+		}
+
+		response = append(response, question.ToResponse())
+	}
+
+	return response, nil
+}
+
+func (equ *eventQuestionUsecase) GetAllInternal(ctx context.Context, param models.GetQuestionsParameter) (response []models.CreateQuestionResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	questions, err := equ.getAll(ctx, param, "")
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CreateQuestionResponse, len(questions))
+	for i, question := range questions {
+		response[i] = question.ToResponse()
+	}
+
+	return response, nil
+}
+
+func (equ *eventQuestionUsecase) Update(ctx context.Context, request models.UpdateQuestionRequest) (response *models.CreateQuestionResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	question, err := equ.r.EventQuestion.GetById(ctx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if question.ID == uuid.Nil {
+		return nil, models.ErrorDataNotFound
+	}
+
+	if request.InstanceCode != nil {
+		if err = equ.checkInstanceCodes(ctx, question.EventCode, request.InstanceCode); err != nil {
+			return nil, err
+		}
+		question.InstanceCode = request.InstanceCode
+	}
+
+	if request.Question != nil {
+		question.Question = *request.Question
+	}
+
+	if request.Description != nil {
+		question.Description = *request.Description
+	}
+
+	if request.IsMainRequired != nil {
+		question.IsMainRequired = *request.IsMainRequired
+	}
+
+	if request.IsRegistrantRequired != nil {
+		question.IsRegistrantRequired = *request.IsRegistrantRequired
+	}
+
+	if request.Options != nil {
+		question.Options = request.Options
+	}
+
+	if request.DisplayOrder != nil {
+		question.DisplayOrder = request.DisplayOrder
+	}
+
+	if request.IsVisibleToRegistrant != nil {
+		question.IsVisibleToRegistrant = *request.IsVisibleToRegistrant
+	}
+
+	if request.Rules != nil {
+		question.Rules = request.Rules
+	}
+
+	if request.Status != nil {
+		question.Status = *request.Status
+	}
+
+	if (question.Type == constants.QuestionTypeSingle || question.Type == constants.QuestionTypeMultiple) && len(question.Options) == 0 {
+		return nil, models.ErrorCannotBeEmpty
+	}
+
+	existing, err := equ.getQuestionMap(ctx, question.EventCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = multierror.Append(question.Rules.Validate(question.Type, question.Options, "rules"), checkCondition(question, existing, "rules")).ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	if err = equ.r.EventQuestion.Update(ctx, &question); err != nil {
+		return nil, err
+	}
+
+	res := question.ToResponse()
+	return &res, nil
+}
+
+// Delete removes the question from the registration form. Answers already given to it are kept.
+func (equ *eventQuestionUsecase) Delete(ctx context.Context, param models.DeleteQuestionParameter) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	question, err := equ.r.EventQuestion.GetById(ctx, param.ID)
+	if err != nil {
+		return err
+	}
+
+	if question.ID == uuid.Nil {
+		return models.ErrorDataNotFound
+	}
+
+	return equ.r.EventQuestion.Delete(ctx, param.ID)
+}
+
+func (equ *eventQuestionUsecase) getAll(ctx context.Context, param models.GetQuestionsParameter, status string) ([]models.EventQuestion, error) {
+	existEvent, err := equ.r.Event.CheckByCode(ctx, param.EventCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !existEvent {
+		return nil, models.ErrorDataNotFound
+	}
+
+	if param.InstanceCode != "" && param.InstanceCode[:7] != param.EventCode {
+		return nil, models.ErrorMismatchFields
+	}
+
+	return equ.r.EventQuestion.GetManyByEventCodeAndInstanceCode(ctx, param.EventCode, param.InstanceCode, status)
+}
+
+func (equ *eventQuestionUsecase) getQuestionMap(ctx context.Context, eventCode string) (map[uuid.UUID]models.EventQuestion, error) {
+	list, err := equ.r.EventQuestion.GetManyByEventCodeAndInstanceCode(ctx, eventCode, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make(map[uuid.UUID]models.EventQuestion, len(list))
+	for _, q := range list {
+		questions[q.ID] = q
+	}
+
+	return questions, nil
+}
+
+// checkCondition makes sure the show-if condition of the question refers to another question of the event,
+// by one of its options when it is a choice question, without the conditions leading back to the question.
+func checkCondition(question models.EventQuestion, questions map[uuid.UUID]models.EventQuestion, field string) error {
+	if question.Rules == nil || question.Rules.ShowIf == nil || question.Rules.ShowIf.QuestionId == uuid.Nil {
+		return nil
+	}
+
+	invalid := func(name string, message string) error {
+		return multierror.Append(nil, models.ErrorValidateResponse{
+			Code:    "INVALID_RULES",
+			Field:   fmt.Sprintf("%s.show_if.%s", field, name),
+			Message: message,
+		})
+	}
+
+	condition := question.Rules.ShowIf
+	target, exists := questions[condition.QuestionId]
+	if !exists || target.ID == question.ID {
+		return invalid("question_id", "show_if.question_id must be another question of the event")
+	}
+
+	if target.Type == constants.QuestionTypeSingle || target.Type == constants.QuestionTypeMultiple {
+		for _, value := range condition.Values {
+			if !slices.Contains(target.Options, value) {
+				return invalid("values", fmt.Sprintf("%q is not one of the options of the question", value))
+			}
+		}
+	}
+
+	for range len(questions) {
+		if target.Rules == nil || target.Rules.ShowIf == nil {
+			return nil
+		}
+
+		if target.Rules.ShowIf.QuestionId == question.ID {
+			return invalid("question_id", "show_if conditions cannot lead back to the question")
+		}
+
+		if target, exists = questions[target.Rules.ShowIf.QuestionId]; !exists {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// checkInstanceCodes makes sure every instance code belongs to the event and exists.
+func (equ *eventQuestionUsecase) checkInstanceCodes(ctx context.Context, eventCode string, instanceCodes []string) error {
+	if len(instanceCodes) == 0 {
+		return nil
+	}
+
+	for _, instanceCode := range instanceCodes {
+		if eventCode != instanceCode[:7] {
+			return models.ErrorMismatchFields
+		}
+	}
+
+	existInstance, err := equ.r.EventInstance.CheckMultiple(ctx, instanceCodes)
+	if err != nil {
+		return err
+	}
+
+	if int(existInstance) != len(instanceCodes) {
+		return models.ErrorDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,473 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-community/internal/common"
+	"go-community/internal/config"
+	"go-community/internal/constants"
+	"go-community/internal/models"
+	"go-community/internal/pkg/generator"
+	"go-community/internal/pkg/logger"
+	"go-community/internal/pkg/recurrence"
+	"go-community/internal/repositories/pgsql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type EventRecurrenceUsecase interface {
+	Preview(ctx context.Context, param models.GetRecurrencePreviewParameter) (response []models.GetRecurrencePreviewResponse, err error)
+	Generate(ctx context.Context, param models.GenerateRecurrenceParameter) (response []models.CreateInstanceResponse, err error)
+	GenerateAll(ctx context.Context) (err error)
+	UpsertException(ctx context.Context, request models.UpsertRecurrenceExceptionRequest) (response *models.RecurrenceExceptionResponse, err error)
+	DeleteException(ctx context.Context, param models.DeleteRecurrenceExceptionParameter) (err error)
+}
+
+type eventRecurrenceUsecase struct {
+	cfg *config.Configuration
+	r   pgsql.PostgreRepositories
+}
+
+func NewEventRecurrenceUsecase(cfg config.Configuration, r pgsql.PostgreRepositories) *eventRecurrenceUsecase {
+	return &eventRecurrenceUsecase{
+		cfg: &cfg,
+		r:   r,
+	}
+}
+
+// occurrence is one date of a recurring event, with the instance it has or would be generated as.
+type occurrence struct {
+	At       time.Time
+	Instance models.EventInstance
+	Status   string
+}
+
+func (eru *eventRecurrenceUsecase) Preview(ctx context.Context, param models.GetRecurrencePreviewParameter) (response []models.GetRecurrencePreviewResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	from := common.Now()
+	if param.From != "" {
+		from, err = common.ParseStringToDatetime(time.RFC3339, param.From, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+	}
+
+	until := from.Add(recurrenceHorizon(eru.cfg))
+	if param.Until != "" {
+		until, err = common.ParseStringToDatetime(time.RFC3339, param.Until, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+	}
+
+	if from.After(until) {
+		return nil, models.ErrorStartDateLater
+	}
+
+	// A preview covers at most a year so an unbounded daily rule cannot produce an unbounded response
+	if until.Sub(from) > 366*24*time.Hour {
+		return nil, models.ErrorInvalidInput
+	}
+
+	event, err := eru.getRecurringEvent(ctx, &eru.r, param.EventCode)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences, err := planOccurrences(ctx, &eru.r, event, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.GetRecurrencePreviewResponse, len(occurrences))
+	for i, o := range occurrences {
+		response[i] = models.GetRecurrencePreviewResponse{
+			Type:            models.TYPE_EVENT_RECURRENCE,
+			EventCode:       event.Code,
+			OccurrenceAt:    o.At,
+			InstanceCode:    o.Instance.Code,
+			Title:           o.Instance.Title,
+			LocationName:    o.Instance.LocationName,
+			InstanceStartAt: o.Instance.InstanceStartAt,
+			InstanceEndAt:   o.Instance.InstanceEndAt,
+			TotalSeats:      o.Instance.TotalSeats,
+			Status:          o.Status,
+		}
+	}
+
+	return response, nil
+}
+
+func (eru *eventRecurrenceUsecase) Generate(ctx context.Context, param models.GenerateRecurrenceParameter) (response []models.CreateInstanceResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	var instances []models.EventInstance
+	err = eru.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		event, err := eru.getRecurringEvent(ctx, r, param.EventCode)
+		if err != nil {
+			return err
+		}
+
+		now := common.Now()
+		instances, err = generateRecurringInstances(ctx, r, event, now, now.Add(recurrenceHorizon(eru.cfg)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.CreateInstanceResponse, len(instances))
+	for i, instance := range instances {
+		response[i] = instance.ToCreateResponse()
+	}
+
+	return response, nil
+}
+
+// GenerateAll tops up the instances of every active recurring event until the configured horizon.
+// It is run by the scheduler, so a failing event is logged and skipped instead of stopping the others.
+func (eru *eventRecurrenceUsecase) GenerateAll(ctx context.Context) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	events, err := eru.r.Event.GetAllRecurring(ctx, constants.MapStatus[constants.STATUS_ACTIVE])
+	if err != nil {
+		return err
+	}
+
+	now := common.Now()
+	until := now.Add(recurrenceHorizon(eru.cfg))
+	var errs []error
+	for _, event := range events {
+		err := eru.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+			instances, err := generateRecurringInstances(ctx, r, event, now, until)
+			if err != nil {
+				return err
+			}
+
+			if len(instances) > 0 {
+				logger.Logger.Info("[RECURRENCE] generated instances", zap.String("eventCode", event.Code), zap.Int("total", len(instances)))
+			}
+
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("event %s: %w", event.Code, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (eru *eventRecurrenceUsecase) UpsertException(ctx context.Context, request models.UpsertRecurrenceExceptionRequest) (response *models.RecurrenceExceptionResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	occurrenceAt, err := common.ParseStringToDatetime(time.RFC3339, request.OccurrenceAt, common.GetLocation())
+	if err != nil {
+		return nil, models.ErrorInvalidInput
+	}
+
+	exception := models.EventRecurrenceException{
+		EventCode:    request.EventCode,
+		OccurrenceAt: occurrenceAt,
+		IsSkipped:    request.IsSkipped,
+		Title:        sql.NullString{String: request.Title, Valid: request.Title != ""},
+		LocationName: sql.NullString{String: request.LocationName, Valid: request.LocationName != ""},
+		UpdatedAt:    common.Now(),
+	}
+
+	if request.InstanceStartAt != "" {
+		instanceStart, err := common.ParseStringToDatetime(time.RFC3339, request.InstanceStartAt, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+		exception.InstanceStartAt = sql.NullTime{Time: instanceStart, Valid: true}
+	}
+
+	if request.InstanceEndAt != "" {
+		instanceEnd, err := common.ParseStringToDatetime(time.RFC3339, request.InstanceEndAt, common.GetLocation())
+		if err != nil {
+			return nil, models.ErrorInvalidInput
+		}
+		exception.InstanceEndAt = sql.NullTime{Time: instanceEnd, Valid: true}
+	}
+
+	if request.TotalSeats != nil {
+		exception.TotalSeats = sql.NullInt32{Int32: int32(*request.TotalSeats), Valid: true}
+	}
+
+	err = eru.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		event, err := eru.getRecurringEvent(ctx, r, request.EventCode)
+		if err != nil {
+			return err
+		}
+
+		occurrences, err := planOccurrences(ctx, r, event, occurrenceAt, occurrenceAt)
+		if err != nil {
+			return err
+		}
+
+		if len(occurrences) == 0 {
+			return models.ErrorNotAnOccurrence
+		}
+
+		if occurrences[0].Status == models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_GENERATED] {
+			return models.ErrorOccurrenceAlreadyGenerated
+		}
+
+		template, err := r.EventInstance.GetRecurrenceTemplate(ctx, event.Code)
+		if err != nil {
+			return err
+		}
+
+		instance := instanceFromTemplate(template, occurrenceAt, &exception)
+		if instance.InstanceStartAt.After(instance.InstanceEndAt) {
+			return models.ErrorStartDateLater
+		}
+
+		return r.EventRecurrenceException.Upsert(ctx, &exception)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return exception.ToResponse(), nil
+}
+
+func (eru *eventRecurrenceUsecase) DeleteException(ctx context.Context, param models.DeleteRecurrenceExceptionParameter) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	occurrenceAt, err := common.ParseStringToDatetime(time.RFC3339, param.OccurrenceAt, common.GetLocation())
+	if err != nil {
+		return models.ErrorInvalidInput
+	}
+
+	rowsAffected, err := eru.r.EventRecurrenceException.DeleteByOccurrence(ctx, param.EventCode, occurrenceAt)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrorDataNotFound
+	}
+
+	return nil
+}
+
+func (eru *eventRecurrenceUsecase) getRecurringEvent(ctx context.Context, r *pgsql.PostgreRepositories, eventCode string) (event models.Event, err error) {
+	event, err = r.Event.GetByCode(ctx, eventCode)
+	if err != nil {
+		return event, err
+	}
+
+	if event.ID == 0 {
+		return event, models.ErrorDataNotFound
+	}
+
+	if !event.IsRecurring {
+		return event, models.ErrorEventNotRecurring
+	}
+
+	return event, nil
+}
+
+// recurrenceHorizon is how far ahead the occurrences of a recurring event are generated as instances.
+func recurrenceHorizon(cfg *config.Configuration) time.Duration {
+	if cfg.Recurrence.HorizonDays <= 0 {
+		return 90 * 24 * time.Hour
+	}
+
+	return time.Duration(cfg.Recurrence.HorizonDays) * 24 * time.Hour
+}
+
+// validateRecurrence checks the recurrence rule of an event before it is stored.
+func validateRecurrence(isRecurring bool, rule string) error {
+	if !isRecurring {
+		return nil
+	}
+
+	if _, err := recurrence.Parse(rule); err != nil {
+		return models.ErrorInvalidRecurrence
+	}
+
+	return nil
+}
+
+// planOccurrences lists the occurrences of a recurring event within [from, until]. An occurrence that already has an
+// instance, including one that was deleted afterwards, is reported as generated and is never generated again.
+func planOccurrences(ctx context.Context, r *pgsql.PostgreRepositories, event models.Event, from time.Time, until time.Time) ([]occurrence, error) {
+	rule, err := recurrence.Parse(event.Recurrence)
+	if err != nil {
+		return nil, models.ErrorInvalidRecurrence
+	}
+
+	template, err := r.EventInstance.GetRecurrenceTemplate(ctx, event.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a first occurrence there is nothing to repeat
+	if template.ID == 0 {
+		return nil, nil
+	}
+
+	existing, err := r.EventInstance.GetManyRecurringByEventCode(ctx, event.Code, from)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := r.EventRecurrenceException.GetManyByEventCode(ctx, event.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	existingByAt := make(map[int64]models.EventInstance, len(existing))
+	for _, instance := range existing {
+		existingByAt[instance.RecurrenceAt.Time.Unix()] = instance
+	}
+
+	exceptionsByAt := make(map[int64]models.EventRecurrenceException, len(exceptions))
+	for _, exception := range exceptions {
+		exceptionsByAt[exception.OccurrenceAt.Unix()] = exception
+	}
+
+	start := template.RecurrenceAt.Time.In(common.GetLocation())
+	var occurrences []occurrence
+	for _, at := range rule.Between(start, from.In(common.GetLocation()), until.In(common.GetLocation())) {
+		if instance, exists := existingByAt[at.Unix()]; exists {
+			occurrences = append(occurrences, occurrence{At: at, Instance: instance, Status: models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_GENERATED]})
+			continue
+		}
+
+		exception, exists := exceptionsByAt[at.Unix()]
+		switch {
+		case !exists:
+			occurrences = append(occurrences, occurrence{At: at, Instance: instanceFromTemplate(template, at, nil), Status: models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_SCHEDULED]})
+		case exception.IsSkipped:
+			occurrences = append(occurrences, occurrence{At: at, Instance: instanceFromTemplate(template, at, nil), Status: models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_SKIPPED]})
+		default:
+			occurrences = append(occurrences, occurrence{At: at, Instance: instanceFromTemplate(template, at, &exception), Status: models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_OVERRIDDEN]})
+		}
+	}
+
+	return occurrences, nil
+}
+
+// instanceFromTemplate copies the seats, flow and time windows of the template instance to an occurrence.
+// Every window keeps its distance to the instance start, which an exception may move along with its other overrides.
+func instanceFromTemplate(template models.EventInstance, at time.Time, exception *models.EventRecurrenceException) models.EventInstance {
+	shift := at.Sub(template.RecurrenceAt.Time)
+	if exception != nil && exception.InstanceStartAt.Valid {
+		shift = exception.InstanceStartAt.Time.Sub(template.InstanceStartAt)
+	}
+
+	instance := models.EventInstance{
+		EventCode:         template.EventCode,
+		Title:             template.Title,
+		Description:       template.Description,
+		InstanceStartAt:   template.InstanceStartAt.Add(shift),
+		InstanceEndAt:     template.InstanceEndAt.Add(shift),
+		RegisterStartAt:   template.RegisterStartAt.Add(shift),
+		RegisterEndAt:     template.RegisterEndAt.Add(shift),
+		AllowVerifyAt:     template.AllowVerifyAt.Add(shift),
+		DisallowVerifyAt:  template.DisallowVerifyAt.Add(shift),
+		LocationType:      template.LocationType,
+		LocationName:      template.LocationName,
+		MaxPerTransaction: template.MaxPerTransaction,
+		IsOnePerAccount:   template.IsOnePerAccount,
+		IsOnePerTicket:    template.IsOnePerTicket,
+		IsWaitlistEnabled: template.IsWaitlistEnabled,
+		RegisterFlow:      template.RegisterFlow,
+		CheckType:         template.CheckType,
+		TotalSeats:        template.TotalSeats,
+		Status:            constants.MapStatus[constants.STATUS_ACTIVE],
+		RecurrenceAt:      sql.NullTime{Time: at, Valid: true},
+	}
+
+	if exception == nil {
+		return instance
+	}
+
+	if exception.InstanceEndAt.Valid {
+		instance.InstanceEndAt = exception.InstanceEndAt.Time
+	}
+
+	if exception.Title.Valid {
+		instance.Title = exception.Title.String
+	}
+
+	if exception.LocationName.Valid {
+		instance.LocationName = exception.LocationName.String
+	}
+
+	if exception.TotalSeats.Valid {
+		instance.TotalSeats = int(exception.TotalSeats.Int32)
+	}
+
+	return instance
+}
+
+// generateRecurringInstances creates the instances of the scheduled and overridden occurrences within [from, until].
+// It has to run in a transaction. Only one transaction generates the instances of an event at a time, the others
+// leave them to it, so the scheduler running on every replica does not generate an occurrence twice.
+func generateRecurringInstances(ctx context.Context, r *pgsql.PostgreRepositories, event models.Event, from time.Time, until time.Time) ([]models.EventInstance, error) {
+	locked, err := r.Transaction.TryLock(ctx, fmt.Sprintf("event-recurrence:%s", event.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	if !locked {
+		return nil, nil
+	}
+
+	occurrences, err := planOccurrences(ctx, r, event, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	countInstance, err := r.EventInstance.CountByCode(ctx, event.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	timeNowNano, err := common.NowWithNanoTime()
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []models.EventInstance
+	for _, o := range occurrences {
+		if o.Status != models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_SCHEDULED] && o.Status != models.MapOccurrenceStatus[models.OCCURRENCE_STATUS_OVERRIDDEN] {
+			continue
+		}
+
+		numberForCode := int(countInstance) + len(instances) + 1
+		code := fmt.Sprintf("instance-%s-%d-%d", event.Code, numberForCode, timeNowNano.UnixNano())
+		o.Instance.Code = fmt.Sprintf("%s-%s", event.Code, generator.GenerateHashCode(code, 7))
+
+		instances = append(instances, o.Instance)
+	}
+
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	if err = r.EventInstance.BulkCreate(ctx, &instances); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
@@ -5,14 +5,19 @@ import (
 	"context"
 	"database/sql"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
 	"github.com/xuri/excelize/v2"
 	"go-community/internal/common"
 	"go-community/internal/config"
 	"go-community/internal/constants"
 	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
+	"go-community/internal/pkg/notification"
 	"go-community/internal/repositories/pgsql"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,17 +30,25 @@ type EventRegistrationRecordUsecase interface {
 	GetAttendance(ctx context.Context, request models.GetEventAttendanceParameter) (detail *models.GetEventAttendanceDetailResponse, list []models.GetEventAttendanceListResponse, err error)
 	GetAllCursor(ctx context.Context, params models.GetAllRegisteredCursorParam) (res []models.GetAllRegisteredCursorResponse, total int, err error)
 	Download(ctx context.Context, param models.GetDownloadAllRegisteredParam) (data []byte, contentType string, err error)
+	SyncStatus(ctx context.Context, request *models.SyncRegistrationStatusRequest, value *models.TokenValues) (response []models.SyncRegistrationStatusResponse, err error)
+	VerifyTicket(ctx context.Context, request *models.VerifyRegistrationTicketRequest, value *models.TokenValues) (response *models.VerifyRegistrationTicketResponse, err error)
 }
 
 type eventRegistrationRecordUsecase struct {
 	r   pgsql.PostgreRepositories
 	cfg config.Configuration
+	t   authorization.Ticket
+	n   *notification.Notifier
+	p   *permissionUsecase
 }
 
-func NewEventRegistrationRecordUsecase(r pgsql.PostgreRepositories, cfg config.Configuration) *eventRegistrationRecordUsecase {
+func NewEventRegistrationRecordUsecase(r pgsql.PostgreRepositories, cfg config.Configuration, t authorization.Ticket, n *notification.Notifier, p *permissionUsecase) *eventRegistrationRecordUsecase {
 	return &eventRegistrationRecordUsecase{
 		r:   r,
 		cfg: cfg,
+		t:   t,
+		n:   n,
+		p:   p,
 	}
 }
 
@@ -44,14 +57,20 @@ func (erru *eventRegistrationRecordUsecase) Create(ctx context.Context, request
 		LogService(ctx, err)
 	}()
 
-	if err = erru.validateCreate(ctx, request, value); err != nil {
+	isWaitlisted, err := erru.validateCreate(ctx, request, value)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := erru.validateAnswers(ctx, request)
+	if err != nil {
 		return nil, err
 	}
 
-	return erru.createAtomic(ctx, request, value)
+	return erru.createAtomic(ctx, request, value, isWaitlisted, questions)
 }
 
-func (erru *eventRegistrationRecordUsecase) createAtomic(ctx context.Context, request *models.CreateEventRegistrationRecordRequest, value *models.TokenValues) (response *models.CreateEventRegistrationRecordResponse, err error) {
+func (erru *eventRegistrationRecordUsecase) createAtomic(ctx context.Context, request *models.CreateEventRegistrationRecordRequest, value *models.TokenValues, isWaitlisted bool, questions map[uuid.UUID]models.EventQuestion) (response *models.CreateEventRegistrationRecordResponse, err error) {
 	res := &models.CreateEventRegistrationRecordResponse{}
 
 	var (
@@ -92,6 +111,10 @@ func (erru *eventRegistrationRecordUsecase) createAtomic(ctx context.Context, re
 	err = erru.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
 		countTotalRegistrants := 1 + len(request.Registrants)
 		var register = make([]models.EventRegistrationRecord, 0, countTotalRegistrants)
+		if err := r.EventInstance.LockByCode(ctx, request.InstanceCode); err != nil {
+			return err
+		}
+
 		instance, err := r.EventInstance.GetSeatsNamesByCode(ctx, request.InstanceCode)
 		if err != nil {
 			return err
@@ -101,6 +124,20 @@ func (erru *eventRegistrationRecordUsecase) createAtomic(ctx context.Context, re
 			return models.ErrorDataNotFound
 		}
 
+		if !isWaitlisted && instance.TotalSeats != 0 {
+			isQuotaNotAvailable := instance.BookedSeats+countTotalRegistrants > instance.TotalSeats || (instance.TotalRemainingSeats-countTotalRegistrants) <= 0
+			if isQuotaNotAvailable && (!instance.IsWaitlistEnabled || request.IsPersonalQR) {
+				return models.ErrorRegisterQuotaNotAvailable
+			}
+
+			// Seats ran out between validation and booking, so the registration falls back to the waitlist
+			isWaitlisted = isQuotaNotAvailable
+		}
+
+		if isWaitlisted {
+			registerStatus = models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED]
+		}
+
 		main := models.EventRegistrationRecord{
 			ID:                uuid.New(),
 			Name:              name,
@@ -137,40 +174,52 @@ func (erru *eventRegistrationRecordUsecase) createAtomic(ctx context.Context, re
 			return err
 		}
 
-		instance.BookedSeats += countTotalRegistrants
-
-		if instance.TotalSeats != 0 {
-			if instance.BookedSeats > instance.TotalSeats {
-				return models.ErrorRegisterQuotaNotAvailable
-			}
+		answers := newAnswers(main.ID, request.Answers)
+		for i, registrant := range request.Registrants {
+			answers = append(answers, newAnswers(register[i+1].ID, registrant.Answers)...)
+		}
 
-			if (instance.TotalRemainingSeats - countTotalRegistrants) <= 0 {
-				return models.ErrorRegisterQuotaNotAvailable
+		answerRes := make(map[uuid.UUID][]models.CreateAnswerResponse)
+		if len(answers) > 0 {
+			if err = r.EventAnswer.BulkCreate(ctx, &answers); err != nil {
+				return err
 			}
 
-			if (instance.TotalSeats - instance.BookedSeats) < 0 {
-				return models.ErrorRegisterQuotaNotAvailable
+			for _, a := range answers {
+				question := questions[a.QuestionID]
+				answerRes[a.RegistrationId] = append(answerRes[a.RegistrationId], a.ToResponse(&question))
 			}
 		}
 
-		if request.IsPersonalQR {
-			instance.ScannedSeats += countTotalRegistrants
-			if err = r.EventInstance.UpdateSeatsByCode(ctx, request.InstanceCode, instance); err != nil {
-				return err
-			}
-		} else {
-			if err = r.EventInstance.UpdateBookedSeatsByCode(ctx, request.InstanceCode, instance); err != nil {
-				return err
+		if !isWaitlisted {
+			instance.BookedSeats += countTotalRegistrants
+
+			if request.IsPersonalQR {
+				instance.ScannedSeats += countTotalRegistrants
+				if err = r.EventInstance.UpdateSeatsByCode(ctx, request.InstanceCode, instance); err != nil {
+					return err
+				}
+			} else {
+				if err = r.EventInstance.UpdateBookedSeatsByCode(ctx, request.InstanceCode, instance); err != nil {
+					return err
+				}
 			}
 		}
 
 		registrantRes := make([]models.CreateOtherEventRegistrationRecordResponse, len(register))
 		for i, p := range register {
+			ticket, err := issueTicket(&erru.t, p.ID, p.InstanceCode, p.Status, instance.InstanceAllowVerifyAt, instance.InstanceDisallowVerifyAt)
+			if err != nil {
+				return err
+			}
+
 			registrantRes[i] = models.CreateOtherEventRegistrationRecordResponse{
-				Type:   models.TYPE_EVENT_REGISTRATION_RECORD,
-				ID:     p.ID,
-				Name:   p.Name,
-				Status: p.Status,
+				Type:    models.TYPE_EVENT_REGISTRATION_RECORD,
+				ID:      p.ID,
+				Name:    p.Name,
+				Status:  p.Status,
+				Ticket:  ticket,
+				Answers: answerRes[p.ID],
 			}
 		}
 
@@ -189,59 +238,177 @@ func (erru *eventRegistrationRecordUsecase) createAtomic(ctx context.Context, re
 			Description:      request.Description,
 			RegisterAt:       registerAt,
 			Registrants:      registrantRes[1:],
+			Ticket:           registrantRes[0].Ticket,
+			Answers:          registrantRes[0].Answers,
 		}
 
-		return nil
+		return enqueueNotification(ctx, r, erru.n, notification.EventRegistrationCreated, main.Identifier, notification.RegistrationData{
+			Name:             main.Name,
+			EventTitle:       instance.EventTitle,
+			InstanceTitle:    instance.EventInstanceTitle,
+			Status:           registerStatus,
+			TotalRegistrants: countTotalRegistrants,
+			At:               registerAt,
+		})
 	})
 	return res, err
 }
 
-func (erru *eventRegistrationRecordUsecase) validateCreate(ctx context.Context, request *models.CreateEventRegistrationRecordRequest, value *models.TokenValues) error {
+// validateAnswers checks the answers of the main registrant and of every other registrant against the active
+// questions of the instance, and returns those questions by id so the answers can be stored and shown with them.
+// Every problem found is reported on the field of its answer, as a *multierror.Error of models.ErrorValidateResponse.
+func (erru *eventRegistrationRecordUsecase) validateAnswers(ctx context.Context, request *models.CreateEventRegistrationRecordRequest) (questions map[uuid.UUID]models.EventQuestion, err error) {
+	list, err := erru.r.EventQuestion.GetManyByEventCodeAndInstanceCode(ctx, request.EventCode, request.InstanceCode, constants.MapStatus[constants.STATUS_ACTIVE])
+	if err != nil {
+		return nil, err
+	}
+
+	questions = make(map[uuid.UUID]models.EventQuestion, len(list))
+	for _, q := range list {
+		questions[q.ID] = q
+	}
+
+	// A personal QR is scanned at the door where there is no form to fill in, so nothing can be required from it
+	errs := multierror.Append(nil, checkAnswers(list, questions, request.Answers, true, !request.IsPersonalQR, "answers"))
+	for i, registrant := range request.Registrants {
+		errs = multierror.Append(errs, checkAnswers(list, questions, registrant.Answers, false, true, fmt.Sprintf("registrants[%d].answers", i)))
+	}
+
+	if err = errs.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// checkAnswers validates the answers of one registrant, each question shown to them answered at most once,
+// and when isRequiredChecked, makes sure the questions required from them are answered.
+// A question hidden by its show-if condition is not required, and answering it is rejected.
+func checkAnswers(list []models.EventQuestion, questions map[uuid.UUID]models.EventQuestion, answers []models.CreateAnswerRequest, isMain bool, isRequiredChecked bool, field string) error {
+	var errs *multierror.Error
+	invalid := func(questionId uuid.UUID, code string, message string) {
+		errs = multierror.Append(errs, models.ErrorValidateResponse{
+			Code:    code,
+			Field:   fmt.Sprintf("%s.%s", field, questionId),
+			Message: message,
+		})
+	}
+
+	answered := make(map[uuid.UUID]models.CreateAnswerRequest, len(answers))
+	for _, answer := range answers {
+		if _, exists := answered[answer.QuestionId]; exists {
+			invalid(answer.QuestionId, "INVALID_ANSWER", "question is answered more than once")
+			continue
+		}
+		answered[answer.QuestionId] = answer
+	}
+
+	checked := make(map[uuid.UUID]bool, len(answered))
+	for _, answer := range answers {
+		id := answer.QuestionId
+		if checked[id] {
+			continue
+		}
+		checked[id] = true
+
+		question, exists := questions[id]
+		if !exists || !question.IsAskedTo(isMain) {
+			invalid(id, "INVALID_ANSWER", "question is not on the form")
+			continue
+		}
+
+		if !question.IsShown(isMain, questions, answered) {
+			invalid(id, "INVALID_ANSWER", "question is not shown for the other answers given")
+			continue
+		}
+
+		var validateErr models.ErrorValidateResponse
+		if err := question.ValidateAnswer(answer.Value, answer.Values); errors.As(err, &validateErr) {
+			validateErr.Field = fmt.Sprintf("%s.%s", field, id)
+			errs = multierror.Append(errs, validateErr)
+		}
+	}
+
+	if !isRequiredChecked {
+		return errs.ErrorOrNil()
+	}
+
+	for _, question := range list {
+		isRequired := question.IsRegistrantRequired
+		if isMain {
+			isRequired = question.IsMainRequired
+		}
+
+		if _, exists := answered[question.ID]; isRequired && !exists && question.IsShown(isMain, questions, answered) {
+			invalid(question.ID, "MISSING_FIELDS", fmt.Sprintf("%s is required", question.Question))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func newAnswers(registrationId uuid.UUID, requests []models.CreateAnswerRequest) []models.EventAnswer {
+	answers := make([]models.EventAnswer, len(requests))
+	for i, request := range requests {
+		answers[i] = models.EventAnswer{
+			ID:             uuid.New(),
+			QuestionID:     request.QuestionId,
+			RegistrationId: registrationId,
+			Value:          request.Value,
+			Values:         request.Values,
+		}
+	}
+
+	return answers
+}
+
+func (erru *eventRegistrationRecordUsecase) validateCreate(ctx context.Context, request *models.CreateEventRegistrationRecordRequest, value *models.TokenValues) (isWaitlisted bool, err error) {
 	if request.EventCode != request.InstanceCode[:7] {
-		return models.ErrorMismatchFields
+		return false, models.ErrorMismatchFields
 	}
 
 	if request.Identifier == "" && request.CommunityId == "" {
-		return models.ErrorIdentifierCommunityIdEmpty
+		return false, models.ErrorIdentifierCommunityIdEmpty
 	}
 
 	if request.IsPersonalQR {
 		if request.CommunityId == "" {
-			return models.ErrorInvalidInput
+			return false, models.ErrorInvalidInput
 		}
 
 		userExist, err := erru.r.User.GetByCommunityId(ctx, request.CommunityId)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		if &userExist == nil {
-			return models.ErrorDataNotFound
+			return false, models.ErrorDataNotFound
 		}
 
 	}
 
 	countTotalRegistrants := 1 + len(request.Registrants)
 	if request.IsPersonalQR && countTotalRegistrants > 1 {
-		return models.ErrorQRForMoreThanOneRegister
+		return false, models.ErrorQRForMoreThanOneRegister
 	}
 
+	var isEventFull bool
 	event, err := erru.r.Event.GetOneByCode(ctx, request.EventCode)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	eventAvailableStatus, err := models.DefineAvailabilityStatus(event)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	registerAt, _ := common.ParseStringToDatetime(time.RFC3339, request.RegisterAt, common.GetLocation())
 	switch {
 	case event.EventCode == "" || event.EventStatus != constants.MapStatus[constants.STATUS_ACTIVE]:
-		return models.ErrorDataNotFound
+		return false, models.ErrorDataNotFound
 	case request.EventCode != event.EventCode:
-		return models.ErrorEventNotValid
+		return false, models.ErrorEventNotValid
 	//case common.Now().Before(event.EventRegisterStartAt.In(common.GetLocation())):
 	//	return models.ErrorCannotRegisterYet
 	//case common.Now().After(event.EventRegisterEndAt.In(common.GetLocation())):
@@ -260,143 +427,147 @@ func (erru *eventRegistrationRecordUsecase) validateCreate(ctx context.Context,
 	case !request.IsPersonalQR && event.EventAllowedFor != "public":
 		userExist, err := erru.r.User.GetByCommunityId(ctx, request.CommunityId)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		if &userExist == nil {
-			return models.ErrorDataNotFound
+			return false, models.ErrorDataNotFound
 		}
 
 		isAllowedRoles := common.CheckOneDataInList(event.EventAllowedRoles, userExist.Roles)
 		isAllowedUsers := common.CheckOneDataInList(event.EventAllowedUsers, userExist.UserTypes)
 		if !isAllowedRoles && !isAllowedUsers {
-			return models.ErrorForbiddenRole
+			return false, models.ErrorForbiddenRole
 		}
 	//case eventAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_UNAVAILABLE]:
 	//	return models.ErrorEventNotAvailable
 	case eventAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_FULL]:
-		return models.ErrorRegisterQuotaNotAvailable
+		// Decided after the instance is known, since a full event can still accept waitlisted registrations
+		isEventFull = true
 		//case eventAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_SOON]:
 		//	return models.ErrorCannotRegisterYet
 	}
 
 	instance, err := erru.r.EventInstance.GetOneByCode(ctx, request.InstanceCode, constants.MapStatus[constants.STATUS_ACTIVE])
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	instanceAvailableStatus, err := models.DefineAvailabilityStatus(instance)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	isWaitlistAllowed := instance.InstanceIsWaitlistEnabled && !request.IsPersonalQR
+	isQuotaNotAvailable := isEventFull ||
+		(((instance.TotalRemainingSeats - countTotalRegistrants) <= 0) && instance.InstanceRegisterFlow != models.MapRegisterFlow[models.REGISTER_FLOW_NONE] && event.EventIsRecurring == false && instance.InstanceTotalSeats > 0) ||
+		instanceAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_FULL]
+
 	switch {
 	case instance.InstanceCode == "" || instance.InstanceStatus != constants.MapStatus[constants.STATUS_ACTIVE]:
-		return models.ErrorDataNotFound
+		return false, models.ErrorDataNotFound
 	case instance.InstanceEventCode != request.EventCode || instance.InstanceEventCode != event.EventCode:
-		return models.ErrorEventNotValid
+		return false, models.ErrorEventNotValid
 	case instance.InstanceRegisterFlow == models.MapRegisterFlow[models.REGISTER_FLOW_NONE]:
-		return models.ErrorNoRegistrationNeeded
+		return false, models.ErrorNoRegistrationNeeded
 	case request.IsPersonalQR && instance.InstanceRegisterFlow == models.MapRegisterFlow[models.REGISTER_FLOW_EVENT]:
-		return models.ErrorCannotUsePersonalQR
+		return false, models.ErrorCannotUsePersonalQR
 	//case instanceAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_UNAVAILABLE]:
 	//	return models.ErrorEventNotAvailable
 	//
 	case registerAt.After(instance.InstanceRegisterEndAt.In(common.GetLocation())):
-		return models.ErrorRegistrationTimeDisabled
+		return false, models.ErrorRegistrationTimeDisabled
 	case request.IsPersonalQR && common.Now().Before(instance.InstanceAllowVerifyAt.In(common.GetLocation())):
-		return models.ErrorCannotRegisterYet
+		return false, models.ErrorCannotRegisterYet
 	case request.IsPersonalQR && registerAt.After(instance.InstanceDisallowVerifyAt.In(common.GetLocation())):
-		return models.ErrorRegistrationTimeDisabled
-	case ((instance.TotalRemainingSeats - countTotalRegistrants) <= 0) && instance.InstanceRegisterFlow != models.MapRegisterFlow[models.REGISTER_FLOW_NONE] && event.EventIsRecurring == false && instance.InstanceTotalSeats > 0:
-		return models.ErrorRegisterQuotaNotAvailable
-	case instanceAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_FULL]:
+		return false, models.ErrorRegistrationTimeDisabled
+	case isQuotaNotAvailable && !isWaitlistAllowed:
 		//if event.EventAllowedFor != "private" {
 		//	return models.ErrorRegisterQuotaNotAvailable
 		//}
 
-		return models.ErrorRegisterQuotaNotAvailable
+		return false, models.ErrorRegisterQuotaNotAvailable
 	//case instanceAvailableStatus == models.MapAvailabilityStatus[models.AVAILABILITY_STATUS_SOON]:
 	//	return models.ErrorCannotRegisterYet
 	case registerAt.Before(event.EventRegisterStartAt.In(common.GetLocation())):
-		return models.ErrorCannotRegisterYet
+		return false, models.ErrorCannotRegisterYet
 	case instance.InstanceMaxPerTransaction > 0 && countTotalRegistrants > instance.InstanceMaxPerTransaction:
-		return models.ErrorExceedMaxSeating
+		return false, models.ErrorExceedMaxSeating
 	}
 
 	switch {
 	case instance.InstanceIsOnePerAccount:
 		countRegistered, err := erru.r.EventRegistrationRecord.CountByCommunityIdOriginAndInstanceCode(ctx, common.StringTrimSpaceAndLower(request.CommunityId), common.StringTrimSpaceAndLower(request.InstanceCode))
 		if err != nil {
-			return err
+			return false, err
 		}
 		if countRegistered > 0 {
-			return models.ErrorEventCanOnlyRegisterOnce
+			return false, models.ErrorEventCanOnlyRegisterOnce
 		}
 	case instance.InstanceIsOnePerTicket:
 		if request.Identifier != "" && request.CommunityId == "" {
 			identifierExist, err := erru.r.EventRegistrationRecord.CheckByIdentifierAndInstanceCode(ctx, common.StringTrimSpaceAndLower(request.Identifier), common.StringTrimSpaceAndLower(request.InstanceCode))
 			if err != nil {
-				return err
+				return false, err
 			}
 			if identifierExist {
-				return models.ErrorAlreadyRegistered
+				return false, models.ErrorAlreadyRegistered
 			}
 		} else if request.Identifier == "" && request.CommunityId != "" {
 			communityIdExist, err := erru.r.EventRegistrationRecord.CheckByCommunityIdAndInstanceCode(ctx, request.CommunityId, common.StringTrimSpaceAndLower(request.InstanceCode))
 			if err != nil {
-				return err
+				return false, err
 			}
 			if communityIdExist {
-				return models.ErrorAlreadyRegistered
+				return false, models.ErrorAlreadyRegistered
 			}
 		} else {
-			return models.ErrorIdentifierCommunityIdEmpty
+			return false, models.ErrorIdentifierCommunityIdEmpty
 		}
 
 		if len(request.Registrants) > 0 {
 			for _, registrant := range request.Registrants {
 				nameExist, err := erru.r.EventRegistrationRecord.CheckByNameAndInstanceCode(ctx, common.StringTrimSpaceAndUpper(registrant.Name), common.StringTrimSpaceAndLower(request.InstanceCode))
 				if err != nil {
-					return err
+					return false, err
 				}
 				if nameExist {
-					return models.ErrorAlreadyRegistered
+					return false, models.ErrorAlreadyRegistered
 				}
 			}
 		}
 	case instance.InstanceIsOnePerTicket && instance.InstanceIsOnePerAccount:
 		countRegistered, err := erru.r.EventRegistrationRecord.CountByCommunityIdOriginAndInstanceCode(ctx, common.StringTrimSpaceAndLower(request.CommunityId), common.StringTrimSpaceAndLower(request.InstanceCode))
 		if err != nil {
-			return err
+			return false, err
 		}
 		if countRegistered > 0 {
-			return models.ErrorEventCanOnlyRegisterOnce
+			return false, models.ErrorEventCanOnlyRegisterOnce
 		}
 	default:
 		countRegistered, err := erru.r.EventRegistrationRecord.CountByIdentifierOriginAndStatus(ctx, common.StringTrimSpaceAndLower(request.Identifier), models.MapRegisterStatus[models.REGISTER_STATUS_PENDING])
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		if instance.InstanceMaxPerTransaction > 0 && ((int(countRegistered) + countTotalRegistrants) > instance.InstanceMaxPerTransaction) {
-			return models.ErrorExceedMaxSeating
+			return false, models.ErrorExceedMaxSeating
 		}
 
 		if request.IsPersonalQR && request.CommunityId != "" {
 			countRegistered, err := erru.r.EventRegistrationRecord.CountByCommunityIdOriginAndInstanceCode(ctx, common.StringTrimSpaceAndLower(request.CommunityId), common.StringTrimSpaceAndLower(request.InstanceCode))
 			if err != nil {
-				return err
+				return false, err
 			}
 
 			if instance.InstanceMaxPerTransaction > 0 && ((int(countRegistered) + countTotalRegistrants) > instance.InstanceMaxPerTransaction) {
-				return models.ErrorExceedMaxSeating
+				return false, models.ErrorExceedMaxSeating
 			}
 
 		}
 	}
 
-	return nil
+	return isQuotaNotAvailable && isWaitlistAllowed, nil
 }
 
 func (erru *eventRegistrationRecordUsecase) UpdateStatus(ctx context.Context, requestParam *models.UpdateRegistrationStatusParameter, requestBody *models.UpdateRegistrationStatusRequest, value *models.TokenValues) (response *models.UpdateRegistrationStatusResponse, err error) {
@@ -404,14 +575,23 @@ func (erru *eventRegistrationRecordUsecase) UpdateStatus(ctx context.Context, re
 		LogService(ctx, err)
 	}()
 
-	allowedRoles := []string{"event-verify-record"}
-	allowedUsers := []string{"admin", "superadmin", "usher", "volunteer"}
+	var ticket *authorization.TicketClaim
 	switch requestBody.Status {
-	case models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS]:
-		isAllowedRoles := common.CheckOneDataInList(allowedRoles, value.Roles)
-		isAllowedUsers := common.CheckOneDataInList(allowedUsers, value.UserTypes)
-		if !isAllowedRoles && !isAllowedUsers {
-			return nil, models.ErrorForbiddenRole
+	case models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS], models.MapRegisterStatus[models.REGISTER_STATUS_CHECKED_OUT]:
+		if err = erru.p.Authorize(ctx, models.PERMISSION_EVENT_REGISTRATION_VERIFY, ""); err != nil {
+			return nil, err
+		}
+
+		// With ticketing on, checking in or out is done by scanning the ticket, a registration id alone can be guessed or copied
+		if erru.t.Enabled() {
+			if requestBody.Ticket == "" {
+				return nil, models.ErrorTicketRequired
+			}
+
+			ticket, err = erru.t.ParseTicket(requestBody.Ticket)
+			if err != nil {
+				return nil, err
+			}
 		}
 	case models.MapRegisterStatus[models.REGISTER_STATUS_CANCELLED]:
 	//case models.MapRegisterStatus[models.REGISTER_STATUS_PENDING]:
@@ -429,6 +609,10 @@ func (erru *eventRegistrationRecordUsecase) UpdateStatus(ctx context.Context, re
 		return nil, models.ErrorDataNotFound
 	}
 
+	if ticket != nil && (ticket.Subject != record.ID.String() || ticket.InstanceCode != record.InstanceCode) {
+		return nil, models.ErrorInvalidTicket
+	}
+
 	instance, err := erru.r.EventInstance.GetSeatsNamesByCode(ctx, record.InstanceCode)
 	if err != nil {
 		return nil, err
@@ -447,19 +631,45 @@ func (erru *eventRegistrationRecordUsecase) UpdateStatus(ctx context.Context, re
 		return nil, models.ErrorRegistrationTimeDisabled
 	}
 
+	isCheckOut := requestBody.Status == models.MapRegisterStatus[models.REGISTER_STATUS_CHECKED_OUT]
+	if isCheckOut && !models.IsCheckOutAllowed(instance.CheckType) {
+		return nil, models.ErrorCheckOutNotAllowed
+	}
+
+	// Check-out usually happens after the session ends, so only the opening of the verify window applies
+	if isCheckOut && verifiedAt.Before(instance.InstanceAllowVerifyAt.In(common.GetLocation())) {
+		return nil, models.ErrorCannotRegisterYet
+	}
+
 	switch record.Status {
 	case models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS]:
-		return nil, models.ErrorAlreadyVerified
+		if !isCheckOut || instance.CheckType != models.MapCheckType[models.CHECK_TYPE_BOTH] {
+			return nil, models.ErrorAlreadyVerified
+		}
+
+		if record.VerifiedAt.Valid && verifiedAt.Before(record.VerifiedAt.Time) {
+			return nil, models.ErrorCheckOutBeforeCheckIn
+		}
+	case models.MapRegisterStatus[models.REGISTER_STATUS_CHECKED_OUT]:
+		return nil, models.ErrorAlreadyCheckedOut
 	case models.MapRegisterStatus[models.REGISTER_STATUS_CANCELLED]:
 		return nil, models.ErrorAlreadyCancelled
 	case models.MapRegisterStatus[models.REGISTER_STATUS_PENDING]:
+		// Instances checking both ways need a check-in first, so the attended duration can be measured
+		if isCheckOut && instance.CheckType == models.MapCheckType[models.CHECK_TYPE_BOTH] {
+			return nil, models.ErrorNotCheckedInYet
+		}
+	case models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED]:
+		if requestBody.Status != models.MapRegisterStatus[models.REGISTER_STATUS_CANCELLED] {
+			return nil, models.ErrorRegistrationWaitlisted
+		}
 	case models.MapRegisterStatus[models.REGISTER_STATUS_PERMIT]:
 		event, err := erru.r.Event.GetOneByCode(ctx, record.EventCode)
 		if err != nil {
 			return nil, err
 		}
 
-		if event.EventAllowedFor != "private" {
+		if event.EventAllowedFor != "private" || isCheckOut {
 			return nil, models.ErrorForbiddenStatus
 		}
 
@@ -471,31 +681,104 @@ func (erru *eventRegistrationRecordUsecase) UpdateStatus(ctx context.Context, re
 	}
 
 	res := models.UpdateRegistrationStatusResponse{}
+	var promoted []models.EventRegistrationRecord
+	previousStatus := record.Status
 	err = erru.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		// Every seat change of the instance goes through its lock, so the seats and the status read before it are
+		// read again under it and cannot change until the transaction ends
+		if err := r.EventInstance.LockByCode(ctx, record.InstanceCode); err != nil {
+			return err
+		}
+
+		current, err := r.EventRegistrationRecord.GetById(ctx, record.ID.String())
+		if err != nil {
+			return err
+		}
+
+		if current.Status != previousStatus {
+			return models.ErrorRegistrationChanged
+		}
+
+		if instance, err = r.EventInstance.GetSeatsNamesByCode(ctx, record.InstanceCode); err != nil {
+			return err
+		}
+
+		if instance == nil {
+			return models.ErrorDataNotFound
+		}
+
+		before := auditSnapshot(record)
 		record.Status = requestBody.Status
 		record.Reason = requestBody.Reason
-		record.VerifiedAt = sql.NullTime{Valid: true, Time: verifiedAt}
 		record.UpdatedBy = value.Id
+		if isCheckOut {
+			record.CheckedOutAt = sql.NullTime{Valid: true, Time: verifiedAt}
+		} else {
+			record.VerifiedAt = sql.NullTime{Valid: true, Time: verifiedAt}
+		}
 
 		if err := r.EventRegistrationRecord.Update(ctx, record); err != nil {
 			return err
 		}
 
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_EVENT_REGISTRATION_STATUS_UPDATE, models.TYPE_EVENT_REGISTRATION_RECORD, record.ID.String(), before, auditSnapshot(record)); err != nil {
+			return err
+		}
+
 		switch requestBody.Status {
 		case models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS]:
 			instance.ScannedSeats += 1
 			if err = r.EventInstance.UpdateScannedSeatsByCode(ctx, record.InstanceCode, instance); err != nil {
 				return err
 			}
+		case models.MapRegisterStatus[models.REGISTER_STATUS_CHECKED_OUT]:
+			// Check-out only instances never scanned the attendee in, so the check-out is also their attendance
+			if previousStatus == models.MapRegisterStatus[models.REGISTER_STATUS_PENDING] {
+				instance.ScannedSeats += 1
+			}
+
+			instance.CheckedOutSeats += 1
+			if err = r.EventInstance.UpdateCheckedOutSeatsByCode(ctx, record.InstanceCode, instance); err != nil {
+				return err
+			}
 		case models.MapRegisterStatus[models.REGISTER_STATUS_CANCELLED]:
+			// A waitlisted registration never held a seat, so there is nothing to release
+			if previousStatus == models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED] {
+				break
+			}
+
 			instance.BookedSeats -= 1
 			if err = r.EventInstance.UpdateBookedSeatsByCode(ctx, record.InstanceCode, instance); err != nil {
 				return err
 			}
+
+			promoted, err = promoteWaitlisted(ctx, r, record.InstanceCode, instance)
+			if err != nil {
+				return err
+			}
 		default:
 			return models.ErrorInvalidInput
 		}
 
+		promotedRes := make([]models.CreateOtherEventRegistrationRecordResponse, len(promoted))
+		for i, p := range promoted {
+			promotedRes[i] = models.CreateOtherEventRegistrationRecordResponse{
+				Type:   models.TYPE_EVENT_REGISTRATION_RECORD,
+				ID:     p.ID,
+				Name:   p.Name,
+				Status: models.MapRegisterStatus[models.REGISTER_STATUS_PENDING],
+			}
+		}
+
+		// Check-out only instances never check the attendee in, so they have no verified time
+		var checkedInAt, checkedOutAt *time.Time
+		if record.VerifiedAt.Valid {
+			checkedInAt = &record.VerifiedAt.Time
+		}
+		if record.CheckedOutAt.Valid {
+			checkedOutAt = &record.CheckedOutAt.Time
+		}
+
 		res = models.UpdateRegistrationStatusResponse{
 			Type:          models.TYPE_EVENT_REGISTRATION_RECORD,
 			ID:            record.ID,
@@ -508,14 +791,202 @@ func (erru *eventRegistrationRecordUsecase) UpdateStatus(ctx context.Context, re
 			InstanceCode:  record.InstanceCode,
 			InstanceTitle: instance.EventInstanceTitle,
 			UpdatedBy:     value.Id,
-			VerifiedAt:    verifiedAt,
+			VerifiedAt:    checkedInAt,
+			CheckedOutAt:  checkedOutAt,
+			Promoted:      promotedRes,
 		}
 
-		return nil
+		if requestBody.Status != models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS] {
+			return nil
+		}
+
+		return enqueueNotification(ctx, r, erru.n, notification.EventRegistrationVerified, record.Identifier, notification.RegistrationData{
+			Name:          record.Name,
+			EventTitle:    instance.EventTitle,
+			InstanceTitle: instance.EventInstanceTitle,
+			Status:        requestBody.Status,
+			At:            verifiedAt,
+		})
 	})
 	return &res, err
 }
 
+// SyncStatus applies scans that a scanner queued while offline. Every scan goes through UpdateStatus on its own,
+// oldest first, so a check-in recorded before a check-out is applied in that order. A failing scan is reported
+// on its own item instead of failing the whole batch, so the scanner can reconcile what it still holds.
+func (erru *eventRegistrationRecordUsecase) SyncStatus(ctx context.Context, request *models.SyncRegistrationStatusRequest, value *models.TokenValues) (response []models.SyncRegistrationStatusResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = erru.p.Authorize(ctx, models.PERMISSION_EVENT_REGISTRATION_VERIFY, ""); err != nil {
+		return nil, err
+	}
+
+	response = make([]models.SyncRegistrationStatusResponse, len(request.Scans))
+	scannedAt := make([]time.Time, len(request.Scans))
+	order := make([]int, 0, len(request.Scans))
+	for i, scan := range request.Scans {
+		response[i] = models.SyncRegistrationStatusResponse{
+			Type:      models.TYPE_EVENT_REGISTRATION_RECORD,
+			ID:        scan.ID,
+			Status:    scan.Status,
+			UpdatedAt: scan.UpdatedAt,
+		}
+
+		parsed, parseErr := common.ParseStringToDatetime(time.RFC3339, scan.UpdatedAt, common.GetLocation())
+		if parseErr != nil {
+			response[i].Result = models.MapSyncResult[models.SYNC_RESULT_REJECTED]
+			response[i].Reason = models.ErrorMapping(models.ErrorInvalidInput).Status
+			response[i].Message = models.ErrorInvalidInput.Error()
+			continue
+		}
+
+		scannedAt[i] = parsed
+		order = append(order, i)
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return scannedAt[order[a]].Before(scannedAt[order[b]])
+	})
+
+	applied := make(map[string]bool)
+	for _, i := range order {
+		scan := request.Scans[i]
+		key := fmt.Sprintf("%s|%s", scan.ID, scan.Status)
+		if applied[key] {
+			response[i].Result = models.MapSyncResult[models.SYNC_RESULT_DUPLICATE]
+			continue
+		}
+
+		_, err := erru.UpdateStatus(ctx, &models.UpdateRegistrationStatusParameter{ID: scan.ID}, &models.UpdateRegistrationStatusRequest{
+			Status:    scan.Status,
+			Reason:    scan.Reason,
+			UpdatedAt: scan.UpdatedAt,
+			Ticket:    scan.Ticket,
+		}, value)
+		switch {
+		case err == nil:
+			applied[key] = true
+			response[i].Result = models.MapSyncResult[models.SYNC_RESULT_APPLIED]
+		case errors.Is(err, models.ErrorAlreadyVerified), errors.Is(err, models.ErrorAlreadyCancelled), errors.Is(err, models.ErrorAlreadyCheckedOut):
+			response[i].Result = models.MapSyncResult[models.SYNC_RESULT_DUPLICATE]
+		default:
+			mapped := models.ErrorMapping(err)
+			response[i].Result = models.MapSyncResult[models.SYNC_RESULT_REJECTED]
+			response[i].Reason = mapped.Status
+			response[i].Message = mapped.Message
+		}
+	}
+
+	return response, nil
+}
+
+func (erru *eventRegistrationRecordUsecase) VerifyTicket(ctx context.Context, request *models.VerifyRegistrationTicketRequest, value *models.TokenValues) (response *models.VerifyRegistrationTicketResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = erru.p.Authorize(ctx, models.PERMISSION_EVENT_REGISTRATION_VERIFY, ""); err != nil {
+		return nil, err
+	}
+
+	claims, err := erru.t.ValidateTicket(request.Ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, models.ErrorInvalidTicket
+	}
+
+	return &models.VerifyRegistrationTicketResponse{
+		Type:         models.TYPE_EVENT_REGISTRATION_TICKET,
+		ID:           id,
+		InstanceCode: claims.InstanceCode,
+		ValidFrom:    claims.NotBefore.Time.In(common.GetLocation()),
+		ValidUntil:   claims.ExpiresAt.Time.In(common.GetLocation()),
+	}, nil
+}
+
+// issueTicket signs the QR ticket of a registration that can still be scanned, valid for the verify window of its instance.
+// Cancelled, waitlisted and permitted registrations have nothing to scan, so they get no ticket, nor does anyone while
+// ticketing is off.
+func issueTicket(t *authorization.Ticket, id uuid.UUID, instanceCode string, status string, validFrom time.Time, validUntil time.Time) (string, error) {
+	if !t.Enabled() {
+		return "", nil
+	}
+
+	switch status {
+	case models.MapRegisterStatus[models.REGISTER_STATUS_PENDING], models.MapRegisterStatus[models.REGISTER_STATUS_SUCCESS]:
+		return t.GenerateTicket(id.String(), instanceCode, validFrom, validUntil)
+	default:
+		return "", nil
+	}
+}
+
+// promoteWaitlisted moves the oldest waitlisted registration groups that still fit into the remaining seats
+// back to pending. A group is every record created by the same registration request, so a main registrant
+// is never promoted without the people they registered with. The instance has to be locked by the transaction of r,
+// see EventInstanceRepository.LockByCode, with its seats read after taking the lock.
+func promoteWaitlisted(ctx context.Context, r *pgsql.PostgreRepositories, instanceCode string, instance *models.GetSeatsAndNamesByInstanceCodeDBOutput) (promoted []models.EventRegistrationRecord, err error) {
+	if !instance.IsWaitlistEnabled || instance.TotalSeats == 0 {
+		return nil, nil
+	}
+
+	waitlisted, err := r.EventRegistrationRecord.GetManyByInstanceCodeAndStatus(ctx, instanceCode, models.MapRegisterStatus[models.REGISTER_STATUS_WAITLISTED])
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, group := range groupRegistrationRecords(waitlisted) {
+		if len(group) > instance.TotalSeats-instance.BookedSeats {
+			continue
+		}
+
+		for _, record := range group {
+			ids = append(ids, record.ID)
+		}
+
+		instance.BookedSeats += len(group)
+		promoted = append(promoted, group...)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if err = r.EventRegistrationRecord.UpdateStatusByIds(ctx, ids, models.MapRegisterStatus[models.REGISTER_STATUS_PENDING]); err != nil {
+		return nil, err
+	}
+
+	if err = r.EventInstance.UpdateBookedSeatsByCode(ctx, instanceCode, instance); err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+// groupRegistrationRecords splits records into the registration requests they were created by, keeping the input order.
+func groupRegistrationRecords(records []models.EventRegistrationRecord) [][]models.EventRegistrationRecord {
+	var groups [][]models.EventRegistrationRecord
+	index := make(map[string]int)
+	for _, record := range records {
+		key := fmt.Sprintf("%s|%s|%d", record.IdentifierOrigin, record.CommunityIdOrigin, record.RegisteredAt.UnixNano())
+		i, exist := index[key]
+		if !exist {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], record)
+	}
+
+	return groups
+}
+
 func (erru *eventRegistrationRecordUsecase) GetAttendance(ctx context.Context, request models.GetEventAttendanceParameter) (detail *models.GetEventAttendanceDetailResponse, list []models.GetEventAttendanceListResponse, err error) {
 	defer func() {
 		LogService(ctx, err)
@@ -578,6 +1049,11 @@ func (erru *eventRegistrationRecordUsecase) GetAllCursor(ctx context.Context, pa
 		LogService(ctx, err)
 	}()
 
+	params.CampusScope, err = scopeCampusFilter(ctx, params.CampusCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	output, prev, next, total, err := erru.r.EventRegistrationRecord.GetAllWithCursor(ctx, params)
 	if err != nil {
 		return nil, nil, err
@@ -669,23 +1145,51 @@ func (erru *eventRegistrationRecordUsecase) Download(ctx context.Context, param
 		LogService(ctx, err)
 	}()
 
+	param.CampusScope, err = scopeCampusFilter(ctx, param.CampusCode)
+	if err != nil {
+		return nil, "", "", err
+	}
+
 	record, err := erru.r.EventRegistrationRecord.Download(ctx, param)
 	if err != nil {
 		return nil, "", "", err
 	}
 
+	if len(record) == 0 {
+		return nil, "", "", models.ErrorDataNotFound
+	}
+
+	// Inactive and deleted questions are exported as well, since they may have been answered before they were turned off
+	questions, err := erru.r.EventQuestion.GetManyWithDeletedByEventCodeAndInstanceCode(ctx, param.EventCode, param.InstanceCode)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	answers, err := erru.r.EventAnswer.GetManyByInstanceCode(ctx, param.InstanceCode)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	answerMap := make(map[uuid.UUID]map[uuid.UUID]string)
+	for _, answer := range answers {
+		if answerMap[answer.RegistrationId] == nil {
+			answerMap[answer.RegistrationId] = make(map[uuid.UUID]string)
+		}
+		answerMap[answer.RegistrationId][answer.QuestionID] = answer.FormatAnswer()
+	}
+
 	fileName = fmt.Sprintf("%s-%s", record[0].EventName, record[0].InstanceName)
 
 	switch param.Format {
 	case "csv":
-		output, contentType, err := erru.downloadCSV(record)
+		output, contentType, err := erru.downloadCSV(record, questions, answerMap)
 		if err != nil {
 			return nil, "", "", err
 		}
 
 		return output, contentType, fmt.Sprintf("%s.csv", fileName), nil
 	case "xlsx":
-		output, contentType, err := erru.downloadXLSX(record)
+		output, contentType, err := erru.downloadXLSX(record, questions, answerMap)
 		if err != nil {
 			return nil, "", "", err
 		}
@@ -696,7 +1200,17 @@ func (erru *eventRegistrationRecordUsecase) Download(ctx context.Context, param
 	}
 }
 
-func (erru *eventRegistrationRecordUsecase) downloadXLSX(data []models.GetDownloadAllRegisteredDBOutput) (file []byte, contentType string, err error) {
+// downloadXLSX writes one row per registration, followed by one column per question holding the answer of that registration.
+// questionHeader is the column of the question in an export, marked when the question has been deleted since.
+func questionHeader(question models.EventQuestion) string {
+	if question.DeletedAt.Valid {
+		return question.Question + " (removed)"
+	}
+
+	return question.Question
+}
+
+func (erru *eventRegistrationRecordUsecase) downloadXLSX(data []models.GetDownloadAllRegisteredDBOutput, questions []models.EventQuestion, answers map[uuid.UUID]map[uuid.UUID]string) (file []byte, contentType string, err error) {
 	f := excelize.NewFile()
 	// Create a new Excel file
 	sheetName := "Registration-Record"
@@ -707,10 +1221,17 @@ func (erru *eventRegistrationRecordUsecase) downloadXLSX(data []models.GetDownlo
 
 	// Define headers
 	headers := []string{"ID", "Name", "Email/Phone Number", "Community ID", "Email", "Phone Number", "Campus", "COOL", "Department", "Event", "Event Instance", "Description", "Is Using QR", "Register At", "Verified At", "Status"}
+	for _, question := range questions {
+		headers = append(headers, questionHeader(question))
+	}
 
 	// Write headers to the first row
 	for i, header := range headers {
-		col := fmt.Sprintf("%c1", 'A'+i) // Convert index to column letter (A, B, C, etc.)
+		col, err := excelize.CoordinatesToCellName(i+1, 1) // Past Z the columns continue as AA, AB, etc.
+		if err != nil {
+			return nil, "", err
+		}
+
 		err = f.SetCellValue(sheetName, col, header)
 		if err != nil {
 			return nil, "", err
@@ -764,6 +1285,15 @@ func (erru *eventRegistrationRecordUsecase) downloadXLSX(data []models.GetDownlo
 		f.SetCellValue(sheetName, fmt.Sprintf("N%d", row), user.RegisteredAt)
 		f.SetCellValue(sheetName, fmt.Sprintf("O%d", row), verifiedAt)
 		f.SetCellValue(sheetName, fmt.Sprintf("P%d", row), user.Status)
+
+		// Answer columns start right after the Status column (P)
+		for j, question := range questions {
+			cell, err := excelize.CoordinatesToCellName(17+j, row)
+			if err != nil {
+				return nil, "", err
+			}
+			f.SetCellValue(sheetName, cell, answers[user.ID][question.ID])
+		}
 	}
 
 	buffer, err := f.WriteToBuffer()
@@ -774,12 +1304,15 @@ func (erru *eventRegistrationRecordUsecase) downloadXLSX(data []models.GetDownlo
 	return buffer.Bytes(), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
 }
 
-func (erru *eventRegistrationRecordUsecase) downloadCSV(data []models.GetDownloadAllRegisteredDBOutput) (file []byte, contentType string, err error) {
+func (erru *eventRegistrationRecordUsecase) downloadCSV(data []models.GetDownloadAllRegisteredDBOutput, questions []models.EventQuestion, answers map[uuid.UUID]map[uuid.UUID]string) (file []byte, contentType string, err error) {
 	buffer := bytes.NewBuffer(nil)
 	writer := csv.NewWriter(buffer)
 
 	// Define headers
 	headers := []string{"ID", "Name", "Email/Phone Number", "Community ID", "Email", "Phone Number", "Campus", "COOL", "Department", "Event", "Event Instance", "Description", "Is Using QR", "Register At", "Verified At", "Status"}
+	for _, question := range questions {
+		headers = append(headers, questionHeader(question))
+	}
 	if err := writer.Write(headers); err != nil {
 		return nil, "", err
 	}
@@ -833,6 +1366,10 @@ func (erru *eventRegistrationRecordUsecase) downloadCSV(data []models.GetDownloa
 			user.Status,
 		}
 
+		for _, question := range questions {
+			row = append(row, answers[user.ID][question.ID])
+		}
+
 		if err := writer.Write(row); err != nil {
 			return nil, "", err
 		}
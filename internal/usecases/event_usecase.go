@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"go-community/internal/common"
@@ -23,19 +24,23 @@ type EventUsecase interface {
 	GetRegistered(ctx context.Context, communityIdOrigin string) (eventRegistrations []models.GetAllRegisteredUserResponse, err error)
 	GetTitles(ctx context.Context) (eventTitles []models.GetEventTitlesResponse, err error)
 	GetSummary(ctx context.Context, code string) (detail *models.GetEventSummaryResponse, data []models.GetInstanceSummaryResponse, err error)
+	Update(ctx context.Context, request models.UpdateEventRequest) (response *models.UpdateEventResponse, err error)
+	Cancel(ctx context.Context, param models.CancelEventParameter, value *models.TokenValues) (response *models.CancelEventResponse, err error)
 }
 
 type eventUsecase struct {
 	cfg  *config.Configuration
 	a    authorization.Auth
+	t    authorization.Ticket
 	r    pgsql.PostgreRepositories
 	flag FeatureFlagUsecase
 }
 
-func NewEventUsecase(cfg config.Configuration, a authorization.Auth, r pgsql.PostgreRepositories, flag FeatureFlagUsecase) *eventUsecase {
+func NewEventUsecase(cfg config.Configuration, a authorization.Auth, t authorization.Ticket, r pgsql.PostgreRepositories, flag FeatureFlagUsecase) *eventUsecase {
 	return &eventUsecase{
 		cfg:  &cfg,
 		a:    a,
+		t:    t,
 		r:    r,
 		flag: flag,
 	}
@@ -65,57 +70,23 @@ func (eu *eventUsecase) Create(ctx context.Context, request models.CreateEventRe
 		return nil, models.ErrorAlreadyExist
 	}
 
-	var allowedRoles []string
-	var allowedUsers []string
-	var allowedCampuses []string
-	switch {
-	case request.AllowedFor == "public":
-		allowedRoles = nil
-		allowedUsers = nil
-		allowedCampuses = nil
-	case request.AllowedFor == "private" && request.AllowedCampuses != nil && request.AllowedRoles != nil && request.AllowedUsers != nil:
-		countRole, err := eu.r.Role.CheckMultiple(ctx, request.AllowedRoles)
-		if err != nil {
-			return nil, err
-		}
-
-		if int(countRole) != len(request.AllowedRoles) {
-			return nil, models.ErrorDataNotFound
-		}
-
-		countUserType, err := eu.r.UserType.CheckMultiple(ctx, request.AllowedUsers)
-		if err != nil {
-			return nil, err
-		}
-
-		if int(countUserType) != len(request.AllowedUsers) {
-			return nil, models.ErrorDataNotFound
-		}
-
-		for i, str := range request.AllowedCampuses {
-			request.AllowedCampuses[i] = strings.ToLower(str)
-		}
-
-		campusExist := common.CheckAllDataMapStructure(eu.cfg.Campus, request.AllowedCampuses)
-		if !campusExist {
-			return nil, models.ErrorDataNotFound
-		}
-
-		for i, str := range request.AllowedCampuses {
-			request.AllowedCampuses[i] = strings.ToUpper(str)
-		}
-
-		allowedRoles = request.AllowedRoles
-		allowedUsers = request.AllowedUsers
-		allowedCampuses = request.AllowedCampuses
-	default:
-		return nil, models.ErrorViolateAllowedForPrivate
+	allowedRoles, allowedUsers, allowedCampuses, err := eu.resolveAllowedFor(ctx, request.AllowedFor, request.AllowedRoles, request.AllowedUsers, request.AllowedCampuses)
+	if err != nil {
+		return nil, err
 	}
 
 	if eventStart.After(eventEnd) {
 		return nil, models.ErrorStartDateLater
 	}
 
+	if err := validateRecurrence(request.IsRecurring, request.Recurrence); err != nil {
+		return nil, err
+	}
+
+	if request.IsRecurring && len(request.Instances) == 0 {
+		return nil, models.ErrorInvalidRecurrence
+	}
+
 	event := models.Event{
 		Code:               eventCode,
 		Title:              request.Title,
@@ -175,6 +146,7 @@ func (eu *eventUsecase) Create(ctx context.Context, request models.CreateEventRe
 		} else {
 			instanceRequest.IsOnePerAccount = false
 			instanceRequest.IsOnePerTicket = false
+			instanceRequest.IsWaitlistEnabled = false
 			instanceRequest.RegisterFlow = models.MapRegisterFlow[models.REGISTER_FLOW_NONE]
 			instanceRequest.MaxPerTransaction = 0
 			instanceRequest.CheckType = "none"
@@ -197,6 +169,7 @@ func (eu *eventUsecase) Create(ctx context.Context, request models.CreateEventRe
 			MaxPerTransaction: instanceRequest.MaxPerTransaction,
 			IsOnePerAccount:   instanceRequest.IsOnePerAccount,
 			IsOnePerTicket:    instanceRequest.IsOnePerTicket,
+			IsWaitlistEnabled: instanceRequest.IsWaitlistEnabled,
 			RegisterFlow:      instanceRequest.RegisterFlow,
 			CheckType:         instanceRequest.CheckType,
 			TotalSeats:        instanceRequest.TotalSeats,
@@ -206,11 +179,33 @@ func (eu *eventUsecase) Create(ctx context.Context, request models.CreateEventRe
 		instances = append(instances, instance)
 	}
 
-	if err := eu.r.Event.Create(ctx, &event); err != nil {
-		return nil, err
+	// The first instance of a recurring event is the template every later occurrence is generated from
+	if request.IsRecurring {
+		instances[0].RecurrenceAt = sql.NullTime{Time: instances[0].InstanceStartAt, Valid: true}
 	}
 
-	if err = eu.r.EventInstance.BulkCreate(ctx, &instances); err != nil {
+	err = eu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.Event.Create(ctx, &event); err != nil {
+			return err
+		}
+
+		if err := r.EventInstance.BulkCreate(ctx, &instances); err != nil {
+			return err
+		}
+
+		if !request.IsRecurring {
+			return nil
+		}
+
+		generated, err := generateRecurringInstances(ctx, r, event, instances[0].InstanceStartAt, common.Now().Add(recurrenceHorizon(eu.cfg)))
+		if err != nil {
+			return err
+		}
+
+		instances = append(instances, generated...)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -233,6 +228,7 @@ func (eu *eventUsecase) Create(ctx context.Context, request models.CreateEventRe
 			MaxPerTransaction: p.MaxPerTransaction,
 			IsOnePerTicket:    p.IsOnePerTicket,
 			IsOnePerAccount:   p.IsOnePerAccount,
+			IsWaitlistEnabled: p.IsWaitlistEnabled,
 			RegisterFlow:      p.RegisterFlow,
 			CheckType:         p.CheckType,
 			TotalSeats:        p.TotalSeats,
@@ -438,6 +434,7 @@ func (eu *eventUsecase) GetByCode(ctx context.Context, code string, roles []stri
 				MaxPerTransaction:   p.InstanceMaxPerTransaction,
 				IsOnePerTicket:      p.InstanceIsOnePerTicket,
 				IsOnePerAccount:     p.InstanceIsOnePerAccount,
+				IsWaitlistEnabled:   p.InstanceIsWaitlistEnabled,
 				RegisterFlow:        p.InstanceRegisterFlow,
 				CheckType:           p.InstanceCheckType,
 				TotalSeats:          p.InstanceTotalSeats,
@@ -492,6 +489,7 @@ func (eu *eventUsecase) GetByCode(ctx context.Context, code string, roles []stri
 			MaxPerTransaction:   p.InstanceMaxPerTransaction,
 			IsOnePerTicket:      p.InstanceIsOnePerTicket,
 			IsOnePerAccount:     p.InstanceIsOnePerAccount,
+			IsWaitlistEnabled:   p.InstanceIsWaitlistEnabled,
 			RegisterFlow:        p.InstanceRegisterFlow,
 			CheckType:           p.InstanceCheckType,
 			TotalSeats:          p.InstanceTotalSeats,
@@ -572,6 +570,11 @@ func (eu *eventUsecase) GetRegistered(ctx context.Context, communityIdOrigin str
 			verifiedAt = common.FormatDatetimeToString(r.RegistrationRecordVerifiedAt.Time, time.RFC3339)
 		}
 
+		ticket, err := issueTicket(&eu.t, r.RegistrationRecordID, r.InstanceCode, r.RegistrationRecordStatus, r.InstanceAllowVerifyAt, r.InstanceDisallowVerifyAt)
+		if err != nil {
+			return nil, err
+		}
+
 		rr := models.UserRegisteredRecordsResponse{
 			Type:               models.TYPE_EVENT_REGISTRATION_RECORD,
 			ID:                 r.RegistrationRecordID,
@@ -583,6 +586,7 @@ func (eu *eventUsecase) GetRegistered(ctx context.Context, communityIdOrigin str
 			IsPersonalQr:       isPersonalQr,
 			VerifiedAt:         verifiedAt,
 			RegistrationStatus: r.RegistrationRecordStatus,
+			Ticket:             ticket,
 		}
 
 		eventExist := false
@@ -693,21 +697,304 @@ func (eu *eventUsecase) GetSummary(ctx context.Context, code string) (detail *mo
 		i.AttendancePercentage = float64(i.InstanceScannedSeats) / float64(event.TotalUsers) * 100
 
 		instanceRes = append(instanceRes, models.GetInstanceSummaryResponse{
-			Type:                models.TYPE_EVENT_INSTANCE,
-			EventCode:           event.EventCode,
-			Code:                i.InstanceCode,
-			Title:               i.InstanceTitle,
-			RegisterFlow:        i.InstanceRegisterFlow,
-			CheckType:           i.InstanceCheckType,
-			TotalSeats:          i.InstanceTotalSeats,
-			BookedSeats:         i.InstanceBookedSeats,
-			ScannedSeats:        i.InstanceScannedSeats,
-			TotalRemainingSeats: totalRemainingSeats,
-			AttendPercentage:    i.AttendancePercentage,
-			MaxPerTransaction:   i.InstanceMaxPerTransaction,
-			Status:              i.InstanceStatus,
+			Type:                   models.TYPE_EVENT_INSTANCE,
+			EventCode:              event.EventCode,
+			Code:                   i.InstanceCode,
+			Title:                  i.InstanceTitle,
+			RegisterFlow:           i.InstanceRegisterFlow,
+			CheckType:              i.InstanceCheckType,
+			TotalSeats:             i.InstanceTotalSeats,
+			BookedSeats:            i.InstanceBookedSeats,
+			ScannedSeats:           i.InstanceScannedSeats,
+			CheckedOutSeats:        i.InstanceCheckedOutSeats,
+			TotalRemainingSeats:    totalRemainingSeats,
+			AttendPercentage:       i.AttendancePercentage,
+			AverageAttendedMinutes: i.AverageAttendedMinutes,
+			MinAttendedMinutes:     i.MinAttendedMinutes,
+			MaxAttendedMinutes:     i.MaxAttendedMinutes,
+			FullSessionCount:       i.FullSessionCount,
+			MaxPerTransaction:      i.InstanceMaxPerTransaction,
+			Status:                 i.InstanceStatus,
 		})
 	}
 
 	return event.ToResponse(), instanceRes, nil
 }
+
+func (eu *eventUsecase) Update(ctx context.Context, request models.UpdateEventRequest) (response *models.UpdateEventResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	err = eu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		event, err := r.Event.GetByCode(ctx, request.Code)
+		if err != nil {
+			return err
+		}
+
+		if event.ID == 0 || event.DeletedAt.Valid {
+			return models.ErrorDataNotFound
+		}
+
+		if event.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+			return models.ErrorEventAlreadyCancelled
+		}
+
+		before := auditSnapshot(event)
+		if request.Title != nil {
+			event.Title = *request.Title
+		}
+
+		if request.Topics != nil {
+			event.Topics = request.Topics
+		}
+
+		if request.Description != nil {
+			event.Description = *request.Description
+		}
+
+		if request.TermsAndConditions != nil {
+			event.TermsAndConditions = *request.TermsAndConditions
+		}
+
+		if request.LocationType != nil {
+			event.LocationType = *request.LocationType
+		}
+
+		if request.LocationName != nil {
+			event.LocationName = *request.LocationName
+		}
+
+		if request.AllowedFor != nil || request.AllowedRoles != nil || request.AllowedUsers != nil || request.AllowedCampuses != nil {
+			allowedFor, allowedRoles, allowedUsers, allowedCampuses := event.AllowedFor, []string(event.AllowedRoles), []string(event.AllowedUsers), []string(event.AllowedCampuses)
+			if request.AllowedFor != nil {
+				allowedFor = *request.AllowedFor
+			}
+
+			if request.AllowedRoles != nil {
+				allowedRoles = request.AllowedRoles
+			}
+
+			if request.AllowedUsers != nil {
+				allowedUsers = request.AllowedUsers
+			}
+
+			if request.AllowedCampuses != nil {
+				allowedCampuses = request.AllowedCampuses
+			}
+
+			allowedRoles, allowedUsers, allowedCampuses, err = eu.resolveAllowedFor(ctx, allowedFor, allowedRoles, allowedUsers, allowedCampuses)
+			if err != nil {
+				return err
+			}
+
+			event.AllowedFor = allowedFor
+			event.AllowedRoles = allowedRoles
+			event.AllowedUsers = allowedUsers
+			event.AllowedCampuses = allowedCampuses
+		}
+
+		if event.EventStartAt, err = parseOptionalTime(request.EventStartAt, event.EventStartAt); err != nil {
+			return err
+		}
+
+		if event.EventEndAt, err = parseOptionalTime(request.EventEndAt, event.EventEndAt); err != nil {
+			return err
+		}
+
+		if event.RegisterStartAt, err = parseOptionalTime(request.RegisterStartAt, event.RegisterStartAt); err != nil {
+			return err
+		}
+
+		if event.RegisterEndAt, err = parseOptionalTime(request.RegisterEndAt, event.RegisterEndAt); err != nil {
+			return err
+		}
+
+		if event.EventStartAt.After(event.EventEndAt) || event.RegisterStartAt.After(event.RegisterEndAt) {
+			return models.ErrorStartDateLater
+		}
+
+		if request.EventStartAt != nil || request.EventEndAt != nil {
+			instances, err := r.EventInstance.GetAllByEventCodeAndStatus(ctx, event.Code, constants.MapStatus[constants.STATUS_ACTIVE])
+			if err != nil {
+				return err
+			}
+
+			for _, instance := range instances {
+				if !isInstanceWithinEvent(event, instance) {
+					return models.ErrorInstanceOutsideEvent
+				}
+			}
+		}
+
+		if err := r.Event.Update(ctx, &event); err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_EVENT_UPDATE, models.TYPE_EVENT, event.Code, before, auditSnapshot(event)); err != nil {
+			return err
+		}
+
+		response = event.ToUpdateResponse()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Cancel deactivates an event together with every instance it still has active, see cancelInstance.
+func (eu *eventUsecase) Cancel(ctx context.Context, param models.CancelEventParameter, value *models.TokenValues) (response *models.CancelEventResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if param.Reason == "" {
+		param.Reason = "event is cancelled"
+	}
+
+	err = eu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		event, err := r.Event.GetByCode(ctx, param.Code)
+		if err != nil {
+			return err
+		}
+
+		if event.ID == 0 || event.DeletedAt.Valid {
+			return models.ErrorDataNotFound
+		}
+
+		if event.Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+			return models.ErrorEventAlreadyCancelled
+		}
+
+		instances, err := r.EventInstance.GetAllByEventCodeAndStatus(ctx, event.Code, constants.MapStatus[constants.STATUS_ACTIVE])
+		if err != nil {
+			return err
+		}
+
+		res := models.CancelEventResponse{
+			Type:      models.TYPE_EVENT,
+			Code:      event.Code,
+			Title:     event.Title,
+			Instances: make([]models.CancelInstanceResponse, 0, len(instances)),
+		}
+
+		for i := range instances {
+			if err := r.EventInstance.LockByCode(ctx, instances[i].Code); err != nil {
+				return err
+			}
+
+			// Read again under the lock, the seats may have changed since they were listed
+			if instances[i], err = r.EventInstance.GetByCode(ctx, instances[i].Code); err != nil {
+				return err
+			}
+
+			if instances[i].Status != constants.MapStatus[constants.STATUS_ACTIVE] {
+				continue
+			}
+
+			cancelled, err := cancelInstance(ctx, r, &instances[i], param.Reason, value.Id)
+			if err != nil {
+				return err
+			}
+
+			res.TotalAffected += cancelled.TotalAffected
+			res.Instances = append(res.Instances, *cancelled)
+		}
+
+		previousStatus := event.Status
+		event.Status = constants.MapStatus[constants.STATUS_INACTIVE]
+		if err := r.Event.Update(ctx, &event); err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_EVENT_CANCEL, models.TYPE_EVENT, event.Code, map[string]interface{}{
+			"status": previousStatus,
+		}, map[string]interface{}{
+			"status":        event.Status,
+			"reason":        param.Reason,
+			"totalAffected": res.TotalAffected,
+		}); err != nil {
+			return err
+		}
+
+		res.Status = event.Status
+		response = &res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// resolveAllowedFor checks that the roles, user types and campuses of a private event exist,
+// and drops them for a public one since everyone is allowed anyway.
+func (eu *eventUsecase) resolveAllowedFor(ctx context.Context, allowedFor string, roles []string, userTypes []string, campuses []string) (allowedRoles []string, allowedUsers []string, allowedCampuses []string, err error) {
+	switch {
+	case allowedFor == "public":
+		return nil, nil, nil, nil
+	case allowedFor == "private" && campuses != nil && roles != nil && userTypes != nil:
+		countRole, err := eu.r.Role.CheckMultiple(ctx, roles)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if int(countRole) != len(roles) {
+			return nil, nil, nil, models.ErrorDataNotFound
+		}
+
+		countUserType, err := eu.r.UserType.CheckMultiple(ctx, userTypes)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if int(countUserType) != len(userTypes) {
+			return nil, nil, nil, models.ErrorDataNotFound
+		}
+
+		for i, str := range campuses {
+			campuses[i] = strings.ToLower(str)
+		}
+
+		campusExist := common.CheckAllDataMapStructure(eu.cfg.Campus, campuses)
+		if !campusExist {
+			return nil, nil, nil, models.ErrorDataNotFound
+		}
+
+		for i, str := range campuses {
+			campuses[i] = strings.ToUpper(str)
+		}
+
+		return roles, userTypes, campuses, nil
+	default:
+		return nil, nil, nil, models.ErrorViolateAllowedForPrivate
+	}
+}
+
+// parseOptionalTime parses an RFC3339 field of a partial update, keeping current when the field is not sent.
+func parseOptionalTime(value *string, current time.Time) (time.Time, error) {
+	if value == nil {
+		return current, nil
+	}
+
+	t, err := common.ParseStringToDatetime(time.RFC3339, *value, common.GetLocation())
+	if err != nil {
+		return time.Time{}, models.ErrorInvalidInput
+	}
+
+	return t, nil
+}
+
+// isInstanceWithinEvent checks that a session takes place within its event. A recurring event keeps generating
+// sessions after its end, so only their start is bound.
+func isInstanceWithinEvent(event models.Event, instance models.EventInstance) bool {
+	if instance.InstanceStartAt.Before(event.EventStartAt) {
+		return false
+	}
+
+	return event.IsRecurring || !instance.InstanceEndAt.After(event.EventEndAt)
+}
@@ -92,7 +92,13 @@ func (ffu *featureFlagUsecase) Create(ctx context.Context, request models.Featur
 		Rules:       rules,
 	}
 
-	err = ffu.r.FeatureFlag.Create(ctx, flag)
+	err = ffu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.FeatureFlag.Create(ctx, flag); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_FEATURE_FLAG_CREATE, models.TYPE_FEATURE_FLAG, flag.Key, nil, auditSnapshot(flag))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -115,20 +121,28 @@ func (ffu *featureFlagUsecase) Update(ctx context.Context, key string, request m
 		return nil, models.ErrorInvalidInput
 	}
 
-	flag, err := ffu.r.FeatureFlag.GetByKey(ctx, request.Key)
-	if err != nil {
-		return nil, models.ErrorDataNotFound
-	}
+	var flag models.FeatureFlag
+	err = ffu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		flag, err = r.FeatureFlag.GetByKey(ctx, request.Key)
+		if err != nil || flag.ID == 0 {
+			return models.ErrorDataNotFound
+		}
 
-	flag.Name = request.Name
-	flag.Key = request.Key
-	flag.Description = request.Description
-	flag.Enabled = request.Enabled
-	if request.Rules != nil {
-		flag.Rules = request.Rules
-	}
+		before := auditSnapshot(flag)
+		flag.Name = request.Name
+		flag.Key = request.Key
+		flag.Description = request.Description
+		flag.Enabled = request.Enabled
+		if request.Rules != nil {
+			flag.Rules = request.Rules
+		}
 
-	err = ffu.r.FeatureFlag.Update(ctx, &flag)
+		if err := r.FeatureFlag.Update(ctx, &flag); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_FEATURE_FLAG_UPDATE, models.TYPE_FEATURE_FLAG, flag.Key, before, auditSnapshot(flag))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -145,12 +159,44 @@ func (ffu *featureFlagUsecase) Update(ctx context.Context, key string, request m
 
 // ToggleFlag enables or disables a feature flag
 func (ffu *featureFlagUsecase) Toggle(ctx context.Context, key string, enabled bool) (err error) {
-	return ffu.r.FeatureFlag.Toggle(ctx, key, enabled)
+	return ffu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		flag, err := r.FeatureFlag.GetByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if flag.ID == 0 {
+			return models.ErrorDataNotFound
+		}
+
+		before := auditSnapshot(flag)
+		if err := r.FeatureFlag.Toggle(ctx, key, enabled); err != nil {
+			return err
+		}
+
+		flag.Enabled = enabled
+		return recordAudit(ctx, r, models.AUDIT_ACTION_FEATURE_FLAG_TOGGLE, models.TYPE_FEATURE_FLAG, key, before, auditSnapshot(flag))
+	})
 }
 
 // DeleteFlag removes a feature flag
 func (ffu *featureFlagUsecase) Delete(ctx context.Context, key string) error {
-	return ffu.r.FeatureFlag.Delete(ctx, key)
+	return ffu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		flag, err := r.FeatureFlag.GetByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if flag.ID == 0 {
+			return models.ErrorDataNotFound
+		}
+
+		if err := r.FeatureFlag.Delete(ctx, key); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_FEATURE_FLAG_DELETE, models.TYPE_FEATURE_FLAG, key, auditSnapshot(flag), nil)
+	})
 }
 
 // IsFeatureEnabled checks if a feature flag is enabled for a specific context
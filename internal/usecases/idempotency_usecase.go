@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"go-community/internal/common"
+	"go-community/internal/config"
+	"go-community/internal/models"
+	"go-community/internal/repositories/pgsql"
+	"time"
+)
+
+type IdempotencyUsecase interface {
+	Begin(ctx context.Context, key *models.IdempotencyKey) (stored *models.IdempotencyKey, err error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) (err error)
+	Release(ctx context.Context, key *models.IdempotencyKey) (err error)
+	DeleteExpired(ctx context.Context) (err error)
+}
+
+type idempotencyUsecase struct {
+	r   pgsql.PostgreRepositories
+	cfg config.Configuration
+}
+
+func NewIdempotencyUsecase(r pgsql.PostgreRepositories, cfg config.Configuration) *idempotencyUsecase {
+	return &idempotencyUsecase{
+		r:   r,
+		cfg: cfg,
+	}
+}
+
+// Begin claims the request id of the caller on the route. It returns nothing when the request has to be processed,
+// or the stored key whose response has to be replayed when the same request was processed before.
+func (iu *idempotencyUsecase) Begin(ctx context.Context, key *models.IdempotencyKey) (stored *models.IdempotencyKey, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	ttl := iu.cfg.Idempotency.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	lockTimeout := iu.cfg.Idempotency.LockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = time.Minute
+	}
+
+	now := common.Now()
+	key.LockedUntil = now.Add(lockTimeout)
+	key.ExpiresAt = now.Add(ttl)
+
+	acquired, err := iu.r.IdempotencyKey.Acquire(ctx, key, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if acquired {
+		return nil, nil
+	}
+
+	stored, err = iu.r.IdempotencyKey.Get(ctx, key.RequestId, key.Caller, key.Route)
+	if err != nil {
+		return nil, err
+	}
+
+	// Released by the request holding it in the meantime, which is about to be retried by someone else
+	if stored == nil {
+		return nil, models.ErrorProcessedRequestID
+	}
+
+	if stored.Fingerprint != key.Fingerprint {
+		return nil, models.ErrorReusedRequestID
+	}
+
+	if stored.Status != models.MapIdempotencyStatus[models.IDEMPOTENCY_STATUS_COMPLETED] {
+		return nil, models.ErrorProcessedRequestID
+	}
+
+	return stored, nil
+}
+
+// Complete stores the response of the request, so it is replayed to retries.
+func (iu *idempotencyUsecase) Complete(ctx context.Context, key *models.IdempotencyKey) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	return iu.r.IdempotencyKey.Complete(ctx, key, common.Now())
+}
+
+// Release forgets a request that failed, so a retry is processed again.
+func (iu *idempotencyUsecase) Release(ctx context.Context, key *models.IdempotencyKey) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	return iu.r.IdempotencyKey.Delete(ctx, key.RequestId, key.Caller, key.Route)
+}
+
+func (iu *idempotencyUsecase) DeleteExpired(ctx context.Context) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	return iu.r.IdempotencyKey.DeleteExpired(ctx, common.Now())
+}
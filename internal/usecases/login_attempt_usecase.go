@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-community/internal/common"
+	"go-community/internal/config"
+	"go-community/internal/models"
+	"go-community/internal/repositories/pgsql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginAttemptUsecase interface {
+	Unlock(ctx context.Context, request models.UnlockLoginRequest, value models.TokenValues) (response *models.UnlockLoginResponse, err error)
+}
+
+type loginAttemptUsecase struct {
+	r pgsql.PostgreRepositories
+}
+
+func NewLoginAttemptUsecase(r pgsql.PostgreRepositories) *loginAttemptUsecase {
+	return &loginAttemptUsecase{
+		r: r,
+	}
+}
+
+// Unlock lets the identifier or IP address log in again right away, ending its lockout and forgetting its failed logins.
+func (lau *loginAttemptUsecase) Unlock(ctx context.Context, request models.UnlockLoginRequest, value models.TokenValues) (response *models.UnlockLoginResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	response = &models.UnlockLoginResponse{
+		Type:       models.TYPE_LOGIN_UNLOCK,
+		Identifier: common.StringTrimSpaceAndLower(request.Identifier),
+		IpAddress:  request.IpAddress,
+	}
+
+	now := common.Now()
+	err = lau.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		for scope, key := range map[string]string{
+			models.MapLoginScope[models.LOGIN_SCOPE_IDENTIFIER]: response.Identifier,
+			models.MapLoginScope[models.LOGIN_SCOPE_IP]:         response.IpAddress,
+		} {
+			if key == "" {
+				continue
+			}
+
+			if err := r.LoginFailure.Delete(ctx, scope, key); err != nil {
+				return err
+			}
+
+			rows, err := r.LoginLockout.Unlock(ctx, scope, key, value.Id, now)
+			if err != nil {
+				return err
+			}
+			response.Unlocked += rows
+
+			if err := recordAudit(ctx, r, models.AUDIT_ACTION_LOGIN_LOCKOUT_UNLOCK, models.TYPE_LOGIN_LOCKOUT, loginLockoutEntityId(scope, key), nil, map[string]interface{}{"unlocked": rows}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type loginLimits struct {
+	maxFailures      int
+	maxFailuresPerIP int
+	window           time.Duration
+	lockoutDuration  time.Duration
+	maxDelay         time.Duration
+}
+
+func newLoginLimits(cfg config.Login) loginLimits {
+	limits := loginLimits{
+		maxFailures:      cfg.MaxFailures,
+		maxFailuresPerIP: cfg.MaxFailuresPerIP,
+		window:           cfg.Window,
+		lockoutDuration:  cfg.LockoutDuration,
+		maxDelay:         cfg.MaxDelay,
+	}
+
+	if limits.maxFailures <= 0 {
+		limits.maxFailures = 5
+	}
+
+	if limits.maxFailuresPerIP <= 0 {
+		limits.maxFailuresPerIP = 20
+	}
+
+	if limits.window <= 0 {
+		limits.window = 15 * time.Minute
+	}
+
+	if limits.lockoutDuration <= 0 {
+		limits.lockoutDuration = 15 * time.Minute
+	}
+
+	if limits.maxDelay <= 0 {
+		limits.maxDelay = 30 * time.Second
+	}
+
+	return limits
+}
+
+// delay is how long the next login has to wait after the given number of failures: nothing for the first two,
+// then one second doubling with every failure.
+func (l loginLimits) delay(failures int) time.Duration {
+	if failures < 3 {
+		return 0
+	}
+
+	delay := time.Second
+	for i := 3; i < failures && delay < l.maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, l.maxDelay)
+}
+
+// checkLoginAttempts rejects a login while the identifier or the IP address is locked out or still has to wait after failing.
+func checkLoginAttempts(ctx context.Context, r *pgsql.PostgreRepositories, identifier string, ipAddress string, now time.Time) error {
+	failures, err := r.LoginFailure.GetByIdentifierAndIp(ctx, identifier, ipAddress)
+	if err != nil {
+		return err
+	}
+
+	for _, failure := range failures {
+		if failure.LockedUntil.Valid && failure.LockedUntil.Time.After(now) {
+			return models.ErrorAccountLocked
+		}
+	}
+
+	for _, failure := range failures {
+		if failure.NextAttemptAt.After(now) {
+			return models.ErrorLoginTooSoon
+		}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the identifier and the IP address, and locks out the ones that
+// reach their limit. Every lockout is stored, so they can be looked back on.
+func recordLoginFailure(ctx context.Context, r *pgsql.PostgreRepositories, cfg config.Login, identifier string, ipAddress string, now time.Time) error {
+	limits := newLoginLimits(cfg)
+	scopes := []struct {
+		scope       string
+		value       string
+		maxFailures int
+	}{
+		{models.MapLoginScope[models.LOGIN_SCOPE_IDENTIFIER], identifier, limits.maxFailures},
+		{models.MapLoginScope[models.LOGIN_SCOPE_IP], ipAddress, limits.maxFailuresPerIP},
+	}
+
+	return r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		for _, s := range scopes {
+			if s.value == "" {
+				continue
+			}
+
+			failure, err := r.LoginFailure.Increment(ctx, s.scope, s.value, now, now.Add(-limits.window))
+			if err != nil {
+				return err
+			}
+
+			nextAttemptAt := now.Add(limits.delay(failure.Failures))
+			var lockedUntil sql.NullTime
+			if failure.Failures >= s.maxFailures {
+				lockedUntil = sql.NullTime{Time: now.Add(limits.lockoutDuration), Valid: true}
+				nextAttemptAt = lockedUntil.Time
+
+				lockout := models.LoginLockout{
+					ID:          uuid.New(),
+					Scope:       s.scope,
+					Value:       s.value,
+					Failures:    failure.Failures,
+					LockedUntil: lockedUntil.Time,
+				}
+				if err := r.LoginLockout.Create(ctx, &lockout); err != nil {
+					return err
+				}
+
+				if err := recordSystemAudit(ctx, r, models.AUDIT_ACTION_LOGIN_LOCKOUT_LOCK, models.TYPE_LOGIN_LOCKOUT, loginLockoutEntityId(s.scope, s.value), nil, auditSnapshot(lockout)); err != nil {
+					return err
+				}
+			}
+
+			if err := r.LoginFailure.UpdateDelay(ctx, s.scope, s.value, nextAttemptAt, lockedUntil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// loginLockoutEntityId is what the lockouts of an identifier or IP address are audited under.
+func loginLockoutEntityId(scope string, value string) string {
+	return fmt.Sprintf("%s:%s", scope, value)
+}
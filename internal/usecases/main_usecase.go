@@ -4,6 +4,7 @@ import (
 	"go-community/internal/config"
 	"go-community/internal/pkg/authorization"
 	"go-community/internal/pkg/google"
+	"go-community/internal/pkg/notification"
 	"go-community/internal/repositories/pgsql"
 )
 
@@ -11,6 +12,8 @@ type Dependencies struct {
 	Repository    *pgsql.PostgreRepositories
 	Google        *google.GoogleAuth
 	Authorization *authorization.Auth
+	Ticket        *authorization.Ticket
+	Notifier      *notification.Notifier
 	Salt          []byte
 	Config        *config.Configuration
 }
@@ -21,34 +24,56 @@ type Usecases struct {
 	CoolCategory            coolCategoryUsecase
 	Location                locationUsecase
 	User                    userUsecase
+	UserSession             userSessionUsecase
+	LoginAttempt            loginAttemptUsecase
 	EventCommunityRequest   eventCommunityRequestUsecase
 	Role                    roleUsecase
 	UserType                userTypeUsecase
 	Event                   eventUsecase
 	EventRegistrationRecord eventRegistrationRecordUsecase
 	EventInstance           eventInstanceUsecase
+	EventRecurrence         eventRecurrenceUsecase
+	EventQuestion           eventQuestionUsecase
 	FeatureFlag             featureFlagUsecase
 	Config                  configDBUsecase
 	Cool                    coolUsecase
 	CoolNewJoiner           coolNewJoinerUsecase
+	CoolMember              coolMemberUsecase
+	CoolMeeting             coolMeetingUsecase
+	Notification            notificationUsecase
+	Idempotency             idempotencyUsecase
+	Permission              permissionUsecase
+	Audit                   auditUsecase
 }
 
 func New(d Dependencies) *Usecases {
+	permission := NewPermissionUsecase(*d.Repository)
+
 	return &Usecases{
 		Health:                  *NewHealthUsecase(d.Repository.Health),
 		Campus:                  *NewCampusUsecase(d.Repository.Campus),
 		CoolCategory:            *NewCoolCategoryUsecase(d.Repository.CoolCategory),
 		Location:                *NewLocationUsecase(d.Repository.Location, d.Repository.Campus),
-		User:                    *NewUserUsecase(d.Repository.User, d.Repository.UserRelation, d.Repository.Campus, d.Repository.CoolCategory, d.Repository.Cool, d.Repository.UserType, d.Repository.Role, *d.Repository, *d.Config, *d.Authorization, d.Salt),
+		User:                    *NewUserUsecase(d.Repository.User, d.Repository.UserRelation, d.Repository.Campus, d.Repository.CoolCategory, d.Repository.Cool, d.Repository.UserType, d.Repository.Role, *d.Repository, *d.Config, *d.Authorization, d.Salt, d.Notifier, permission),
+		UserSession:             *NewUserSessionUsecase(*d.Repository, d.Authorization, permission),
+		LoginAttempt:            *NewLoginAttemptUsecase(*d.Repository),
 		EventCommunityRequest:   *NewEventCommunityRequestUsecase(d.Repository.EventCommunityRequest, d.Repository.User),
 		Role:                    *NewRoleUsecase(d.Repository.Role),
 		UserType:                *NewUserTypeUsecase(*d.Repository),
-		Event:                   *NewEventUsecase(*d.Config, *d.Authorization, *d.Repository, &featureFlagUsecase{r: *d.Repository}),
-		EventRegistrationRecord: *NewEventRegistrationRecordUsecase(*d.Repository, *d.Config),
+		Event:                   *NewEventUsecase(*d.Config, *d.Authorization, *d.Ticket, *d.Repository, &featureFlagUsecase{r: *d.Repository}),
+		EventRegistrationRecord: *NewEventRegistrationRecordUsecase(*d.Repository, *d.Config, *d.Ticket, d.Notifier, permission),
 		EventInstance:           *NewEventInstanceUsecase(*d.Config, *d.Authorization, *d.Repository),
+		EventRecurrence:         *NewEventRecurrenceUsecase(*d.Config, *d.Repository),
+		EventQuestion:           *NewEventQuestionUsecase(*d.Repository),
 		FeatureFlag:             *NewFeatureFlagUsecase(*d.Repository),
 		Config:                  *NewConfigDBUsecase(*d.Repository, *d.Config),
-		Cool:                    *NewCoolUsecase(*d.Repository, *d.Config, &featureFlagUsecase{r: *d.Repository}),
-		CoolNewJoiner:           *NewCoolNewJoinerUsecase(*d.Repository, d.Config, configDBUsecase{r: *d.Repository}),
+		Cool:                    *NewCoolUsecase(*d.Repository, *d.Config, &featureFlagUsecase{r: *d.Repository}, *NewConfigDBUsecase(*d.Repository, *d.Config)),
+		CoolNewJoiner:           *NewCoolNewJoinerUsecase(*d.Repository, d.Config, configDBUsecase{r: *d.Repository}, d.Notifier, d.Salt, permission),
+		CoolMember:              *NewCoolMemberUsecase(*d.Repository),
+		CoolMeeting:             *NewCoolMeetingUsecase(*d.Config, *d.Repository, permission),
+		Notification:            *NewNotificationUsecase(*d.Repository, *d.Config, d.Notifier),
+		Idempotency:             *NewIdempotencyUsecase(*d.Repository, *d.Config),
+		Permission:              *permission,
+		Audit:                   *NewAuditUsecase(*d.Repository),
 	}
 }
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"go-community/internal/common"
+	"go-community/internal/config"
+	"go-community/internal/models"
+	"go-community/internal/pkg/notification"
+	"go-community/internal/repositories/pgsql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultNotificationBatchSize   = 50
+	defaultNotificationMaxAttempts = 5
+
+	// notificationLease is how long a claimed notification is left to its worker before it is due again
+	notificationLease = 10 * time.Minute
+)
+
+type NotificationUsecase interface {
+	SendDue(ctx context.Context) (err error)
+}
+
+type notificationUsecase struct {
+	r   pgsql.PostgreRepositories
+	cfg config.Configuration
+	n   *notification.Notifier
+}
+
+func NewNotificationUsecase(r pgsql.PostgreRepositories, cfg config.Configuration, n *notification.Notifier) *notificationUsecase {
+	return &notificationUsecase{
+		r:   r,
+		cfg: cfg,
+		n:   n,
+	}
+}
+
+// SendDue sends the notifications waiting in the outbox. A failed notification is retried with a growing delay,
+// and given up on once it has been attempted max_attempts times. The body of a sensitive notification is cleared
+// once it is sent or given up on, so the credential in it does not stay in the outbox.
+func (nu *notificationUsecase) SendDue(ctx context.Context) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	batchSize := nu.cfg.Notification.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultNotificationBatchSize
+	}
+
+	maxAttempts := nu.cfg.Notification.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultNotificationMaxAttempts
+	}
+
+	now := common.Now()
+	notifications, err := nu.r.NotificationOutbox.ClaimDue(ctx, now, now.Add(notificationLease), batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range notifications {
+		sendErr := nu.n.Send(ctx, notification.Message{
+			Channel:   n.Channel,
+			Recipient: n.Recipient,
+			Subject:   n.Subject,
+			Body:      n.Body,
+		})
+
+		switch {
+		case sendErr == nil:
+			n.Status = models.MapNotificationStatus[models.NOTIFICATION_STATUS_SENT]
+			n.SentAt = sql.NullTime{Valid: true, Time: common.Now()}
+			n.LastError = ""
+		case n.Attempts >= maxAttempts:
+			n.Status = models.MapNotificationStatus[models.NOTIFICATION_STATUS_FAILED]
+			n.LastError = sendErr.Error()
+		default:
+			n.NextAttemptAt = common.Now().Add(notification.Backoff(n.Attempts))
+			n.LastError = sendErr.Error()
+		}
+
+		if n.Status != models.MapNotificationStatus[models.NOTIFICATION_STATUS_PENDING] && notification.IsSensitive(n.Event) {
+			n.Body = ""
+		}
+
+		if err = nu.r.NotificationOutbox.Update(ctx, &n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enqueueNotification renders the message of event and writes it to the outbox through r, so it is only sent once
+// the transaction r belongs to commits. Recipients no configured channel can reach are skipped.
+func enqueueNotification(ctx context.Context, r *pgsql.PostgreRepositories, n *notification.Notifier, event string, recipient string, data interface{}) error {
+	channel := n.ChannelFor(recipient)
+	if channel == "" {
+		return nil
+	}
+
+	subject, body, err := notification.Render(event, data)
+	if err != nil {
+		return err
+	}
+
+	return r.NotificationOutbox.Create(ctx, &models.NotificationOutbox{
+		ID:            uuid.New(),
+		Event:         event,
+		Channel:       channel,
+		Recipient:     recipient,
+		Subject:       subject,
+		Body:          body,
+		Status:        models.MapNotificationStatus[models.NOTIFICATION_STATUS_PENDING],
+		NextAttemptAt: common.Now(),
+	})
+}
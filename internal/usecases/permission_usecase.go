@@ -0,0 +1,306 @@
+package usecases
+
+import (
+	"context"
+	"go-community/internal/common"
+	"go-community/internal/models"
+	"go-community/internal/repositories/pgsql"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Granted permissions are cached for permissionCacheTTL, so a change made on another instance takes that long to apply there
+const permissionCacheTTL = time.Minute
+
+type PermissionUsecase interface {
+	Authorize(ctx context.Context, permission string, resource string) (err error)
+	AuthorizeGrants(ctx context.Context, roles []string, userTypes []string) (err error)
+	GetAll(ctx context.Context) (permissions []models.Permission, err error)
+	UpdateRolePermissions(ctx context.Context, param models.UpdateRolePermissionsParameter, request models.UpdatePermissionsRequest) (response *models.GrantedPermissionsResponse, err error)
+	UpdateUserTypePermissions(ctx context.Context, param models.UpdateUserTypePermissionsParameter, request models.UpdatePermissionsRequest) (response *models.GrantedPermissionsResponse, err error)
+}
+
+type permissionUsecase struct {
+	r     pgsql.PostgreRepositories
+	cache *permissionCache
+}
+
+type permissionCache struct {
+	mu          sync.RWMutex
+	granted     map[string]cachedPermissions
+	selfService map[string]bool
+	loadedAt    time.Time
+}
+
+type cachedPermissions struct {
+	permissions []string
+	loadedAt    time.Time
+}
+
+func NewPermissionUsecase(r pgsql.PostgreRepositories) *permissionUsecase {
+	return &permissionUsecase{
+		r:     r,
+		cache: &permissionCache{granted: make(map[string]cachedPermissions)},
+	}
+}
+
+// Authorize checks that the user of the access token in ctx has the permission. resource is the community id of the
+// owner of what is accessed, if anyone owns it; owners are always allowed self service permissions on their own.
+func (pu *permissionUsecase) Authorize(ctx context.Context, permission string, resource string) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	value, ok := models.TokenValuesFromContext(ctx)
+	if !ok {
+		return models.ErrorForbiddenRole
+	}
+
+	granted, err := pu.granted(ctx, value)
+	if err != nil {
+		return err
+	}
+
+	for _, grant := range granted {
+		if models.GrantCovers(grant, permission) {
+			return nil
+		}
+	}
+
+	if resource != "" && resource == value.Id {
+		selfService, err := pu.selfService(ctx)
+		if err != nil {
+			return err
+		}
+
+		if selfService[permission] {
+			return nil
+		}
+	}
+
+	return models.ErrorForbiddenRole
+}
+
+// AuthorizeGrants checks that the user of the access token in ctx holds every permission granted to the roles and user
+// types, so no one can give others more than they have themselves.
+func (pu *permissionUsecase) AuthorizeGrants(ctx context.Context, roles []string, userTypes []string) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	value, ok := models.TokenValuesFromContext(ctx)
+	if !ok {
+		return models.ErrorForbiddenRole
+	}
+
+	granted, err := pu.granted(ctx, value)
+	if err != nil {
+		return err
+	}
+
+	assigned, err := pu.r.Permission.GetGranted(ctx, roles, userTypes)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range assigned {
+		if !slices.ContainsFunc(granted, func(grant string) bool { return models.GrantCovers(grant, permission) }) {
+			return models.ErrorForbiddenRole
+		}
+	}
+
+	return nil
+}
+
+func (pu *permissionUsecase) GetAll(ctx context.Context) (permissions []models.Permission, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	return pu.r.Permission.GetAll(ctx)
+}
+
+// UpdateRolePermissions replaces what the role is granted.
+func (pu *permissionUsecase) UpdateRolePermissions(ctx context.Context, param models.UpdateRolePermissionsParameter, request models.UpdatePermissionsRequest) (response *models.GrantedPermissionsResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	permissions, err := pu.validateGrants(ctx, request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := common.StringTrimSpaceAndLower(param.Role)
+	err = pu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		before, err := r.Permission.GetGranted(ctx, []string{role}, nil)
+		if err != nil {
+			return err
+		}
+
+		rows, err := r.Permission.UpdateRolePermissions(ctx, role, permissions)
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return models.ErrorDataNotFound
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_ROLE_PERMISSIONS_UPDATE, models.TYPE_ROLE, role, grantsSnapshot(before), grantsSnapshot(permissions))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pu.cache.reset()
+
+	return &models.GrantedPermissionsResponse{
+		Type:        models.TYPE_ROLE,
+		Name:        role,
+		Permissions: permissions,
+	}, nil
+}
+
+// UpdateUserTypePermissions replaces what the user type is granted.
+func (pu *permissionUsecase) UpdateUserTypePermissions(ctx context.Context, param models.UpdateUserTypePermissionsParameter, request models.UpdatePermissionsRequest) (response *models.GrantedPermissionsResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	permissions, err := pu.validateGrants(ctx, request.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	userType := common.StringTrimSpaceAndLower(param.UserType)
+	err = pu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		before, err := r.Permission.GetGranted(ctx, nil, []string{userType})
+		if err != nil {
+			return err
+		}
+
+		rows, err := r.Permission.UpdateUserTypePermissions(ctx, userType, permissions)
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return models.ErrorDataNotFound
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_USER_TYPE_PERMISSIONS_UPDATE, models.TYPE_USER_TYPE, userType, grantsSnapshot(before), grantsSnapshot(permissions))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pu.cache.reset()
+
+	return &models.GrantedPermissionsResponse{
+		Type:        models.TYPE_USER_TYPE,
+		Name:        userType,
+		Permissions: permissions,
+	}, nil
+}
+
+// validateGrants only lets registered permissions be granted, or wildcards of registered resources.
+func (pu *permissionUsecase) validateGrants(ctx context.Context, grants []string) ([]string, error) {
+	registered, err := pu.r.Permission.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{models.PERMISSION_ALL: true}
+	for _, p := range registered {
+		known[p.Permission] = true
+		known[p.Resource+":*"] = true
+	}
+
+	permissions := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		grant = strings.TrimSpace(grant)
+		if !known[grant] {
+			return nil, models.ErrorInvalidInput
+		}
+
+		if !slices.Contains(permissions, grant) {
+			permissions = append(permissions, grant)
+		}
+	}
+
+	return permissions, nil
+}
+
+// grantsSnapshot is how grants are audited, sorted so only what was granted or taken away shows up as changed.
+func grantsSnapshot(grants []string) map[string]interface{} {
+	sorted := append([]string{}, grants...)
+	slices.Sort(sorted)
+
+	return map[string]interface{}{"permissions": sorted}
+}
+
+func (pu *permissionUsecase) granted(ctx context.Context, value models.TokenValues) ([]string, error) {
+	roles := slices.Clone(value.Roles)
+	userTypes := slices.Clone(value.UserTypes)
+	slices.Sort(roles)
+	slices.Sort(userTypes)
+	key := strings.Join(roles, ",") + "|" + strings.Join(userTypes, ",")
+
+	now := time.Now()
+	pu.cache.mu.RLock()
+	cached, exists := pu.cache.granted[key]
+	pu.cache.mu.RUnlock()
+	if exists && now.Sub(cached.loadedAt) < permissionCacheTTL {
+		return cached.permissions, nil
+	}
+
+	permissions, err := pu.r.Permission.GetGranted(ctx, roles, userTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	pu.cache.mu.Lock()
+	pu.cache.granted[key] = cachedPermissions{permissions: permissions, loadedAt: now}
+	pu.cache.mu.Unlock()
+
+	return permissions, nil
+}
+
+func (pu *permissionUsecase) selfService(ctx context.Context) (map[string]bool, error) {
+	now := time.Now()
+	pu.cache.mu.RLock()
+	selfService, loadedAt := pu.cache.selfService, pu.cache.loadedAt
+	pu.cache.mu.RUnlock()
+	if selfService != nil && now.Sub(loadedAt) < permissionCacheTTL {
+		return selfService, nil
+	}
+
+	registered, err := pu.r.Permission.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selfService = make(map[string]bool)
+	for _, p := range registered {
+		if p.SelfService {
+			selfService[p.Permission] = true
+		}
+	}
+
+	pu.cache.mu.Lock()
+	pu.cache.selfService, pu.cache.loadedAt = selfService, now
+	pu.cache.mu.Unlock()
+
+	return selfService, nil
+}
+
+func (c *permissionCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.granted = make(map[string]cachedPermissions)
+	c.selfService = nil
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"github.com/lib/pq"
 	"go-community/internal/models"
 	"go-community/internal/repositories/pgsql"
 	"strings"
@@ -39,6 +40,7 @@ func (ru *roleUsecase) Create(ctx context.Context, request *models.CreateRoleReq
 	input := models.Role{
 		Role:        strings.TrimSpace(strings.ToLower(request.Role)),
 		Description: request.Description,
+		Permissions: pq.StringArray{},
 	}
 
 	if err := ru.rr.Create(ctx, &input); err != nil {
@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"go-community/internal/common"
+	"go-community/internal/models"
+	"go-community/internal/pkg/authorization"
+	"go-community/internal/repositories/pgsql"
+
+	"github.com/google/uuid"
+)
+
+type UserSessionUsecase interface {
+	Refresh(ctx context.Context, request models.RefreshSessionRequest) (tokens *models.UserToken, err error)
+	Logout(ctx context.Context, sessionId string) (err error)
+	LogoutAll(ctx context.Context, value models.TokenValues) (err error)
+	GetAll(ctx context.Context, param models.GetUserSessionsParameter, value models.TokenValues) (response []models.UserSessionResponse, err error)
+	Delete(ctx context.Context, param models.DeleteUserSessionParameter, value models.TokenValues) (err error)
+}
+
+type userSessionUsecase struct {
+	r pgsql.PostgreRepositories
+	a *authorization.Auth
+	p *permissionUsecase
+}
+
+func NewUserSessionUsecase(r pgsql.PostgreRepositories, a *authorization.Auth, p *permissionUsecase) *userSessionUsecase {
+	return &userSessionUsecase{
+		r: r,
+		a: a,
+		p: p,
+	}
+}
+
+// Refresh rotates the session of the refresh token: the token is revoked and a new one is issued in the same family.
+// A refresh token that was already rotated means it has been copied, so the whole family is revoked.
+func (usu *userSessionUsecase) Refresh(ctx context.Context, request models.RefreshSessionRequest) (tokens *models.UserToken, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	// Refresh tokens issued before sessions were tracked have no id, so they cannot be refreshed anymore
+	id, err := uuid.Parse(request.SessionId)
+	if err != nil {
+		return nil, models.ErrorInvalidToken
+	}
+
+	session, err := usu.r.UserSession.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.ID == uuid.Nil || session.CommunityId != request.CommunityId {
+		return nil, models.ErrorInvalidToken
+	}
+
+	now := common.Now()
+	if session.RevokedAt.Valid {
+		if session.ReplacedBy.Valid {
+			if _, err = usu.r.UserSession.RevokeByFamilyId(ctx, session.FamilyId, models.MapSessionRevokedReason[models.SESSION_REVOKED_REUSED], now); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, models.ErrorLoggedOut
+	}
+
+	if session.ExpiresAt.Before(now) {
+		return nil, models.ErrorExpiredToken
+	}
+
+	user, err := usu.r.User.GetRBAC(ctx, session.CommunityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.CommunityId == "" {
+		return nil, models.ErrorUserNotFound
+	}
+
+	roles := common.UniqueArray(common.CombineMapStrings(user.CombinedRoles, user.Roles))
+	err = usu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		next := uuid.New()
+		rows, err := r.UserSession.Rotate(ctx, session.ID, next, now)
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return models.ErrorLoggedOut
+		}
+
+		tokens, err = startSession(ctx, r, usu.a, session.CommunityId, user.UserTypes, roles, user.CampusScopes, next, session.FamilyId, request.Client)
+		return err
+	})
+	if errors.Is(err, models.ErrorLoggedOut) {
+		// Another request rotated the token in the meantime, which is a reuse as well
+		if _, revokeErr := usu.r.UserSession.RevokeByFamilyId(ctx, session.FamilyId, models.MapSessionRevokedReason[models.SESSION_REVOKED_REUSED], now); revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the session of the refresh token on the device it was issued to.
+func (usu *userSessionUsecase) Logout(ctx context.Context, sessionId string) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	id, err := uuid.Parse(sessionId)
+	if err != nil {
+		return nil
+	}
+
+	session, err := usu.r.UserSession.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if session.ID == uuid.Nil {
+		return nil
+	}
+
+	_, err = usu.r.UserSession.RevokeByFamilyId(ctx, session.FamilyId, models.MapSessionRevokedReason[models.SESSION_REVOKED_LOGOUT], common.Now())
+	return err
+}
+
+// LogoutAll revokes every session of the user. Access tokens already issued stay valid until they expire.
+func (usu *userSessionUsecase) LogoutAll(ctx context.Context, value models.TokenValues) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	return usu.r.UserSession.RevokeByCommunityId(ctx, value.Id, uuid.Nil, models.MapSessionRevokedReason[models.SESSION_REVOKED_LOGOUT_ALL], common.Now())
+}
+
+// GetAll lists the sessions the user is logged in with, one per device.
+func (usu *userSessionUsecase) GetAll(ctx context.Context, param models.GetUserSessionsParameter, value models.TokenValues) (response []models.UserSessionResponse, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = usu.p.Authorize(ctx, models.PERMISSION_USER_SESSION_MANAGE, param.CommunityId); err != nil {
+		return nil, err
+	}
+
+	sessions, err := usu.r.UserSession.GetActiveByCommunityId(ctx, param.CommunityId, common.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]models.UserSessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = session.ToResponse(value.SessionId)
+	}
+
+	return response, nil
+}
+
+// Delete logs the device of the session out, its refresh token cannot be used anymore.
+func (usu *userSessionUsecase) Delete(ctx context.Context, param models.DeleteUserSessionParameter, value models.TokenValues) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if err = usu.p.Authorize(ctx, models.PERMISSION_USER_SESSION_MANAGE, param.CommunityId); err != nil {
+		return err
+	}
+
+	rows, err := usu.r.UserSession.RevokeByCommunityIdAndFamilyId(ctx, param.CommunityId, uuid.MustParse(param.SessionId), models.MapSessionRevokedReason[models.SESSION_REVOKED_REMOVED], common.Now())
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrorDataNotFound
+	}
+
+	return nil
+}
+
+// startSession stores the session of a new refresh token and issues the tokens for it.
+func startSession(ctx context.Context, r *pgsql.PostgreRepositories, a *authorization.Auth, communityId string, userTypes []string, roles []string, campuses []string, id uuid.UUID, familyId uuid.UUID, client models.SessionClient) (*models.UserToken, error) {
+	tokens, err := a.GenerateTokens(communityId, userTypes, roles, campuses, familyId.String(), id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.UserSession.Create(ctx, &models.UserSession{
+		ID:          id,
+		FamilyId:    familyId,
+		CommunityId: communityId,
+		UserAgent:   client.UserAgent,
+		IpAddress:   client.IpAddress,
+		ExpiresAt:   tokens.RefreshExpiry,
+	}); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
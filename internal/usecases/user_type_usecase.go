@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"github.com/lib/pq"
 	"go-community/internal/models"
 	"go-community/internal/repositories/pgsql"
 	"strings"
@@ -49,6 +50,7 @@ func (utu *userTypeUsecase) Create(ctx context.Context, request *models.CreateUs
 		Type:        strings.TrimSpace(strings.ToLower(request.UserType)),
 		Name:        strings.TrimSpace(request.Name),
 		Roles:       request.Roles,
+		Permissions: pq.StringArray{},
 		Description: request.Description,
 		Category:    request.Category,
 	}
@@ -10,10 +10,15 @@ import (
 	"go-community/internal/pkg/authorization"
 	"go-community/internal/pkg/generator"
 	"go-community/internal/pkg/hash"
+	"go-community/internal/pkg/notification"
 	"go-community/internal/repositories/pgsql"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -23,7 +28,9 @@ type UserUsecase interface {
 	Login(ctx context.Context, request models.LoginUserRequest) (user *models.User, token string, err error)
 	GetByCommunityId(ctx context.Context, request models.GetOneByCommunityIdParameter) (response *models.GetOneByCommunityIdResponse, err error)
 	Check(ctx context.Context, identifier string) (isExist bool, err error)
-	UpdatePassword(ctx context.Context, param *models.UpdateUserPasswordParam, request *models.UpdateUserPasswordRequest) (user *models.User, err error)
+	ForgotPassword(ctx context.Context, request models.ForgotPasswordRequest) (err error)
+	ResetPassword(ctx context.Context, request models.ResetPasswordRequest) (user *models.User, err error)
+	ChangePassword(ctx context.Context, request models.ChangePasswordRequest, value models.TokenValues) (user *models.User, err error)
 	GetAllCursor(ctx context.Context, params models.GetAllUserCursorParam) (res []models.GetAllUserCursorResponse, info *models.CursorInfo, err error)
 	UpdateRolesOrUserType(ctx context.Context, request *models.UpdateRolesOrUserTypesRequest) (res *models.UpdateRolesOrUserTypesResponse, err error)
 	UpdateProfile(ctx context.Context, parameter models.UpdateProfileParameter, request models.UpdateProfileRequest, value models.TokenValues) (response *models.UpdateProfileResponse, err error)
@@ -45,9 +52,11 @@ type userUsecase struct {
 	cfg *config.Configuration
 	a   authorization.Auth
 	s   []byte
+	n   *notification.Notifier
+	p   *permissionUsecase
 }
 
-func NewUserUsecase(ur pgsql.UserRepository, urr pgsql.UserRelationRepository, cr pgsql.CampusRepository, ccr pgsql.CoolCategoryRepository, clr pgsql.CoolRepository, utr pgsql.UserTypeRepository, rr pgsql.RoleRepository, r pgsql.PostgreRepositories, cfg config.Configuration, a authorization.Auth, s []byte) *userUsecase {
+func NewUserUsecase(ur pgsql.UserRepository, urr pgsql.UserRelationRepository, cr pgsql.CampusRepository, ccr pgsql.CoolCategoryRepository, clr pgsql.CoolRepository, utr pgsql.UserTypeRepository, rr pgsql.RoleRepository, r pgsql.PostgreRepositories, cfg config.Configuration, a authorization.Auth, s []byte, n *notification.Notifier, p *permissionUsecase) *userUsecase {
 	return &userUsecase{
 		ur:  ur,
 		urr: urr,
@@ -60,6 +69,8 @@ func NewUserUsecase(ur pgsql.UserRepository, urr pgsql.UserRelationRepository, c
 		cfg: &cfg,
 		a:   a,
 		s:   s,
+		n:   n,
+		p:   p,
 	}
 }
 
@@ -160,6 +171,10 @@ func (uu *userUsecase) Create(ctx context.Context, request *models.CreateUserReq
 			return nil, err
 		}
 
+		if err := syncCoolMember(ctx, &uu.r, userExist.CommunityID, userExist.CoolID); err != nil {
+			return nil, err
+		}
+
 		response = &models.CreateUserResponse{
 			Type:           models.TYPE_USER,
 			CommunityId:    userExist.CommunityID,
@@ -232,6 +247,10 @@ func (uu *userUsecase) Create(ctx context.Context, request *models.CreateUserReq
 			return nil, err
 		}
 
+		if err := syncCoolMember(ctx, &uu.r, input.CommunityID, input.CoolID); err != nil {
+			return nil, err
+		}
+
 		response = &models.CreateUserResponse{
 			Type:           models.TYPE_USER,
 			CommunityId:    input.CommunityID,
@@ -359,6 +378,10 @@ func (uu *userUsecase) CreateVolunteer(ctx context.Context, request *models.Crea
 		}
 
 		input.CommunityID = userExist.CommunityID
+		if err := syncCoolMember(ctx, &uu.r, input.CommunityID, input.CoolID); err != nil {
+			return nil, err
+		}
+
 		return &input, nil
 	}
 
@@ -410,6 +433,10 @@ func (uu *userUsecase) CreateVolunteer(ctx context.Context, request *models.Crea
 		return nil, err
 	}
 
+	if err := syncCoolMember(ctx, &uu.r, input.CommunityID, input.CoolID); err != nil {
+		return nil, err
+	}
+
 	return &input, nil
 }
 
@@ -418,20 +445,39 @@ func (uu *userUsecase) Login(ctx context.Context, request *models.LoginUserReque
 		LogService(ctx, err)
 	}()
 
-	user, err := uu.ur.GetOneByIdentifier(ctx, common.StringTrimSpaceAndLower(request.Identifier))
+	identifier := common.StringTrimSpaceAndLower(request.Identifier)
+	now := common.Now()
+	if err = checkLoginAttempts(ctx, &uu.r, identifier, request.Client.IpAddress, now); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := uu.ur.GetOneByIdentifier(ctx, identifier)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Unknown identifiers count as failures as well, so guessing them is throttled the same way
 	if user.ID == 0 {
+		if err = recordLoginFailure(ctx, &uu.r, uu.cfg.Login, identifier, request.Client.IpAddress, now); err != nil {
+			return nil, nil, err
+		}
+
 		return nil, nil, models.ErrorUserNotFound
 	}
 
 	salted := append([]byte(request.Password), uu.s...)
 	if err = hash.Validate(user.Password, string(salted)); err != nil {
+		if err = recordLoginFailure(ctx, &uu.r, uu.cfg.Login, identifier, request.Client.IpAddress, now); err != nil {
+			return nil, nil, err
+		}
+
 		return nil, nil, models.ErrorInvalidPassword
 	}
 
+	if err = uu.r.LoginFailure.Delete(ctx, models.MapLoginScope[models.LOGIN_SCOPE_IDENTIFIER], identifier); err != nil {
+		return nil, nil, err
+	}
+
 	userType, err := uu.utr.GetByArray(ctx, user.UserTypes)
 	if err != nil {
 		return nil, nil, err
@@ -444,7 +490,7 @@ func (uu *userUsecase) Login(ctx context.Context, request *models.LoginUserReque
 
 	userRoles := common.CombineMapStrings(rolesInUserType, user.Roles)
 	user.Roles = userRoles
-	tokens, err = uu.a.GenerateTokens(user.CommunityID, user.UserTypes, userRoles)
+	tokens, err = startSession(ctx, &uu.r, &uu.a, user.CommunityID, user.UserTypes, userRoles, user.CampusScopes, uuid.New(), uuid.New(), request.Client)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -556,7 +602,68 @@ func (uu *userUsecase) Check(ctx context.Context, identifier string) (isExist bo
 	return isExist, nil
 }
 
-func (uu *userUsecase) UpdatePassword(ctx context.Context, param *models.UpdateUserPasswordParam, request *models.UpdateUserPasswordRequest) (user *models.User, err error) {
+// ForgotPassword sends a reset token to the identifier when it belongs to a user. Unknown identifiers are not reported,
+// so the endpoint cannot be used to find out who has an account.
+func (uu *userUsecase) ForgotPassword(ctx context.Context, request models.ForgotPasswordRequest) (err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	// The channel only depends on the kind of identifier, so this tells nothing about whether it has an account
+	identifier := strings.ToLower(request.Identifier)
+	if uu.n.ChannelFor(identifier) == "" {
+		return models.ErrorResetUnavailable
+	}
+
+	user, err := uu.ur.GetOneByIdentifier(ctx, identifier)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == 0 {
+		return nil
+	}
+
+	token, err := generator.SecretToken()
+	if err != nil {
+		return err
+	}
+
+	duration := uu.cfg.Auth.PasswordResetDuration
+	if duration <= 0 {
+		duration = 30
+	}
+
+	now := common.Now()
+	data := notification.PasswordResetData{
+		Name:    user.Name,
+		Token:   token,
+		Minutes: duration,
+	}
+
+	if uu.cfg.Auth.PasswordResetURL != "" {
+		data.URL = uu.cfg.Auth.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	}
+
+	return uu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.PasswordReset.RevokeByCommunityId(ctx, user.CommunityID, now); err != nil {
+			return err
+		}
+
+		if err := r.PasswordReset.Create(ctx, &models.PasswordResetToken{
+			ID:          uuid.New(),
+			CommunityId: user.CommunityID,
+			TokenHash:   hash.Token(token),
+			ExpiresAt:   now.Add(time.Duration(duration) * time.Minute),
+		}); err != nil {
+			return err
+		}
+
+		return enqueueNotification(ctx, r, uu.n, notification.EventPasswordResetRequested, identifier, data)
+	})
+}
+
+func (uu *userUsecase) ResetPassword(ctx context.Context, request models.ResetPasswordRequest) (user *models.User, err error) {
 	defer func() {
 		LogService(ctx, err)
 	}()
@@ -565,23 +672,100 @@ func (uu *userUsecase) UpdatePassword(ctx context.Context, param *models.UpdateU
 		return nil, models.ErrorMismatchFields
 	}
 
-	data, err := uu.ur.GetOneByIdentifier(ctx, strings.ToLower(param.Identifier))
+	now := common.Now()
+	token, err := uu.r.PasswordReset.GetActiveByTokenHash(ctx, hash.Token(request.Token), now)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ID == uuid.Nil {
+		return nil, models.ErrorInvalidResetToken
+	}
+
+	data, err := uu.ur.GetByCommunityId(ctx, token.CommunityId)
 	if err != nil {
 		return nil, err
 	}
 
 	if data.ID == 0 {
-		return nil, models.ErrorDataNotFound
+		return nil, models.ErrorInvalidResetToken
 	}
 
-	salted := append([]byte(request.Password), uu.s...)
-	password, err := hash.Generate(salted)
+	password, err := hash.Generate(append([]byte(request.Password), uu.s...))
 	if err != nil {
 		return nil, err
 	}
 
 	data.Password = password
-	if err := uu.ur.Update(ctx, &data); err != nil {
+	err = uu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		rows, err := r.PasswordReset.MarkUsed(ctx, token.ID, now)
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return models.ErrorInvalidResetToken
+		}
+
+		if err := r.PasswordReset.RevokeByCommunityId(ctx, data.CommunityID, now); err != nil {
+			return err
+		}
+
+		if err := r.UserSession.RevokeByCommunityId(ctx, data.CommunityID, uuid.Nil, models.MapSessionRevokedReason[models.SESSION_REVOKED_PASSWORD], now); err != nil {
+			return err
+		}
+
+		return r.User.Update(ctx, &data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (uu *userUsecase) ChangePassword(ctx context.Context, request models.ChangePasswordRequest, value models.TokenValues) (user *models.User, err error) {
+	defer func() {
+		LogService(ctx, err)
+	}()
+
+	if request.CommunityId != value.Id {
+		return nil, models.ErrorDifferentCommunityId
+	}
+
+	if request.Password != request.ConfirmPassword {
+		return nil, models.ErrorMismatchFields
+	}
+
+	data, err := uu.ur.GetByCommunityId(ctx, request.CommunityId)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.ID == 0 {
+		return nil, models.ErrorUserNotFound
+	}
+
+	if err = hash.Validate(data.Password, string(append([]byte(request.CurrentPassword), uu.s...))); err != nil {
+		return nil, models.ErrorInvalidPassword
+	}
+
+	password, err := hash.Generate(append([]byte(request.Password), uu.s...))
+	if err != nil {
+		return nil, err
+	}
+
+	// The session the password is changed from stays logged in, every other one has to log in again
+	currentSession, _ := uuid.Parse(value.SessionId)
+	data.Password = password
+	err = uu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.UserSession.RevokeByCommunityId(ctx, data.CommunityID, currentSession, models.MapSessionRevokedReason[models.SESSION_REVOKED_PASSWORD], common.Now()); err != nil {
+			return err
+		}
+
+		return r.User.Update(ctx, &data)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -593,6 +777,11 @@ func (uu *userUsecase) GetAllCursor(ctx context.Context, params models.GetAllUse
 		LogService(ctx, err)
 	}()
 
+	params.CampusScope, err = scopeCampusFilter(ctx, params.CampusCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	output, prev, next, total, err := uu.ur.GetAllWithCursor(ctx, params)
 	if err != nil {
 		return nil, nil, err
@@ -675,6 +864,7 @@ func (uu *userUsecase) UpdateRolesOrUserType(ctx context.Context, request *model
 		return nil, models.ErrorDataNotFound
 	}
 
+	var action string
 	switch request.Field {
 	case "role":
 		countRole, err := uu.rr.CheckMultiple(ctx, request.Changes)
@@ -686,9 +876,11 @@ func (uu *userUsecase) UpdateRolesOrUserType(ctx context.Context, request *model
 			return nil, models.ErrorDataNotFound
 		}
 
-		if err := uu.ur.BulkUpdateRolesByCommunityIds(ctx, request.CommunityIds, request.Changes); err != nil {
+		if err := uu.p.AuthorizeGrants(ctx, request.Changes, nil); err != nil {
 			return nil, err
 		}
+
+		action = models.AUDIT_ACTION_USER_ROLES_UPDATE
 	case "userType":
 		countUserType, err := uu.utr.CheckMultiple(ctx, request.Changes)
 		if err != nil {
@@ -699,13 +891,57 @@ func (uu *userUsecase) UpdateRolesOrUserType(ctx context.Context, request *model
 			return nil, models.ErrorDataNotFound
 		}
 
-		if err := uu.ur.BulkUpdateUserTypesByCommunityIds(ctx, request.CommunityIds, request.Changes); err != nil {
+		if err := uu.p.AuthorizeGrants(ctx, nil, request.Changes); err != nil {
 			return nil, err
 		}
+
+		action = models.AUDIT_ACTION_USER_USER_TYPES_UPDATE
 	default:
 		return nil, fmt.Errorf("should be one of userType or role")
 	}
 
+	err = uu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		before := make(map[string]map[string]interface{}, len(request.CommunityIds))
+		for _, communityId := range request.CommunityIds {
+			user, err := r.User.GetOneByCommunityId(ctx, communityId)
+			if err != nil {
+				return err
+			}
+
+			if !inCampusScope(ctx, user.CampusCode) {
+				return models.ErrorOutOfCampusScope
+			}
+
+			before[communityId] = auditSnapshot(user)
+		}
+
+		if action == models.AUDIT_ACTION_USER_ROLES_UPDATE {
+			if err := r.User.BulkUpdateRolesByCommunityIds(ctx, request.CommunityIds, request.Changes); err != nil {
+				return err
+			}
+		} else {
+			if err := r.User.BulkUpdateUserTypesByCommunityIds(ctx, request.CommunityIds, request.Changes); err != nil {
+				return err
+			}
+		}
+
+		for _, communityId := range request.CommunityIds {
+			user, err := r.User.GetOneByCommunityId(ctx, communityId)
+			if err != nil {
+				return err
+			}
+
+			if err := recordAudit(ctx, r, action, models.TYPE_USER, communityId, before[communityId], auditSnapshot(user)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.UpdateRolesOrUserTypesResponse{
 		Type:         models.TYPE_USER,
 		CommunityIds: request.CommunityIds,
@@ -816,6 +1052,10 @@ func (uu *userUsecase) updateProfileAtomic(ctx context.Context, parameter models
 			return err
 		}
 
+		if err := syncCoolMember(ctx, r, parameter.CommunityId, user.CoolID); err != nil {
+			return err
+		}
+
 		if request.DeleteRelation != nil || request.Relation != nil {
 			relationCommunityIds := make([]string, 0, len(request.Relation))
 			for _, relation := range request.Relation {
@@ -1107,14 +1347,31 @@ func (uu *userUsecase) UpdateUser(ctx context.Context, parameter models.UpdatePr
 		return nil, models.ErrorDataNotFound
 	}
 
+	if !inCampusScope(ctx, data.CampusCode) {
+		return nil, models.ErrorOutOfCampusScope
+	}
+
+	before := auditSnapshot(data)
+
 	if request.CampusCode != "" {
 		_, campusExist := uu.cfg.Campus[strings.ToLower(request.CampusCode)]
 		if !campusExist {
 			return nil, models.ErrorDataNotFound
 		}
+
+		if !inCampusScope(ctx, request.CampusCode) {
+			return nil, models.ErrorOutOfCampusScope
+		}
 		data.CampusCode = request.CampusCode
 	}
 
+	if request.CampusScopes != nil {
+		data.CampusScopes, err = uu.validateCampusScopes(ctx, *request.CampusScopes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if request.DateOfBirth != "" {
 		location, _ := time.LoadLocation("Asia/Jakarta")
 		dob, err := common.ParseStringToDatetime("2006-01-02", request.DateOfBirth, location)
@@ -1185,6 +1442,7 @@ func (uu *userUsecase) UpdateUser(ctx context.Context, parameter models.UpdatePr
 		PhoneNumber:      common.StringTrimSpaceAndLower(*request.PhoneNumber),
 		Roles:            data.Roles,
 		UserTypes:        data.UserTypes,
+		CampusScopes:     data.CampusScopes,
 		Gender:           request.Gender,
 		Address:          *request.Address,
 		CampusCode:       data.CampusCode,
@@ -1203,15 +1461,18 @@ func (uu *userUsecase) UpdateUser(ctx context.Context, parameter models.UpdatePr
 		Status:           data.Status,
 	}
 
-	return uu.updateUserAtomic(ctx, parameter, &updatedUser, &request)
+	return uu.updateUserAtomic(ctx, parameter, before, &updatedUser, &request)
 }
 
-func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.UpdateProfileParameter, user *models.User, request *models.UpdateUserRequest) (response *models.UpdateUserResponse, err error) {
+func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.UpdateProfileParameter, before map[string]interface{}, user *models.User, request *models.UpdateUserRequest) (response *models.UpdateUserResponse, err error) {
 	res := &models.UpdateUserResponse{}
 
 	err = uu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.User.UpdateByCommunityId(ctx, parameter.CommunityId, user); err != nil {
+			return err
+		}
 
-		if err := uu.ur.UpdateByCommunityId(ctx, parameter.CommunityId, user); err != nil {
+		if err := syncCoolMember(ctx, r, parameter.CommunityId, user.CoolID); err != nil {
 			return err
 		}
 
@@ -1228,7 +1489,7 @@ func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.Up
 
 		if request.Relation != nil {
 			for _, relation := range request.Relation {
-				relationExist, err := uu.ur.CheckByCommunityId(ctx, relation.CommunityId)
+				relationExist, err := r.User.CheckByCommunityId(ctx, relation.CommunityId)
 				if err != nil {
 					return err
 				}
@@ -1237,7 +1498,7 @@ func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.Up
 					return models.ErrorDataNotFound
 				}
 
-				existingRelation, err := uu.urr.GetOneByRelatedCommunityIds(ctx, parameter.CommunityId, relation.CommunityId)
+				existingRelation, err := r.UserRelation.GetOneByRelatedCommunityIds(ctx, parameter.CommunityId, relation.CommunityId)
 				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 					return err
 				}
@@ -1245,23 +1506,23 @@ func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.Up
 				switch {
 				case existingRelation.ID > 0:
 					existingRelation.RelationshipType = relation.Type
-					if err := uu.urr.Update(ctx, existingRelation); err != nil {
+					if err := r.UserRelation.Update(ctx, existingRelation); err != nil {
 						return err
 					}
 
-					existingRelationRecriprocal, err := uu.urr.GetOneByRelatedCommunityIds(ctx, relation.CommunityId, parameter.CommunityId)
+					existingRelationRecriprocal, err := r.UserRelation.GetOneByRelatedCommunityIds(ctx, relation.CommunityId, parameter.CommunityId)
 					if err != nil {
 						return err
 					}
 
 					if existingRelationRecriprocal.ID > 0 {
 						existingRelationRecriprocal.RelationshipType = models.ReciprocalRelationshipType(relation.Type)
-						if err := uu.urr.Update(ctx, existingRelationRecriprocal); err != nil {
+						if err := r.UserRelation.Update(ctx, existingRelationRecriprocal); err != nil {
 							return err
 						}
 					}
 				case existingRelation.ID == 0 || errors.Is(err, gorm.ErrRecordNotFound):
-					err := uu.urr.Create(ctx, &models.UserRelation{
+					err := r.UserRelation.Create(ctx, &models.UserRelation{
 						CommunityId:        parameter.CommunityId,
 						RelatedCommunityId: relation.CommunityId,
 						RelationshipType:   relation.Type,
@@ -1271,7 +1532,7 @@ func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.Up
 						return err
 					}
 
-					err = uu.urr.Create(ctx, &models.UserRelation{
+					err = r.UserRelation.Create(ctx, &models.UserRelation{
 						CommunityId:        relation.CommunityId,
 						RelatedCommunityId: parameter.CommunityId,
 						RelationshipType:   models.ReciprocalRelationshipType(relation.Type),
@@ -1289,18 +1550,36 @@ func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.Up
 
 		if request.DeleteRelation != nil {
 			for _, deleteRelation := range request.DeleteRelation {
-				err := uu.urr.Delete(ctx, parameter.CommunityId, deleteRelation)
+				err := r.UserRelation.Delete(ctx, parameter.CommunityId, deleteRelation)
 				if err != nil {
 					return err
 				}
 
-				err = uu.urr.Delete(ctx, deleteRelation, parameter.CommunityId)
+				err = r.UserRelation.Delete(ctx, deleteRelation, parameter.CommunityId)
 				if err != nil {
 					return err
 				}
 			}
 		}
 
+		updated, err := r.User.GetOneByCommunityId(ctx, parameter.CommunityId)
+		if err != nil {
+			return err
+		}
+
+		after := auditSnapshot(updated)
+		if request.Relation != nil {
+			after["Relation"] = request.Relation
+		}
+
+		if request.DeleteRelation != nil {
+			after["DeleteRelation"] = request.DeleteRelation
+		}
+
+		if err := recordAudit(ctx, r, models.AUDIT_ACTION_USER_UPDATE, models.TYPE_USER, parameter.CommunityId, before, after); err != nil {
+			return err
+		}
+
 		res = &models.UpdateUserResponse{
 			Type:             models.TYPE_USER,
 			CommunityId:      parameter.CommunityId,
@@ -1309,6 +1588,7 @@ func (uu *userUsecase) updateUserAtomic(ctx context.Context, parameter models.Up
 			PhoneNumber:      user.PhoneNumber,
 			Roles:            user.Roles,
 			UserTypes:        user.UserTypes,
+			CampusScopes:     user.CampusScopes,
 			Gender:           user.Gender,
 			Address:          user.Address,
 			PlaceOfBirth:     user.PlaceOfBirth,
@@ -1348,7 +1628,18 @@ func (uu *userUsecase) Delete(ctx context.Context, parameter models.DeleteUserPa
 		return nil, models.ErrorDataNotFound
 	}
 
-	if err := uu.ur.Delete(ctx, parameter.CommunityId); err != nil {
+	if !inCampusScope(ctx, data.CampusCode) {
+		return nil, models.ErrorOutOfCampusScope
+	}
+
+	err = uu.r.Transaction.Atomic(ctx, func(ctx context.Context, r *pgsql.PostgreRepositories) error {
+		if err := r.User.Delete(ctx, parameter.CommunityId); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, r, models.AUDIT_ACTION_USER_DELETE, models.TYPE_USER, parameter.CommunityId, auditSnapshot(data), nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -1388,3 +1679,29 @@ func (uu *userUsecase) GetRBAC(ctx context.Context, communityId string) (user *m
 
 	return user, nil
 }
+
+// validateCampusScopes checks the campuses a user is going to administer. A caller limited to some campuses can only
+// hand out a part of them, and cannot leave the user administering every campus.
+func (uu *userUsecase) validateCampusScopes(ctx context.Context, campuses []string) (pq.StringArray, error) {
+	scopes := pq.StringArray{}
+	for _, campus := range campuses {
+		campus = strings.ToUpper(strings.TrimSpace(campus))
+		if _, exists := uu.cfg.Campus[strings.ToLower(campus)]; !exists {
+			return nil, models.ErrorDataNotFound
+		}
+
+		if !inCampusScope(ctx, campus) {
+			return nil, models.ErrorOutOfCampusScope
+		}
+
+		if !slices.Contains(scopes, campus) {
+			scopes = append(scopes, campus)
+		}
+	}
+
+	if len(scopes) == 0 && campusScope(ctx) != nil {
+		return nil, models.ErrorOutOfCampusScope
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_event_registration_records_instance_code_status;
+
+ALTER TABLE "event_instances" DROP COLUMN IF EXISTS "is_waitlist_enabled";
@@ -0,0 +1,6 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE "event_instances" ADD COLUMN "is_waitlist_enabled" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Index to quickly find the waitlisted registrations of an instance in registration order
+CREATE INDEX idx_event_registration_records_instance_code_status ON event_registration_records(instance_code, status, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE "event_registration_records" DROP COLUMN IF EXISTS "checked_out_at";
+ALTER TABLE "event_instances" DROP COLUMN IF EXISTS "checked_out_seats";
@@ -0,0 +1,4 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE "event_instances" ADD COLUMN "checked_out_seats" INT NOT NULL DEFAULT 0;
+ALTER TABLE "event_registration_records" ADD COLUMN "checked_out_at" TIMESTAMPTZ NULL;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "event_recurrence_exceptions";
+
+DROP INDEX IF EXISTS idx_event_instances_event_code_recurrence_at;
+ALTER TABLE "event_instances" DROP COLUMN IF EXISTS "recurrence_at";
+
+ALTER TABLE "events" ALTER COLUMN "recurrence" TYPE VARCHAR(20);
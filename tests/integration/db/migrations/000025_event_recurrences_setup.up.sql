@@ -0,0 +1,23 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Recurrence now holds an RRULE (e.g. FREQ=WEEKLY;BYDAY=SU) instead of a single frequency word
+ALTER TABLE "events" ALTER COLUMN "recurrence" TYPE VARCHAR(255);
+
+-- The occurrence an instance was generated for. Kept after the instance is deleted so it is not generated again
+ALTER TABLE "event_instances" ADD COLUMN "recurrence_at" TIMESTAMPTZ;
+CREATE UNIQUE INDEX idx_event_instances_event_code_recurrence_at ON event_instances(event_code, recurrence_at) WHERE recurrence_at IS NOT NULL;
+
+CREATE TABLE "event_recurrence_exceptions" (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "event_code" varchar(7) NOT NULL,
+    "occurrence_at" TIMESTAMPTZ NOT NULL,
+    "is_skipped" BOOLEAN NOT NULL DEFAULT FALSE,
+    "title" varchar(255),
+    "location_name" varchar(255),
+    "instance_start_at" TIMESTAMPTZ,
+    "instance_end_at" TIMESTAMPTZ,
+    "total_seats" INT,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE ("event_code", "occurrence_at")
+);
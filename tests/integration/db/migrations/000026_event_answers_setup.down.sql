@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "event_answers";
+
+DROP INDEX IF EXISTS idx_event_questions_instance_code;
+ALTER TABLE "event_questions" ALTER COLUMN "instance_code" TYPE VARCHAR(255) USING "instance_code"[1];
+CREATE INDEX idx_event_questions_instance_code ON event_questions(instance_code);
@@ -0,0 +1,20 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- A question can be shared by several instances of the event, so instance_code holds all of them
+ALTER TABLE "event_questions" ALTER COLUMN "instance_code" TYPE TEXT[] USING CASE WHEN "instance_code" IS NULL THEN NULL ELSE ARRAY["instance_code"] END;
+DROP INDEX IF EXISTS idx_event_questions_instance_code;
+CREATE INDEX idx_event_questions_instance_code ON event_questions USING GIN (instance_code);
+
+CREATE TABLE "event_answers" (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "question_id" UUID NOT NULL REFERENCES event_questions(id),
+    "registration_id" UUID NOT NULL REFERENCES event_registration_records(id),
+    "value" TEXT,
+    "values" TEXT[],
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "deleted_at" TIMESTAMP,
+    UNIQUE ("registration_id", "question_id")
+);
+
+CREATE INDEX idx_event_answers_question_id ON event_answers(question_id);
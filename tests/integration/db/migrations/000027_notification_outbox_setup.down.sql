@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notification_outboxes_due;
+DROP TABLE IF EXISTS "notification_outboxes";
@@ -0,0 +1,19 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Messages are written in the same transaction as the change they tell about, then picked up by the notification worker
+CREATE TABLE "notification_outboxes" (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "event" varchar(50) NOT NULL,
+    "channel" varchar(20) NOT NULL,
+    "recipient" varchar(255) NOT NULL,
+    "subject" varchar(255) NOT NULL DEFAULT '',
+    "body" TEXT NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "attempts" INT NOT NULL DEFAULT 0,
+    "last_error" TEXT NOT NULL DEFAULT '',
+    "next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "sent_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_notification_outboxes_due ON notification_outboxes(next_attempt_at) WHERE status = 'pending';
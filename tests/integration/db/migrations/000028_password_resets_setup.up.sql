@@ -0,0 +1,13 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Only the SHA-256 of a reset token is kept, so a leaked row cannot be used to reset the password
+CREATE TABLE "password_reset_tokens" (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "community_id" varchar(15) NOT NULL REFERENCES users(community_id),
+    "token_hash" varchar(64) NOT NULL UNIQUE,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "used_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_password_reset_tokens_community_id ON password_reset_tokens(community_id) WHERE used_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_sessions_community_id;
+DROP INDEX IF EXISTS idx_user_sessions_family_id;
+DROP TABLE IF EXISTS "user_sessions";
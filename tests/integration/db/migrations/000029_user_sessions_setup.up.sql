@@ -0,0 +1,19 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Every refresh token is a row, keyed by its jti. Rotation revokes the row and adds the next one to the same family,
+-- so a refresh token used twice points at a revoked row and the whole family can be revoked.
+CREATE TABLE "user_sessions" (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "family_id" UUID NOT NULL,
+    "community_id" varchar(15) NOT NULL REFERENCES users(community_id),
+    "replaced_by" UUID,
+    "user_agent" TEXT NOT NULL DEFAULT '',
+    "ip_address" varchar(64) NOT NULL DEFAULT '',
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "revoked_at" TIMESTAMPTZ,
+    "revoked_reason" varchar(30) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_user_sessions_family_id ON user_sessions(family_id);
+CREATE INDEX idx_user_sessions_community_id ON user_sessions(community_id) WHERE revoked_at IS NULL;
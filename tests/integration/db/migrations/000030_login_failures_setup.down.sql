@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_login_lockouts_scope_value;
+DROP TABLE IF EXISTS "login_lockouts";
+DROP TABLE IF EXISTS "login_failures";
@@ -0,0 +1,28 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Failed logins counted per identifier and per IP address, reset by a successful login or an unlock
+CREATE TABLE "login_failures" (
+    "scope" varchar(20) NOT NULL,
+    "value" varchar(255) NOT NULL,
+    "failures" INT NOT NULL DEFAULT 0,
+    "last_failed_at" TIMESTAMPTZ NOT NULL,
+    "next_attempt_at" TIMESTAMPTZ NOT NULL,
+    "locked_until" TIMESTAMPTZ,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY ("scope", "value")
+);
+
+-- Every lockout is kept, with who unlocked it early if anyone did
+CREATE TABLE "login_lockouts" (
+    "id" UUID NOT NULL PRIMARY KEY,
+    "scope" varchar(20) NOT NULL,
+    "value" varchar(255) NOT NULL,
+    "failures" INT NOT NULL,
+    "locked_until" TIMESTAMPTZ NOT NULL,
+    "unlocked_at" TIMESTAMPTZ,
+    "unlocked_by" varchar(15),
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_login_lockouts_scope_value ON login_lockouts(scope, value);
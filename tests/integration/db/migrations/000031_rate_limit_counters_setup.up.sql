@@ -0,0 +1,11 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Requests counted per key and fixed window, shared by every instance of the app when rate limits are stored in postgres
+CREATE TABLE "rate_limit_counters" (
+    "key" varchar(255) NOT NULL,
+    "window_start" TIMESTAMPTZ NOT NULL,
+    "count" INT NOT NULL DEFAULT 0,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY ("key", "window_start")
+);
+CREATE INDEX idx_rate_limit_counters_expires_at ON rate_limit_counters(expires_at);
@@ -0,0 +1,19 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Responses of requests sent with an X-Request-ID, replayed when the same caller sends it to the same route again
+CREATE TABLE "idempotency_keys" (
+    "request_id" varchar(255) NOT NULL,
+    "caller" varchar(255) NOT NULL,
+    "route" varchar(255) NOT NULL,
+    "fingerprint" varchar(64) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "response_code" INT NOT NULL DEFAULT 0,
+    "response_body" BYTEA,
+    "content_type" varchar(255) NOT NULL DEFAULT '',
+    "locked_until" TIMESTAMPTZ NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY ("request_id", "caller", "route")
+);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
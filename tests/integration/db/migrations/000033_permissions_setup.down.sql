@@ -0,0 +1,3 @@
+ALTER TABLE "user_types" DROP COLUMN IF EXISTS "permissions";
+ALTER TABLE "roles" DROP COLUMN IF EXISTS "permissions";
+DROP TABLE IF EXISTS "permissions";
@@ -0,0 +1,30 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Every permission the code checks, as resource:action. Self service ones are also granted to the owner of the resource
+CREATE TABLE "permissions" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "permission" varchar(100) UNIQUE NOT NULL,
+    "resource" varchar(50) NOT NULL,
+    "action" varchar(50) NOT NULL,
+    "description" text,
+    "self_service" BOOLEAN NOT NULL DEFAULT false,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "deleted_at" TIMESTAMP
+);
+
+-- Granted permissions, either registered ones, resource:* for every action of a resource or * for everything
+ALTER TABLE "roles" ADD COLUMN "permissions" TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE "user_types" ADD COLUMN "permissions" TEXT[] NOT NULL DEFAULT '{}';
+
+INSERT INTO "permissions" ("permission", "resource", "action", "description", "self_service") VALUES
+    ('internal:access', 'internal', 'access', 'Use the internal endpoints', false),
+    ('event-registration:verify', 'event-registration', 'verify', 'Scan, verify and check out registrations', false),
+    ('user-session:manage', 'user-session', 'manage', 'See and remove the device sessions of a user', true),
+    ('permission:manage', 'permission', 'manage', 'Grant permissions to roles and user types', false);
+
+-- Grant what used to be hardcoded
+UPDATE "roles" SET "permissions" = ARRAY['internal:access'] WHERE "role" IN ('event-internal-view', 'event-internal-edit');
+UPDATE "roles" SET "permissions" = ARRAY['event-registration:verify'] WHERE "role" = 'event-verify-record';
+UPDATE "user_types" SET "permissions" = ARRAY['event-registration:verify'] WHERE "type" IN ('admin', 'usher', 'volunteer');
+UPDATE "user_types" SET "permissions" = ARRAY['*'] WHERE "type" = 'superadmin';
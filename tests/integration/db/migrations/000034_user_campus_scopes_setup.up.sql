@@ -0,0 +1,4 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Campuses a user administers with their roles. Empty or NULL administers every campus
+ALTER TABLE "users" ADD COLUMN "campus_scopes" TEXT[];
@@ -0,0 +1,9 @@
+DELETE FROM "permissions" WHERE "permission" = 'audit:view';
+UPDATE "roles" SET "permissions" = array_remove("permissions", 'audit:view');
+UPDATE "user_types" SET "permissions" = array_remove("permissions", 'audit:view');
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON "audit_events";
+DROP FUNCTION IF EXISTS audit_events_append_only();
+DROP INDEX IF EXISTS idx_audit_events_actor_community_id;
+DROP INDEX IF EXISTS idx_audit_events_entity;
+DROP INDEX IF EXISTS idx_audit_events_created_at_id;
+DROP TABLE IF EXISTS "audit_events";
@@ -0,0 +1,31 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Who changed what through the administrative endpoints, written in the same transaction as the change
+CREATE TABLE "audit_events" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "actor_community_id" varchar(15) NOT NULL DEFAULT '',
+    "action" varchar(100) NOT NULL,
+    "entity_type" varchar(50) NOT NULL,
+    "entity_id" varchar(255) NOT NULL,
+    "before" JSONB,
+    "after" JSONB,
+    "request_id" varchar(255) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_audit_events_created_at_id ON audit_events(created_at DESC, id DESC);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX idx_audit_events_actor_community_id ON audit_events(actor_community_id);
+
+-- The log is append only, rows can never be changed or removed afterwards
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON "audit_events"
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO "permissions" ("permission", "resource", "action", "description", "self_service") VALUES
+    ('audit:view', 'audit', 'view', 'See the audit log of administrative changes', false);
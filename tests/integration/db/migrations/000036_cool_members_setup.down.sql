@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cool_members_cool_id;
+DROP INDEX IF EXISTS uq_cool_members_open_community_id;
+DROP TABLE IF EXISTS "cool_members";
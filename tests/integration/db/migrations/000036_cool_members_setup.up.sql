@@ -0,0 +1,31 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Who is or was in which cool. users.cool_id mirrors the open membership of each user
+CREATE TABLE "cool_members" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "cool_id" BIGINT NOT NULL REFERENCES "cools" ("id"),
+    "community_id" varchar(15) NOT NULL,
+    "role" varchar(20) NOT NULL DEFAULT 'member',
+    "joined_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "left_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+-- A user is in one cool at a time
+CREATE UNIQUE INDEX uq_cool_members_open_community_id ON cool_members(community_id) WHERE left_at IS NULL;
+CREATE INDEX idx_cool_members_cool_id ON cool_members(cool_id, left_at);
+
+-- Start the history with the current members
+INSERT INTO "cool_members" ("cool_id", "community_id", "role", "joined_at")
+SELECT
+    c.id,
+    u.community_id,
+    CASE
+        WHEN u.community_id = ANY(c.leader_community_ids) THEN 'leader'
+        WHEN u.community_id = ANY(COALESCE(c.core_community_ids, '{}')) THEN 'core'
+        ELSE 'member'
+    END,
+    COALESCE(u.updated_at, now())
+FROM users u
+JOIN cools c ON c.id = u.cool_id
+WHERE u.deleted_at IS NULL AND c.deleted_at IS NULL;
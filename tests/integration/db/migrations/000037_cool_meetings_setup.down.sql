@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "cool_meeting_attendances";
+DROP INDEX IF EXISTS idx_cool_meetings_cool_id_start_at;
+DROP INDEX IF EXISTS uq_cool_meetings_recurrence_at;
+DROP TABLE IF EXISTS "cool_meetings";
+ALTER TABLE "cools" DROP COLUMN IF EXISTS "meeting_duration_minutes";
+ALTER TABLE "cools" DROP COLUMN IF EXISTS "meeting_start_at";
+ALTER TABLE "cools" DROP COLUMN IF EXISTS "meeting_rule";
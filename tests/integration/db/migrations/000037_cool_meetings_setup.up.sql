@@ -0,0 +1,37 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- When a cool meets, as a recurrence rule repeated from the first meeting
+ALTER TABLE "cools" ADD COLUMN "meeting_rule" TEXT;
+ALTER TABLE "cools" ADD COLUMN "meeting_start_at" TIMESTAMPTZ;
+ALTER TABLE "cools" ADD COLUMN "meeting_duration_minutes" INT NOT NULL DEFAULT 120;
+
+-- Meetings that happened or are planned, generated from the rule of the cool or created ad hoc by its leaders
+CREATE TABLE "cool_meetings" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "cool_id" BIGINT NOT NULL REFERENCES "cools" ("id"),
+    "title" varchar(255) NOT NULL DEFAULT '',
+    "start_at" TIMESTAMPTZ NOT NULL,
+    "end_at" TIMESTAMPTZ NOT NULL,
+    "location_type" varchar(6),
+    "location_name" varchar(255),
+    "recurrence_at" TIMESTAMPTZ,
+    "status" varchar(10) NOT NULL DEFAULT 'active',
+    "created_by" varchar(15) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+-- An occurrence of the rule is generated once, even after it was cancelled
+CREATE UNIQUE INDEX uq_cool_meetings_recurrence_at ON cool_meetings(cool_id, recurrence_at) WHERE recurrence_at IS NOT NULL;
+CREATE INDEX idx_cool_meetings_cool_id_start_at ON cool_meetings(cool_id, start_at);
+
+CREATE TABLE "cool_meeting_attendances" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "meeting_id" BIGINT NOT NULL REFERENCES "cool_meetings" ("id"),
+    "community_id" varchar(15) NOT NULL,
+    "status" varchar(10) NOT NULL,
+    "reason" TEXT,
+    "marked_by" varchar(15) NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE ("meeting_id", "community_id")
+);
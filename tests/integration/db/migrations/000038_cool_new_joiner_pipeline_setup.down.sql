@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "cool_new_joiner_notes";
+DROP INDEX IF EXISTS idx_cool_new_joiners_follow_up;
+DROP INDEX IF EXISTS idx_cool_new_joiners_pending;
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "converted_at";
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "community_id";
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "sla_reminded_at";
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "followed_up_at";
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "assigned_at";
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "follow_up_community_id";
+ALTER TABLE "cool_new_joiners" DROP COLUMN IF EXISTS "cool_id";
@@ -0,0 +1,26 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- The cool a new joiner is assigned to and who follows them up, until they join as a user
+ALTER TABLE "cool_new_joiners" ADD COLUMN "cool_id" BIGINT REFERENCES "cools" ("id");
+ALTER TABLE "cool_new_joiners" ADD COLUMN "follow_up_community_id" varchar(15);
+ALTER TABLE "cool_new_joiners" ADD COLUMN "assigned_at" TIMESTAMP;
+ALTER TABLE "cool_new_joiners" ADD COLUMN "followed_up_at" TIMESTAMP;
+ALTER TABLE "cool_new_joiners" ADD COLUMN "sla_reminded_at" TIMESTAMP;
+ALTER TABLE "cool_new_joiners" ADD COLUMN "community_id" varchar(15);
+ALTER TABLE "cool_new_joiners" ADD COLUMN "converted_at" TIMESTAMP;
+
+-- Joiners that already left pending were followed up when they were last updated
+UPDATE "cool_new_joiners" SET "followed_up_at" = "updated_at" WHERE "status" <> 'pending';
+
+CREATE INDEX idx_cool_new_joiners_pending ON cool_new_joiners(created_at) WHERE status = 'pending' AND deleted_at IS NULL;
+CREATE INDEX idx_cool_new_joiners_follow_up ON cool_new_joiners(follow_up_community_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE "cool_new_joiner_notes" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "new_joiner_id" BIGINT NOT NULL REFERENCES "cool_new_joiners" ("id"),
+    "note" TEXT NOT NULL,
+    "status" varchar(30) NOT NULL,
+    "created_by" varchar(15) NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_cool_new_joiner_notes_new_joiner_id ON cool_new_joiner_notes(new_joiner_id, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_cools_discovery;
+ALTER TABLE "cools" DROP COLUMN IF EXISTS "capacity";
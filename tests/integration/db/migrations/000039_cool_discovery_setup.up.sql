@@ -0,0 +1,6 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- How many members a cool takes before it is no longer open for new joiners, without a limit when NULL
+ALTER TABLE "cools" ADD COLUMN "capacity" INT;
+
+CREATE INDEX idx_cools_discovery ON cools(campus_code, category) WHERE status = 'active' AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE "permission" = 'user:manage';
+UPDATE "roles" SET "permissions" = array_remove("permissions", 'user:manage');
+UPDATE "user_types" SET "permissions" = array_remove("permissions", 'user:manage');
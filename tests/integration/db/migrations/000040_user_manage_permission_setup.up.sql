@@ -0,0 +1,6 @@
+SET TIME ZONE 'Asia/Jakarta';
+
+-- Changing the roles and user types of users and unlocking logins is left to superadmin, who is granted everything
+-- with '*'. Anyone else is granted it on purpose through PUT /v2/internal/permissions/roles/{role} or /user-types/{userType}.
+INSERT INTO "permissions" ("permission", "resource", "action", "description", "self_service") VALUES
+    ('user:manage', 'user', 'manage', 'Change the roles and user types of users and unlock their logins', false);